@@ -1,6 +1,9 @@
 package corp
 
-import "strconv"
+import (
+	"context"
+	"strconv"
+)
 
 type AgentUserInfo struct {
 	UserId string `json:"userid"`
@@ -33,14 +36,18 @@ type AgentParameters struct {
 
 // 获取企业号应用
 func (clt *Client) GetAgent(agentId int64) (agent AgentParameters, err error) {
+	return clt.GetAgentContext(context.Background(), agentId)
+}
+
+func (clt *Client) GetAgentContext(ctx context.Context, agentId int64) (agent AgentParameters, err error) {
 	var result struct {
 		Error
 		AgentParameters
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/agent/get?agentid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/agent/get?agentid=" +
 		strconv.FormatInt(agentId, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -65,12 +72,16 @@ type UpdateAgentParameters struct {
 
 // 设置企业号应用
 func (clt *Client) SetAgent(data UpdateAgentParameters) (err error) {
+	return clt.SetAgentContext(context.Background(), data)
+}
+
+func (clt *Client) SetAgentContext(ctx context.Context, data UpdateAgentParameters) (err error) {
 	var result struct {
 		Error
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/agent/set?access_token="
-	if err = clt.PostJSON(incompleteURL, data, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/agent/set?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, data, &result); err != nil {
 		return
 	}
 
@@ -93,13 +104,17 @@ type AgentListParameters struct {
 
 // 获取应用概况列表说明
 func (clt *Client) GetAgentList() (agent AgentListParameters, err error) {
+	return clt.GetAgentListContext(context.Background())
+}
+
+func (clt *Client) GetAgentListContext(ctx context.Context) (agent AgentListParameters, err error) {
 	var result struct {
 		Error
 		AgentListParameters
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/agent/list?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/agent/list?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -1,6 +1,9 @@
 package corp
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 type Chat struct {
 	ChatId  string   `json:"chatid"`
@@ -11,10 +14,14 @@ type Chat struct {
 
 // 创建会话
 func (clt *Client) CreateChat(chat Chat) (err error) {
+	return clt.CreateChatContext(context.Background(), chat)
+}
+
+func (clt *Client) CreateChatContext(ctx context.Context, chat Chat) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/chat/create?access_token="
-	if err = clt.PostJSON(incompleteURL, chat, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/chat/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, chat, &result); err != nil {
 		return
 	}
 
@@ -27,14 +34,18 @@ func (clt *Client) CreateChat(chat Chat) (err error) {
 
 // 获取会话
 func (clt *Client) GetChat(id string) (chat Chat, err error) {
+	return clt.GetChatContext(context.Background(), id)
+}
+
+func (clt *Client) GetChatContext(ctx context.Context, id string) (chat Chat, err error) {
 	var result struct {
 		Error
 		Chat Chat `json:"chat_info"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/chat/get?chatid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/chat/get?chatid=" +
 		id + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -48,6 +59,10 @@ func (clt *Client) GetChat(id string) (chat Chat, err error) {
 
 // 退出会话
 func (clt *Client) QuitChat(chatid string, userId string) (err error) {
+	return clt.QuitChatContext(context.Background(), chatid, userId)
+}
+
+func (clt *Client) QuitChatContext(ctx context.Context, chatid string, userId string) (err error) {
 	var result Error
 
 	data := map[string]interface{}{
@@ -55,8 +70,8 @@ func (clt *Client) QuitChat(chatid string, userId string) (err error) {
 		"op_user": userId,
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/chat/quit?access_token="
-	if err = clt.PostJSON(incompleteURL, data, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/chat/quit?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, data, &result); err != nil {
 		return
 	}
 
@@ -81,6 +96,10 @@ func (c *ChatRecevier) CheckValid() error {
 
 // 清楚未读会话状态
 func (clt *Client) CleanChatNotify(userId string, receiver ChatRecevier) (err error) {
+	return clt.CleanChatNotifyContext(context.Background(), userId, receiver)
+}
+
+func (clt *Client) CleanChatNotifyContext(ctx context.Context, userId string, receiver ChatRecevier) (err error) {
 	var result Error
 
 	data := map[string]interface{}{
@@ -88,8 +107,8 @@ func (clt *Client) CleanChatNotify(userId string, receiver ChatRecevier) (err er
 		"chat":    receiver,
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/chat/quit?access_token="
-	if err = clt.PostJSON(incompleteURL, data, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/chat/quit?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, data, &result); err != nil {
 		return
 	}
 
@@ -104,6 +123,10 @@ func (clt *Client) CleanChatNotify(userId string, receiver ChatRecevier) (err er
 // isMute = true: 免打扰
 // isMute = false: 解封免打扰
 func (clt *Client) SetChatMute(userIds []string, isMute bool) (invalidusers []string, err error) {
+	return clt.SetChatMuteContext(context.Background(), userIds, isMute)
+}
+
+func (clt *Client) SetChatMuteContext(ctx context.Context, userIds []string, isMute bool) (invalidusers []string, err error) {
 	var result struct {
 		Error
 		InvalidUser []string `json:"invaliduser"`
@@ -127,8 +150,8 @@ func (clt *Client) SetChatMute(userIds []string, isMute bool) (invalidusers []st
 		"user_mute_list": list,
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/chat/setmute?access_token="
-	if err = clt.PostJSON(incompleteURL, data, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/chat/setmute?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, data, &result); err != nil {
 		return
 	}
 
@@ -169,28 +192,40 @@ type ChatFile struct {
 }
 
 func (clt *Client) SendChatText(msg *ChatText) (err error) {
+	return clt.SendChatTextContext(context.Background(), msg)
+}
+
+func (clt *Client) SendChatTextContext(ctx context.Context, msg *ChatText) (err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.SendChat(msg)
+	return clt.SendChatContext(ctx, msg)
 }
 
 func (clt *Client) SendChatImage(msg *ChatImage) (err error) {
+	return clt.SendChatImageContext(context.Background(), msg)
+}
+
+func (clt *Client) SendChatImageContext(ctx context.Context, msg *ChatImage) (err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.SendChat(msg)
+	return clt.SendChatContext(ctx, msg)
 }
 
 func (clt *Client) SendChat(msg interface{}) (err error) {
+	return clt.SendChatContext(context.Background(), msg)
+}
+
+func (clt *Client) SendChatContext(ctx context.Context, msg interface{}) (err error) {
 	var result struct {
 		Error
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/chat/send?access_token="
-	if err = clt.PostJSON(incompleteURL, msg, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/chat/send?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
 		return
 	}
 
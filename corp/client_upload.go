@@ -9,6 +9,7 @@ package corp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -72,23 +73,29 @@ func escapeQuotes(s string) string {
 //     ErrCode int `json:"errcode"` (可以是直接属性, 也可以是匿名属性里的属性)
 func (clt *Client) UploadFromReader(incompleteURL, filename string,
 	reader io.Reader, response interface{}) (err error) {
+	return clt.UploadFromReaderContext(context.Background(), incompleteURL, filename, reader, response)
+}
+
+// 同 UploadFromReader, ctx 用于控制请求的取消和超时.
+func (clt *Client) UploadFromReaderContext(ctx context.Context, incompleteURL, filename string,
+	reader io.Reader, response interface{}) (err error) {
 
 	filename = escapeQuotes(filename)
 	switch v := reader.(type) {
 	case *os.File:
-		return clt.uploadFromOSFile(incompleteURL, filename, v, response)
+		return clt.uploadFromOSFile(ctx, incompleteURL, filename, v, response)
 	case *bytes.Buffer:
-		return clt.uploadFromBytesBuffer(incompleteURL, filename, v, response)
+		return clt.uploadFromBytesBuffer(ctx, incompleteURL, filename, v, response)
 	case *bytes.Reader:
-		return clt.uploadFromBytesReader(incompleteURL, filename, v, response)
+		return clt.uploadFromBytesReader(ctx, incompleteURL, filename, v, response)
 	case *strings.Reader:
-		return clt.uploadFromStringsReader(incompleteURL, filename, v, response)
+		return clt.uploadFromStringsReader(ctx, incompleteURL, filename, v, response)
 	default:
-		return clt.uploadFromIOReader(incompleteURL, filename, v, response)
+		return clt.uploadFromIOReader(ctx, incompleteURL, filename, v, response)
 	}
 }
 
-func (clt *Client) uploadFromOSFile(incompleteURL, filename string,
+func (clt *Client) uploadFromOSFile(ctx context.Context, incompleteURL, filename string,
 	file *os.File, response interface{}) (err error) {
 
 	fi, err := file.Stat()
@@ -97,7 +104,7 @@ func (clt *Client) uploadFromOSFile(incompleteURL, filename string,
 	}
 
 	if !fi.Mode().IsRegular() {
-		return clt.uploadFromIOReader(incompleteURL, filename, file, response)
+		return clt.uploadFromIOReader(ctx, incompleteURL, filename, file, response)
 	}
 
 	originalOffset, err := file.Seek(0, 1)
@@ -106,14 +113,14 @@ func (clt *Client) uploadFromOSFile(incompleteURL, filename string,
 	}
 	ContentLength := int64(multipartConstPartLen+len(filename)) + fi.Size() - originalOffset
 
-	token, err := clt.Token()
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 
 	if hasRetried {
 		if _, err = file.Seek(originalOffset, 0); err != nil {
@@ -128,14 +135,14 @@ RETRY:
 		strings.NewReader(multipartFormDataEnd),
 	)
 
-	httpReq, err := http.NewRequest("POST", finalURL, mr)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", finalURL, mr)
 	if err != nil {
 		return
 	}
 	httpReq.Header.Set("Content-Type", multipartContentType)
 	httpReq.ContentLength = ContentLength
 
-	httpResp, err := clt.httpClient.Do(httpReq)
+	httpResp, err := clt.do(httpReq)
 	if err != nil {
 		return
 	}
@@ -170,7 +177,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -181,20 +188,20 @@ RETRY:
 	}
 }
 
-func (clt *Client) uploadFromBytesBuffer(incompleteURL, filename string,
+func (clt *Client) uploadFromBytesBuffer(ctx context.Context, incompleteURL, filename string,
 	buffer *bytes.Buffer, response interface{}) (err error) {
 
 	fileBytes := buffer.Bytes()
 	ContentLength := int64(multipartConstPartLen + len(filename) + len(fileBytes))
 
-	token, err := clt.Token()
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 
 	mr := io.MultiReader(
 		strings.NewReader(multipartFormDataFront),
@@ -204,14 +211,14 @@ RETRY:
 		strings.NewReader(multipartFormDataEnd),
 	)
 
-	httpReq, err := http.NewRequest("POST", finalURL, mr)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", finalURL, mr)
 	if err != nil {
 		return
 	}
 	httpReq.Header.Set("Content-Type", multipartContentType)
 	httpReq.ContentLength = ContentLength
 
-	httpResp, err := clt.httpClient.Do(httpReq)
+	httpResp, err := clt.do(httpReq)
 	if err != nil {
 		return
 	}
@@ -246,7 +253,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -257,7 +264,7 @@ RETRY:
 	}
 }
 
-func (clt *Client) uploadFromBytesReader(incompleteURL, filename string,
+func (clt *Client) uploadFromBytesReader(ctx context.Context, incompleteURL, filename string,
 	reader *bytes.Reader, response interface{}) (err error) {
 
 	originalOffset, err := reader.Seek(0, 1)
@@ -266,15 +273,14 @@ func (clt *Client) uploadFromBytesReader(incompleteURL, filename string,
 	}
 	ContentLength := int64(multipartConstPartLen + len(filename) + reader.Len())
 
-	token, err := clt.Token()
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
-	fmt.Println("final url:", finalURL)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 	if hasRetried {
 		if _, err = reader.Seek(originalOffset, 0); err != nil {
 			return
@@ -288,14 +294,14 @@ RETRY:
 		strings.NewReader(multipartFormDataEnd),
 	)
 
-	httpReq, err := http.NewRequest("POST", finalURL, mr)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", finalURL, mr)
 	if err != nil {
 		return
 	}
 	httpReq.Header.Set("Content-Type", multipartContentType)
 	httpReq.ContentLength = ContentLength
 
-	httpResp, err := clt.httpClient.Do(httpReq)
+	httpResp, err := clt.do(httpReq)
 	if err != nil {
 		return
 	}
@@ -330,7 +336,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -341,7 +347,7 @@ RETRY:
 	}
 }
 
-func (clt *Client) uploadFromStringsReader(incompleteURL, filename string,
+func (clt *Client) uploadFromStringsReader(ctx context.Context, incompleteURL, filename string,
 	reader *strings.Reader, response interface{}) (err error) {
 
 	originalOffset, err := reader.Seek(0, 1)
@@ -350,14 +356,14 @@ func (clt *Client) uploadFromStringsReader(incompleteURL, filename string,
 	}
 	ContentLength := int64(multipartConstPartLen + len(filename) + reader.Len())
 
-	token, err := clt.Token()
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 
 	if hasRetried {
 		if _, err = reader.Seek(originalOffset, 0); err != nil {
@@ -372,14 +378,14 @@ RETRY:
 		strings.NewReader(multipartFormDataEnd),
 	)
 
-	httpReq, err := http.NewRequest("POST", finalURL, mr)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", finalURL, mr)
 	if err != nil {
 		return
 	}
 	httpReq.Header.Set("Content-Type", multipartContentType)
 	httpReq.ContentLength = ContentLength
 
-	httpResp, err := clt.httpClient.Do(httpReq)
+	httpResp, err := clt.do(httpReq)
 	if err != nil {
 		return
 	}
@@ -414,7 +420,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -425,7 +431,7 @@ RETRY:
 	}
 }
 
-func (clt *Client) uploadFromIOReader(incompleteURL, filename string,
+func (clt *Client) uploadFromIOReader(ctx context.Context, incompleteURL, filename string,
 	reader io.Reader, response interface{}) (err error) {
 
 	bodyBuf := mediaBufferPool.Get().(*bytes.Buffer)
@@ -442,16 +448,16 @@ func (clt *Client) uploadFromIOReader(incompleteURL, filename string,
 
 	bodyBytes := bodyBuf.Bytes()
 
-	token, err := clt.Token()
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 
-	httpResp, err := clt.httpClient.Post(finalURL, multipartContentType, bytes.NewReader(bodyBytes))
+	httpResp, err := clt.httpPost(ctx, finalURL, multipartContentType, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return
 	}
@@ -486,7 +492,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
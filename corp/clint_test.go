@@ -0,0 +1,117 @@
+package corp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenConcurrentRefresh(t *testing.T) {
+	var tokenCalls, ticketCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cgi-bin/gettoken":
+			n := atomic.AddInt32(&tokenCalls, 1)
+			time.Sleep(50 * time.Millisecond) // 让并发的调用者都赶上这次刷新
+			fmt.Fprintf(w, `{"access_token":"TOKEN%d","expires_in":7200}`, n)
+		case "/cgi-bin/get_jsapi_ticket":
+			n := atomic.AddInt32(&ticketCalls, 1)
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintf(w, `{"errcode":0,"errmsg":"ok","ticket":"TICKET%d","expires_in":7200}`, n)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	clt := NewClient("corpid", "secret")
+	clt.SetBaseURL(server.URL)
+
+	const n = 64
+	var wg sync.WaitGroup
+	tokens := make([]string, n)
+	tickets := make([]string, n)
+	errs := make([]error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = clt.Token()
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			tickets[i], errs[n+i] = clt.Ticket()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tokenCalls != 1 {
+		t.Errorf("access_token 应该只刷新 1 次, 实际刷新了 %d 次", tokenCalls)
+	}
+	if ticketCalls != 1 {
+		t.Errorf("jsapi_ticket 应该只刷新 1 次, 实际刷新了 %d 次", ticketCalls)
+	}
+	for i := 0; i < n; i++ {
+		if tokens[i] != "TOKEN1" {
+			t.Errorf("获取了错误的 access_token: %s", tokens[i])
+		}
+		if tickets[i] != "TICKET1" {
+			t.Errorf("获取了错误的 jsapi_ticket: %s", tickets[i])
+		}
+	}
+}
+
+func TestRefreshTokenAfterExpired(t *testing.T) {
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cgi-bin/gettoken":
+			n := atomic.AddInt32(&tokenCalls, 1)
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprintf(w, `{"access_token":"TOKEN%d","expires_in":7200}`, n)
+		case "/cgi-bin/menu/get":
+			if r.URL.Query().Get("access_token") == "EXPIRED" {
+				fmt.Fprint(w, `{"errcode":42001,"errmsg":"access_token expired"}`)
+				return
+			}
+			fmt.Fprint(w, `{"errcode":0,"errmsg":"ok"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	clt := NewClient("corpid", "secret")
+	clt.SetBaseURL(server.URL)
+	clt.SetToken(TokenInfo{Token: "EXPIRED", ExpiresIn: time.Now().Unix() + 3600})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result Error
+			if err := clt.GetJSON(clt.apiBaseURL+"/cgi-bin/menu/get?access_token=", &result); err != nil {
+				t.Error(err)
+				return
+			}
+			if result.ErrCode != ErrCodeOK {
+				t.Error(&result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if tokenCalls != 1 {
+		t.Errorf("access_token 应该只刷新 1 次, 实际刷新了 %d 次", tokenCalls)
+	}
+}
@@ -0,0 +1,56 @@
+package corp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	started, release := make(chan struct{}), make(chan error)
+	calls := 0
+	handler := Dedupe(nil, 0)(HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			close(started)
+			return nil, <-release
+		}
+		return "reply", nil
+	}))
+
+	msg := &MixedMessage{}
+	msg.ToUserName = testCorpId
+	msg.FromUserName = "userid"
+	msg.MsgId = 1234567890123456
+	msg.AgentId = 1
+	serve := func() (interface{}, error) {
+		return handler.ServeMessage(context.Background(), &Request{MixedMessage: msg})
+	}
+
+	// 第一次推送还在处理时, 重试的推送不能回复成功
+	done := make(chan error)
+	go func() {
+		_, err := serve()
+		done <- err
+	}()
+	<-started
+	if _, err := serve(); !errors.Is(err, ErrDedupeInProgress) {
+		t.Errorf("err = %v, want ErrDedupeInProgress", err)
+	}
+
+	// 第一次处理失败后, 之后的重试再次调用 next
+	release <- errors.New("temporary failure")
+	if err := <-done; err == nil {
+		t.Fatal("expected error")
+	}
+	if reply, err := serve(); err != nil || reply != "reply" {
+		t.Fatalf("reply = %v, err = %v", reply, err)
+	}
+	// 处理成功后的重复推送直接回复
+	if reply, err := serve(); err != nil || reply != nil {
+		t.Errorf("reply = %v, err = %v", reply, err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
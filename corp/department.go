@@ -6,6 +6,7 @@
 package corp
 
 import (
+	"context"
 	"errors"
 	"strconv"
 )
@@ -27,6 +28,10 @@ func (para *DepartmentCreateParameters) SetId(id int64) {
 
 // 创建部门
 func (clt *Client) DepartmentCreate(para *DepartmentCreateParameters) (id int64, err error) {
+	return clt.DepartmentCreateContext(context.Background(), para)
+}
+
+func (clt *Client) DepartmentCreateContext(ctx context.Context, para *DepartmentCreateParameters) (id int64, err error) {
 	if para == nil {
 		err = errors.New("nil parameters")
 		return
@@ -37,8 +42,8 @@ func (clt *Client) DepartmentCreate(para *DepartmentCreateParameters) (id int64,
 		Id int64 `json:"id"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/department/create?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/department/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -67,6 +72,10 @@ func (para *DepartmentUpdateParameters) SetOrder(order int64) {
 
 // 更新部门
 func (clt *Client) DepartmentUpdate(para *DepartmentUpdateParameters) (err error) {
+	return clt.DepartmentUpdateContext(context.Background(), para)
+}
+
+func (clt *Client) DepartmentUpdateContext(ctx context.Context, para *DepartmentUpdateParameters) (err error) {
 	if para == nil {
 		err = errors.New("nil parameters")
 		return
@@ -74,8 +83,8 @@ func (clt *Client) DepartmentUpdate(para *DepartmentUpdateParameters) (err error
 
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/department/update?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/department/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -88,11 +97,15 @@ func (clt *Client) DepartmentUpdate(para *DepartmentUpdateParameters) (err error
 
 // 删除部门
 func (clt *Client) DepartmentDelete(id int64) (err error) {
+	return clt.DepartmentDeleteContext(context.Background(), id)
+}
+
+func (clt *Client) DepartmentDeleteContext(ctx context.Context, id int64) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/department/delete?id=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/department/delete?id=" +
 		strconv.FormatInt(id, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -111,14 +124,18 @@ type Department struct {
 
 // 获取 rootId 部门的子部门
 func (clt *Client) DepartmentList(rootId int64) (departments []Department, err error) {
+	return clt.DepartmentListContext(context.Background(), rootId)
+}
+
+func (clt *Client) DepartmentListContext(ctx context.Context, rootId int64) (departments []Department, err error) {
 	var result struct {
 		Error
 		Departments []Department `json:"department"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/department/list?id=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/department/list?id=" +
 		strconv.FormatInt(rootId, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -0,0 +1,598 @@
+// Code generated by errcodegen from errcode.txt; DO NOT EDIT.
+
+package corp
+
+const (
+	ErrCodeSystemBusy                  = -1    // 系统繁忙
+	ErrCodeInvalidCredential           = 40001 // 获取 access_token 时 Secret 错误，或者 access_token 无效
+	ErrCodeInvalidGrantType            = 40002 // 不合法的凭证类型
+	ErrCodeInvalidUserId               = 40003 // 不合法的 UserID
+	ErrCodeInvalidMediaType            = 40004 // 不合法的媒体文件类型
+	ErrCodeInvalidFileType             = 40005 // 不合法的文件类型
+	ErrCodeInvalidFileSize             = 40006 // 不合法的文件大小
+	ErrCodeInvalidMediaId              = 40007 // 不合法的媒体文件 id
+	ErrCodeInvalidMessageType          = 40008 // 不合法的消息类型
+	ErrCodeInvalidCorpId               = 40013 // 不合法的 corpid
+	ErrCodeInvalidAccessToken          = 40014 // 不合法的 access_token
+	ErrCodeInvalidMenuType             = 40015 // 不合法的菜单类型
+	ErrCodeInvalidButtonCount          = 40016 // 不合法的按钮个数
+	ErrCodeInvalidButtonType           = 40017 // 不合法的按钮类型
+	ErrCodeInvalidButtonNameSize       = 40018 // 不合法的按钮名字长度
+	ErrCodeInvalidButtonKeySize        = 40019 // 不合法的按钮 KEY 长度
+	ErrCodeInvalidButtonURLSize        = 40020 // 不合法的按钮 URL 长度
+	ErrCodeInvalidSubMenuLevel         = 40022 // 不合法的子菜单级数
+	ErrCodeInvalidSubButtonCount       = 40023 // 不合法的子菜单按钮个数
+	ErrCodeInvalidSubButtonType        = 40024 // 不合法的子菜单按钮类型
+	ErrCodeInvalidSubButtonNameSize    = 40025 // 不合法的子菜单按钮名字长度
+	ErrCodeInvalidSubButtonKeySize     = 40026 // 不合法的子菜单按钮 KEY 长度
+	ErrCodeInvalidSubButtonURLSize     = 40027 // 不合法的子菜单按钮 URL 长度
+	ErrCodeInvalidOAuthCode            = 40029 // 不合法的 oauth_code
+	ErrCodeInvalidUserList             = 40031 // 不合法的 UserID 列表
+	ErrCodeInvalidUserListSize         = 40032 // 不合法的 UserID 列表长度
+	ErrCodeInvalidCharset              = 40033 // 不合法的请求字符，不能包含 \uxxxx 格式的字符
+	ErrCodeInvalidParameter            = 40035 // 不合法的参数
+	ErrCodeInvalidRequestFormat        = 40038 // 不合法的请求格式
+	ErrCodeInvalidURLSize              = 40039 // 不合法的 URL 长度
+	ErrCodeInvalidSubMenuURLDomain     = 40054 // 不合法的子菜单 url 域名
+	ErrCodeInvalidMenuURLDomain        = 40055 // 不合法的菜单 url 域名
+	ErrCodeInvalidAgentId              = 40056 // 不合法的 agentid
+	ErrCodeInvalidCallbackURL          = 40057 // 不合法的 callbackurl
+	ErrCodeInvalidRedirectURL          = 40058 // 不合法的红包参数
+	ErrCodeInvalidReportLocationFlag   = 40059 // 不合法的上报地理位置标志位
+	ErrCodeInvalidDepartmentId         = 40060 // 删除部门时不能删除有子部门或成员的部门
+	ErrCodeInvalidAgentLogo            = 40061 // 设置应用头像失败
+	ErrCodeInvalidIsReportEnter        = 40062 // 不合法的应用模式
+	ErrCodeEmptyParameter              = 40063 // 参数为空
+	ErrCodeDuplicateAgentName          = 40064 // 管理组名字已存在
+	ErrCodeInvalidGroupNameSize        = 40065 // 不合法的管理组名字长度
+	ErrCodeInvalidDepartmentList       = 40066 // 不合法的部门列表
+	ErrCodeInvalidTitleSize            = 40067 // 标题长度不合法
+	ErrCodeInvalidTagId                = 40068 // 不合法的标签 ID
+	ErrCodeInvalidTagIdList            = 40069 // 不合法的标签 ID 列表
+	ErrCodeInvalidTagUserList          = 40070 // 列表中所有标签（用户）ID 都不合法
+	ErrCodeInvalidTagName              = 40071 // 不合法的标签名字，标签名字已经存在
+	ErrCodeInvalidTagNameSize          = 40072 // 不合法的标签名字长度
+	ErrCodeInvalidOpenId               = 40073 // 不合法的 openid
+	ErrCodeNewsMessageNotSupported     = 40074 // news 消息不支持指定为高保密消息
+	ErrCodeAccessTokenMissing          = 41001 // 缺少 access_token 参数
+	ErrCodeCorpIdMissing               = 41002 // 缺少 corpid 参数
+	ErrCodeSecretMissing               = 41004 // 缺少 secret 参数
+	ErrCodeMediaDataMissing            = 41005 // 缺少多媒体文件数据
+	ErrCodeMediaIdMissing              = 41006 // 缺少 media_id 参数
+	ErrCodeSubMenuDataMissing          = 41007 // 缺少子菜单数据
+	ErrCodeOAuthCodeMissing            = 41008 // 缺少 oauth code
+	ErrCodeUserIdMissing               = 41009 // 缺少 UserID
+	ErrCodeURLMissing                  = 41010 // 缺少 url
+	ErrCodeAgentIdMissing              = 41011 // 缺少 agentid
+	ErrCodeAgentLogoMissing            = 41012 // 缺少应用头像 mediaid
+	ErrCodeAgentNameMissing            = 41013 // 缺少应用名字
+	ErrCodeAgentDescriptionMissing     = 41014 // 缺少应用描述
+	ErrCodeContentMissing              = 41015 // 缺少 Content
+	ErrCodeTitleMissing                = 41016 // 缺少标题
+	ErrCodeTagIdMissing                = 41017 // 缺少标签 ID
+	ErrCodeTagNameMissing              = 41018 // 缺少标签名字
+	ErrCodeAccessTokenExpired          = 42001 // access_token 超时
+	ErrCodeRefreshTokenExpired         = 42002 // refresh_token 超时
+	ErrCodeOAuthCodeExpired            = 42003 // oauth_code 超时
+	ErrCodePluginTokenExpired          = 42004 // 插件 token 超时
+	ErrCodeRequireGETMethod            = 43001 // 需要 GET 请求
+	ErrCodeRequirePOSTMethod           = 43002 // 需要 POST 请求
+	ErrCodeRequireHTTPS                = 43003 // 需要 HTTPS
+	ErrCodeRequireSubscribe            = 43004 // 需要成员已关注
+	ErrCodeRequireFriendRelations      = 43005 // 需要好友关系
+	ErrCodeRequireNotFrozen            = 43006 // 需要订阅
+	ErrCodeRequireAuthorization        = 43007 // 需要授权
+	ErrCodeRequirePaymentAuthorization = 43008 // 需要支付授权
+	ErrCodeRequireCallbackMode         = 43010 // 需要处于回调模式
+	ErrCodeRequireEnterpriseAuth       = 43011 // 需要企业授权
+	ErrCodeEmptyMediaData              = 44001 // 多媒体文件为空
+	ErrCodeEmptyPostData               = 44002 // POST 的数据包为空
+	ErrCodeEmptyNewsData               = 44003 // 图文消息内容为空
+	ErrCodeEmptyContent                = 44004 // 文本消息内容为空
+	ErrCodeMediaSizeOutOfLimit         = 45001 // 多媒体文件大小超过限制
+	ErrCodeContentSizeOutOfLimit       = 45002 // 消息内容超过限制
+	ErrCodeTitleSizeOutOfLimit         = 45003 // 标题字段超过限制
+	ErrCodeDescriptionSizeOutOfLimit   = 45004 // 描述字段超过限制
+	ErrCodeURLSizeOutOfLimit           = 45005 // 链接字段超过限制
+	ErrCodePicURLSizeOutOfLimit        = 45006 // 图片链接字段超过限制
+	ErrCodePlaytimeOutOfLimit          = 45007 // 语音播放时间超过限制
+	ErrCodeArticleSizeOutOfLimit       = 45008 // 图文消息超过限制
+	ErrCodeAPIQuotaExceeded            = 45009 // 接口调用超过限制
+	ErrCodeCreateMenuLimit             = 45010 // 创建菜单个数超过限制
+	ErrCodeResponseOutOfTime           = 45015 // 回复时间超过限制
+	ErrCodeSystemGroupNotAllowed       = 45016 // 系统分组，不允许修改
+	ErrCodeGroupNameTooLong            = 45017 // 分组名字过长
+	ErrCodeGroupCountLimit             = 45018 // 分组数量超过上限
+	ErrCodeAccountCountLimit           = 45024 // 账号数量超过上限
+	ErrCodeConcurrentCallLimit         = 45033 // 接口并发调用超过限制
+	ErrCodeMediaDataNotExist           = 46001 // 不存在媒体数据
+	ErrCodeMenuVersionNotExist         = 46002 // 不存在的菜单版本
+	ErrCodeMenuDataNotExist            = 46003 // 不存在的菜单数据
+	ErrCodeUserNotExist                = 46004 // 不存在的成员
+	ErrCodeDataFormatError             = 47001 // 解析 JSON/XML 内容错误
+	ErrCodeAPIForbidden                = 48002 // API 接口无权限调用
+	ErrCodeSuiteNotAuthorized          = 48003 // 不合法的 suiteid
+	ErrCodeAuthorizationRevoked        = 48004 // 授权关系无效
+	ErrCodeAPIDeprecated               = 48005 // API 接口已废弃
+	ErrCodeRedirectURLUnauthorized     = 50001 // redirect_uri 未授权
+	ErrCodeUserOutOfScope              = 50002 // 成员不在权限范围
+	ErrCodeAgentDisabled               = 50003 // 应用已停用
+	ErrCodeUserStatusInvalid           = 50004 // 成员状态不正确，需要成员为企业验证中状态
+	ErrCodeCorpDisabled                = 50005 // 企业已禁用
+	ErrCodeInvalidDepartmentNameSize   = 60001 // 部门长度不符合限制
+	ErrCodeDepartmentLevelLimit        = 60002 // 部门层级深度超过限制
+	ErrCodeDepartmentNotExist          = 60003 // 部门不存在
+	ErrCodeParentDepartmentNotExist    = 60004 // 父亲部门不存在
+	ErrCodeDepartmentHasMember         = 60005 // 不允许删除有成员的部门
+	ErrCodeDepartmentHasSubDepartment  = 60006 // 不允许删除有子部门的部门
+	ErrCodeRootDepartmentNotAllowed    = 60007 // 不允许删除根部门
+	ErrCodeDepartmentNameExisted       = 60008 // 部门 ID 或者部门名称已存在
+	ErrCodeInvalidDepartmentName       = 60009 // 部门名称含有非法字符
+	ErrCodeDepartmentCycle             = 60010 // 部门存在循环关系
+	ErrCodeAdminPermissionDenied       = 60011 // 管理员权限不足，（user/department/agent）无权限
+	ErrCodeDefaultDepartmentNotAllowed = 60012 // 不允许删除默认应用
+	ErrCodeAgentClosed                 = 60013 // 不允许关闭应用
+	ErrCodeAgentEnabled                = 60014 // 不允许开启应用
+	ErrCodeDefaultAgentNotAllowed      = 60015 // 不允许修改默认应用可见范围
+	ErrCodeDepartmentNotAllowed        = 60016 // 不允许删除存在成员的标签
+	ErrCodeDepartmentSettingDenied     = 60017 // 不允许设置企业
+	ErrCodeUserIdExisted               = 60102 // UserID 已存在
+	ErrCodeInvalidMobile               = 60103 // 手机号码不合法
+	ErrCodeMobileExisted               = 60104 // 手机号码已存在
+	ErrCodeInvalidEmail                = 60105 // 邮箱不合法
+	ErrCodeEmailExisted                = 60106 // 邮箱已存在
+	ErrCodeInvalidWeixinId             = 60107 // 微信号不合法
+	ErrCodeWeixinIdExisted             = 60108 // 微信号已存在
+	ErrCodeQQExisted                   = 60109 // QQ 号已存在
+	ErrCodeDepartmentCountLimit        = 60110 // 用户同时归属部门超过 20 个
+	ErrCodeUserIdNotExist              = 60111 // UserID 不存在
+	ErrCodeInvalidUserName             = 60112 // 成员姓名不合法
+	ErrCodeContactInfoEmpty            = 60113 // 身份认证信息（微信号/手机/邮箱）不能同时为空
+	ErrCodeInvalidGender               = 60114 // 性别不合法
+	ErrCodeFollowedUserEmailNotAllowed = 60115 // 已关注成员微信不能修改
+	ErrCodeExtAttrExisted              = 60116 // 扩展属性已存在
+	ErrCodeEmptyUpdateField            = 60118 // 成员无有效邀请字段，详情参考(邀请成员关注)的接口说明
+	ErrCodeUserSubscribed              = 60119 // 成员已关注
+	ErrCodeUserDisabled                = 60120 // 成员已禁用
+	ErrCodeUserNotFound                = 60121 // 找不到该成员
+	ErrCodeEmailSubscribed             = 60122 // 邮箱已被外部管理员使用
+	ErrCodeInvalidPosition             = 60123 // 无效的部门 id
+	ErrCodeInvalidParentDepartment     = 60124 // 无效的父部门 id
+	ErrCodeNonDepartmentMember         = 60125 // 非法部门名字，长度超过限制、重名等
+	ErrCodeCreateDepartmentFailed      = 60126 // 创建部门失败
+	ErrCodeInvalidDepartmentParent     = 60127 // 缺少部门 id
+	ErrCodeInvalidMobileRepeat         = 60128 // 字段不合法，可能存在主键冲突或者格式错误
+	ErrCodeReliableDomainNotSet        = 80001 // 可信域名不正确，或者无 ICP 备案
+	ErrCodeDepartmentRangeOverflow     = 81001 // 部门下的结点数超过限制（3W）
+	ErrCodeDepartmentMemberOverflow    = 81002 // 部门最多 15 层
+	ErrCodeAllRecipientsInvalid        = 82001 // 发送消息或者邀请的参数全部为空或者全部不合法
+	ErrCodeInvalidPartyListSize        = 82002 // 不合法的 PartyID 列表长度
+	ErrCodeInvalidTagListSize          = 82003 // 不合法的 TagID 列表长度
+	ErrCodeWeixinVersionTooLow         = 82004 // 微信版本号过低
+	ErrCodeInvalidAgentPermission      = 84014 // 成员票据过期
+	ErrCodeInvalidUserTicket           = 84015 // 成员票据无效
+	ErrCodeUserTicketNotMatch          = 84016 // 成员票据不属于当前应用
+	ErrCodeSafeModeNotSupported        = 84017 // 不支持安全模式
+	ErrCodeInvalidChatId               = 86001 // 参数 chatid 不合法
+	ErrCodeChatNotExist                = 86003 // 参数 chatid 不存在
+	ErrCodeInvalidChatName             = 86004 // 参数群名不合法
+	ErrCodeInvalidChatOwner            = 86005 // 参数群主不合法
+	ErrCodeChatMemberCountLimit        = 86006 // 群成员数过多或过少
+	ErrCodeInvalidChatMember           = 86007 // 不合法的群成员
+	ErrCodeNonChatMember               = 86008 // 非法操作非自己创建的群
+	ErrCodeChatOwnerRequired           = 86101 // 需要群主或管理员权限
+	ErrCodeInvalidReceiverType         = 86201 // 参数需要 chatid
+	ErrCodeChatReceiverMissing         = 86202 // 参数需要 receiver
+	ErrCodeInvalidReceiver             = 86203 // 参数需要 sender
+	ErrCodeInvalidMessageContent       = 86204 // 参数需要 msg
+	ErrCodeInvalidSender               = 86205 // 参数 sender 不合法
+	ErrCodeInvalidReceiverId           = 86206 // 参数 receiver 不合法
+	ErrCodeInvalidChatMessageType      = 86207 // 不合法的消息类型
+	ErrCodeInvalidChatMessage          = 86208 // 参数 msg 不合法
+	ErrCodeInvalidMessageSize          = 86209 // 消息长度超过限制
+	ErrCodeSenderNotChatMember         = 86210 // 发送者不是群成员
+	ErrCodeReceiverNotChatMember       = 86211 // 接收者不是群成员
+	ErrCodeInvalidChatOperation        = 86213 // 不合法的操作
+	ErrCodeChatMemberExisted           = 86214 // 群成员已存在
+	ErrCodeChatMemberNotExist          = 86215 // 群成员不存在
+	ErrCodeChatOwnerCannotQuit         = 86216 // 群主不能退群
+	ErrCodeInvalidMessageId            = 86217 // 不合法的消息 id
+	ErrCodeChatMessageSendFailed       = 86220 // 发送消息失败
+)
+
+// 可以用 errors.Is(err, ErrXxx) 判断错误码
+var (
+	ErrSystemBusy                  = &Error{ErrCode: ErrCodeSystemBusy, ErrMsg: "system busy"}
+	ErrInvalidCredential           = &Error{ErrCode: ErrCodeInvalidCredential, ErrMsg: "invalid credential"}
+	ErrInvalidGrantType            = &Error{ErrCode: ErrCodeInvalidGrantType, ErrMsg: "invalid grant_type"}
+	ErrInvalidUserId               = &Error{ErrCode: ErrCodeInvalidUserId, ErrMsg: "invalid userid"}
+	ErrInvalidMediaType            = &Error{ErrCode: ErrCodeInvalidMediaType, ErrMsg: "invalid media type"}
+	ErrInvalidFileType             = &Error{ErrCode: ErrCodeInvalidFileType, ErrMsg: "invalid file type"}
+	ErrInvalidFileSize             = &Error{ErrCode: ErrCodeInvalidFileSize, ErrMsg: "invalid file size"}
+	ErrInvalidMediaId              = &Error{ErrCode: ErrCodeInvalidMediaId, ErrMsg: "invalid media_id"}
+	ErrInvalidMessageType          = &Error{ErrCode: ErrCodeInvalidMessageType, ErrMsg: "invalid message type"}
+	ErrInvalidCorpId               = &Error{ErrCode: ErrCodeInvalidCorpId, ErrMsg: "invalid corpid"}
+	ErrInvalidAccessToken          = &Error{ErrCode: ErrCodeInvalidAccessToken, ErrMsg: "invalid access_token"}
+	ErrInvalidMenuType             = &Error{ErrCode: ErrCodeInvalidMenuType, ErrMsg: "invalid menu type"}
+	ErrInvalidButtonCount          = &Error{ErrCode: ErrCodeInvalidButtonCount, ErrMsg: "invalid button count"}
+	ErrInvalidButtonType           = &Error{ErrCode: ErrCodeInvalidButtonType, ErrMsg: "invalid button type"}
+	ErrInvalidButtonNameSize       = &Error{ErrCode: ErrCodeInvalidButtonNameSize, ErrMsg: "invalid button name size"}
+	ErrInvalidButtonKeySize        = &Error{ErrCode: ErrCodeInvalidButtonKeySize, ErrMsg: "invalid button key size"}
+	ErrInvalidButtonURLSize        = &Error{ErrCode: ErrCodeInvalidButtonURLSize, ErrMsg: "invalid button url size"}
+	ErrInvalidSubMenuLevel         = &Error{ErrCode: ErrCodeInvalidSubMenuLevel, ErrMsg: "invalid sub menu level"}
+	ErrInvalidSubButtonCount       = &Error{ErrCode: ErrCodeInvalidSubButtonCount, ErrMsg: "invalid sub button count"}
+	ErrInvalidSubButtonType        = &Error{ErrCode: ErrCodeInvalidSubButtonType, ErrMsg: "invalid sub button type"}
+	ErrInvalidSubButtonNameSize    = &Error{ErrCode: ErrCodeInvalidSubButtonNameSize, ErrMsg: "invalid sub button name size"}
+	ErrInvalidSubButtonKeySize     = &Error{ErrCode: ErrCodeInvalidSubButtonKeySize, ErrMsg: "invalid sub button key size"}
+	ErrInvalidSubButtonURLSize     = &Error{ErrCode: ErrCodeInvalidSubButtonURLSize, ErrMsg: "invalid sub button url size"}
+	ErrInvalidOAuthCode            = &Error{ErrCode: ErrCodeInvalidOAuthCode, ErrMsg: "invalid oauth code"}
+	ErrInvalidUserList             = &Error{ErrCode: ErrCodeInvalidUserList, ErrMsg: "invalid userid list"}
+	ErrInvalidUserListSize         = &Error{ErrCode: ErrCodeInvalidUserListSize, ErrMsg: "invalid userid list size"}
+	ErrInvalidCharset              = &Error{ErrCode: ErrCodeInvalidCharset, ErrMsg: "invalid charset, \\uxxxx is not allowed"}
+	ErrInvalidParameter            = &Error{ErrCode: ErrCodeInvalidParameter, ErrMsg: "invalid parameter"}
+	ErrInvalidRequestFormat        = &Error{ErrCode: ErrCodeInvalidRequestFormat, ErrMsg: "invalid request format"}
+	ErrInvalidURLSize              = &Error{ErrCode: ErrCodeInvalidURLSize, ErrMsg: "invalid url size"}
+	ErrInvalidSubMenuURLDomain     = &Error{ErrCode: ErrCodeInvalidSubMenuURLDomain, ErrMsg: "invalid sub menu url domain"}
+	ErrInvalidMenuURLDomain        = &Error{ErrCode: ErrCodeInvalidMenuURLDomain, ErrMsg: "invalid menu url domain"}
+	ErrInvalidAgentId              = &Error{ErrCode: ErrCodeInvalidAgentId, ErrMsg: "invalid agentid"}
+	ErrInvalidCallbackURL          = &Error{ErrCode: ErrCodeInvalidCallbackURL, ErrMsg: "invalid callback url"}
+	ErrInvalidRedirectURL          = &Error{ErrCode: ErrCodeInvalidRedirectURL, ErrMsg: "invalid redirect url"}
+	ErrInvalidReportLocationFlag   = &Error{ErrCode: ErrCodeInvalidReportLocationFlag, ErrMsg: "invalid report location flag"}
+	ErrInvalidDepartmentId         = &Error{ErrCode: ErrCodeInvalidDepartmentId, ErrMsg: "department has sub department or member"}
+	ErrInvalidAgentLogo            = &Error{ErrCode: ErrCodeInvalidAgentLogo, ErrMsg: "set agent logo failed"}
+	ErrInvalidIsReportEnter        = &Error{ErrCode: ErrCodeInvalidIsReportEnter, ErrMsg: "invalid agent mode"}
+	ErrEmptyParameter              = &Error{ErrCode: ErrCodeEmptyParameter, ErrMsg: "parameter is empty"}
+	ErrDuplicateAgentName          = &Error{ErrCode: ErrCodeDuplicateAgentName, ErrMsg: "duplicate group name"}
+	ErrInvalidGroupNameSize        = &Error{ErrCode: ErrCodeInvalidGroupNameSize, ErrMsg: "invalid group name size"}
+	ErrInvalidDepartmentList       = &Error{ErrCode: ErrCodeInvalidDepartmentList, ErrMsg: "invalid department list"}
+	ErrInvalidTitleSize            = &Error{ErrCode: ErrCodeInvalidTitleSize, ErrMsg: "invalid title size"}
+	ErrInvalidTagId                = &Error{ErrCode: ErrCodeInvalidTagId, ErrMsg: "invalid tagid"}
+	ErrInvalidTagIdList            = &Error{ErrCode: ErrCodeInvalidTagIdList, ErrMsg: "invalid tagid list"}
+	ErrInvalidTagUserList          = &Error{ErrCode: ErrCodeInvalidTagUserList, ErrMsg: "all tag or user ids are invalid"}
+	ErrInvalidTagName              = &Error{ErrCode: ErrCodeInvalidTagName, ErrMsg: "invalid tag name, already exists"}
+	ErrInvalidTagNameSize          = &Error{ErrCode: ErrCodeInvalidTagNameSize, ErrMsg: "invalid tag name size"}
+	ErrInvalidOpenId               = &Error{ErrCode: ErrCodeInvalidOpenId, ErrMsg: "invalid openid"}
+	ErrNewsMessageNotSupported     = &Error{ErrCode: ErrCodeNewsMessageNotSupported, ErrMsg: "news message can not be safe"}
+	ErrAccessTokenMissing          = &Error{ErrCode: ErrCodeAccessTokenMissing, ErrMsg: "access_token missing"}
+	ErrCorpIdMissing               = &Error{ErrCode: ErrCodeCorpIdMissing, ErrMsg: "corpid missing"}
+	ErrSecretMissing               = &Error{ErrCode: ErrCodeSecretMissing, ErrMsg: "secret missing"}
+	ErrMediaDataMissing            = &Error{ErrCode: ErrCodeMediaDataMissing, ErrMsg: "media data missing"}
+	ErrMediaIdMissing              = &Error{ErrCode: ErrCodeMediaIdMissing, ErrMsg: "media_id missing"}
+	ErrSubMenuDataMissing          = &Error{ErrCode: ErrCodeSubMenuDataMissing, ErrMsg: "sub menu data missing"}
+	ErrOAuthCodeMissing            = &Error{ErrCode: ErrCodeOAuthCodeMissing, ErrMsg: "oauth code missing"}
+	ErrUserIdMissing               = &Error{ErrCode: ErrCodeUserIdMissing, ErrMsg: "userid missing"}
+	ErrURLMissing                  = &Error{ErrCode: ErrCodeURLMissing, ErrMsg: "url missing"}
+	ErrAgentIdMissing              = &Error{ErrCode: ErrCodeAgentIdMissing, ErrMsg: "agentid missing"}
+	ErrAgentLogoMissing            = &Error{ErrCode: ErrCodeAgentLogoMissing, ErrMsg: "agent logo media_id missing"}
+	ErrAgentNameMissing            = &Error{ErrCode: ErrCodeAgentNameMissing, ErrMsg: "agent name missing"}
+	ErrAgentDescriptionMissing     = &Error{ErrCode: ErrCodeAgentDescriptionMissing, ErrMsg: "agent description missing"}
+	ErrContentMissing              = &Error{ErrCode: ErrCodeContentMissing, ErrMsg: "content missing"}
+	ErrTitleMissing                = &Error{ErrCode: ErrCodeTitleMissing, ErrMsg: "title missing"}
+	ErrTagIdMissing                = &Error{ErrCode: ErrCodeTagIdMissing, ErrMsg: "tagid missing"}
+	ErrTagNameMissing              = &Error{ErrCode: ErrCodeTagNameMissing, ErrMsg: "tag name missing"}
+	ErrAccessTokenExpired          = &Error{ErrCode: ErrCodeAccessTokenExpired, ErrMsg: "access_token expired"}
+	ErrRefreshTokenExpired         = &Error{ErrCode: ErrCodeRefreshTokenExpired, ErrMsg: "refresh_token expired"}
+	ErrOAuthCodeExpired            = &Error{ErrCode: ErrCodeOAuthCodeExpired, ErrMsg: "oauth code expired"}
+	ErrPluginTokenExpired          = &Error{ErrCode: ErrCodePluginTokenExpired, ErrMsg: "plugin token expired"}
+	ErrRequireGETMethod            = &Error{ErrCode: ErrCodeRequireGETMethod, ErrMsg: "require GET method"}
+	ErrRequirePOSTMethod           = &Error{ErrCode: ErrCodeRequirePOSTMethod, ErrMsg: "require POST method"}
+	ErrRequireHTTPS                = &Error{ErrCode: ErrCodeRequireHTTPS, ErrMsg: "require https"}
+	ErrRequireSubscribe            = &Error{ErrCode: ErrCodeRequireSubscribe, ErrMsg: "require subscribe"}
+	ErrRequireFriendRelations      = &Error{ErrCode: ErrCodeRequireFriendRelations, ErrMsg: "require friend relations"}
+	ErrRequireNotFrozen            = &Error{ErrCode: ErrCodeRequireNotFrozen, ErrMsg: "require not frozen"}
+	ErrRequireAuthorization        = &Error{ErrCode: ErrCodeRequireAuthorization, ErrMsg: "require authorization"}
+	ErrRequirePaymentAuthorization = &Error{ErrCode: ErrCodeRequirePaymentAuthorization, ErrMsg: "require payment authorization"}
+	ErrRequireCallbackMode         = &Error{ErrCode: ErrCodeRequireCallbackMode, ErrMsg: "require callback mode"}
+	ErrRequireEnterpriseAuth       = &Error{ErrCode: ErrCodeRequireEnterpriseAuth, ErrMsg: "require enterprise authorization"}
+	ErrEmptyMediaData              = &Error{ErrCode: ErrCodeEmptyMediaData, ErrMsg: "empty media data"}
+	ErrEmptyPostData               = &Error{ErrCode: ErrCodeEmptyPostData, ErrMsg: "empty post data"}
+	ErrEmptyNewsData               = &Error{ErrCode: ErrCodeEmptyNewsData, ErrMsg: "empty news data"}
+	ErrEmptyContent                = &Error{ErrCode: ErrCodeEmptyContent, ErrMsg: "empty content"}
+	ErrMediaSizeOutOfLimit         = &Error{ErrCode: ErrCodeMediaSizeOutOfLimit, ErrMsg: "media size out of limit"}
+	ErrContentSizeOutOfLimit       = &Error{ErrCode: ErrCodeContentSizeOutOfLimit, ErrMsg: "content size out of limit"}
+	ErrTitleSizeOutOfLimit         = &Error{ErrCode: ErrCodeTitleSizeOutOfLimit, ErrMsg: "title size out of limit"}
+	ErrDescriptionSizeOutOfLimit   = &Error{ErrCode: ErrCodeDescriptionSizeOutOfLimit, ErrMsg: "description size out of limit"}
+	ErrURLSizeOutOfLimit           = &Error{ErrCode: ErrCodeURLSizeOutOfLimit, ErrMsg: "url size out of limit"}
+	ErrPicURLSizeOutOfLimit        = &Error{ErrCode: ErrCodePicURLSizeOutOfLimit, ErrMsg: "picurl size out of limit"}
+	ErrPlaytimeOutOfLimit          = &Error{ErrCode: ErrCodePlaytimeOutOfLimit, ErrMsg: "playtime out of limit"}
+	ErrArticleSizeOutOfLimit       = &Error{ErrCode: ErrCodeArticleSizeOutOfLimit, ErrMsg: "article size out of limit"}
+	ErrAPIQuotaExceeded            = &Error{ErrCode: ErrCodeAPIQuotaExceeded, ErrMsg: "api freq out of limit"}
+	ErrCreateMenuLimit             = &Error{ErrCode: ErrCodeCreateMenuLimit, ErrMsg: "create menu limit"}
+	ErrResponseOutOfTime           = &Error{ErrCode: ErrCodeResponseOutOfTime, ErrMsg: "response out of time limit"}
+	ErrSystemGroupNotAllowed       = &Error{ErrCode: ErrCodeSystemGroupNotAllowed, ErrMsg: "system group can not be modified"}
+	ErrGroupNameTooLong            = &Error{ErrCode: ErrCodeGroupNameTooLong, ErrMsg: "group name too long"}
+	ErrGroupCountLimit             = &Error{ErrCode: ErrCodeGroupCountLimit, ErrMsg: "too many group now"}
+	ErrAccountCountLimit           = &Error{ErrCode: ErrCodeAccountCountLimit, ErrMsg: "account count limit"}
+	ErrConcurrentCallLimit         = &Error{ErrCode: ErrCodeConcurrentCallLimit, ErrMsg: "api concurrent call out of limit"}
+	ErrMediaDataNotExist           = &Error{ErrCode: ErrCodeMediaDataNotExist, ErrMsg: "media data no exist"}
+	ErrMenuVersionNotExist         = &Error{ErrCode: ErrCodeMenuVersionNotExist, ErrMsg: "menu version no exist"}
+	ErrMenuDataNotExist            = &Error{ErrCode: ErrCodeMenuDataNotExist, ErrMsg: "menu no exist"}
+	ErrUserNotExist                = &Error{ErrCode: ErrCodeUserNotExist, ErrMsg: "user no exist"}
+	ErrDataFormatError             = &Error{ErrCode: ErrCodeDataFormatError, ErrMsg: "data format error"}
+	ErrAPIForbidden                = &Error{ErrCode: ErrCodeAPIForbidden, ErrMsg: "api forbidden"}
+	ErrSuiteNotAuthorized          = &Error{ErrCode: ErrCodeSuiteNotAuthorized, ErrMsg: "invalid suiteid"}
+	ErrAuthorizationRevoked        = &Error{ErrCode: ErrCodeAuthorizationRevoked, ErrMsg: "authorization revoked"}
+	ErrAPIDeprecated               = &Error{ErrCode: ErrCodeAPIDeprecated, ErrMsg: "api deprecated"}
+	ErrRedirectURLUnauthorized     = &Error{ErrCode: ErrCodeRedirectURLUnauthorized, ErrMsg: "redirect_uri unauthorized"}
+	ErrUserOutOfScope              = &Error{ErrCode: ErrCodeUserOutOfScope, ErrMsg: "user out of scope"}
+	ErrAgentDisabled               = &Error{ErrCode: ErrCodeAgentDisabled, ErrMsg: "agent disabled"}
+	ErrUserStatusInvalid           = &Error{ErrCode: ErrCodeUserStatusInvalid, ErrMsg: "invalid user status"}
+	ErrCorpDisabled                = &Error{ErrCode: ErrCodeCorpDisabled, ErrMsg: "corp disabled"}
+	ErrInvalidDepartmentNameSize   = &Error{ErrCode: ErrCodeInvalidDepartmentNameSize, ErrMsg: "invalid department name size"}
+	ErrDepartmentLevelLimit        = &Error{ErrCode: ErrCodeDepartmentLevelLimit, ErrMsg: "department level out of limit"}
+	ErrDepartmentNotExist          = &Error{ErrCode: ErrCodeDepartmentNotExist, ErrMsg: "department no exist"}
+	ErrParentDepartmentNotExist    = &Error{ErrCode: ErrCodeParentDepartmentNotExist, ErrMsg: "parent department no exist"}
+	ErrDepartmentHasMember         = &Error{ErrCode: ErrCodeDepartmentHasMember, ErrMsg: "department has member"}
+	ErrDepartmentHasSubDepartment  = &Error{ErrCode: ErrCodeDepartmentHasSubDepartment, ErrMsg: "department has sub department"}
+	ErrRootDepartmentNotAllowed    = &Error{ErrCode: ErrCodeRootDepartmentNotAllowed, ErrMsg: "root department can not be deleted"}
+	ErrDepartmentNameExisted       = &Error{ErrCode: ErrCodeDepartmentNameExisted, ErrMsg: "department id or name existed"}
+	ErrInvalidDepartmentName       = &Error{ErrCode: ErrCodeInvalidDepartmentName, ErrMsg: "invalid department name"}
+	ErrDepartmentCycle             = &Error{ErrCode: ErrCodeDepartmentCycle, ErrMsg: "department cycle"}
+	ErrAdminPermissionDenied       = &Error{ErrCode: ErrCodeAdminPermissionDenied, ErrMsg: "no privilege to access/modify contact/party/agent"}
+	ErrDefaultDepartmentNotAllowed = &Error{ErrCode: ErrCodeDefaultDepartmentNotAllowed, ErrMsg: "default agent can not be deleted"}
+	ErrAgentClosed                 = &Error{ErrCode: ErrCodeAgentClosed, ErrMsg: "agent can not be closed"}
+	ErrAgentEnabled                = &Error{ErrCode: ErrCodeAgentEnabled, ErrMsg: "agent can not be opened"}
+	ErrDefaultAgentNotAllowed      = &Error{ErrCode: ErrCodeDefaultAgentNotAllowed, ErrMsg: "default agent visible range can not be modified"}
+	ErrDepartmentNotAllowed        = &Error{ErrCode: ErrCodeDepartmentNotAllowed, ErrMsg: "tag has member"}
+	ErrDepartmentSettingDenied     = &Error{ErrCode: ErrCodeDepartmentSettingDenied, ErrMsg: "corp setting not allowed"}
+	ErrUserIdExisted               = &Error{ErrCode: ErrCodeUserIdExisted, ErrMsg: "userid existed"}
+	ErrInvalidMobile               = &Error{ErrCode: ErrCodeInvalidMobile, ErrMsg: "invalid mobile"}
+	ErrMobileExisted               = &Error{ErrCode: ErrCodeMobileExisted, ErrMsg: "mobile existed"}
+	ErrInvalidEmail                = &Error{ErrCode: ErrCodeInvalidEmail, ErrMsg: "invalid email"}
+	ErrEmailExisted                = &Error{ErrCode: ErrCodeEmailExisted, ErrMsg: "email existed"}
+	ErrInvalidWeixinId             = &Error{ErrCode: ErrCodeInvalidWeixinId, ErrMsg: "invalid weixinid"}
+	ErrWeixinIdExisted             = &Error{ErrCode: ErrCodeWeixinIdExisted, ErrMsg: "weixinid existed"}
+	ErrQQExisted                   = &Error{ErrCode: ErrCodeQQExisted, ErrMsg: "qq existed"}
+	ErrDepartmentCountLimit        = &Error{ErrCode: ErrCodeDepartmentCountLimit, ErrMsg: "department count of user out of limit"}
+	ErrUserIdNotExist              = &Error{ErrCode: ErrCodeUserIdNotExist, ErrMsg: "userid no exist"}
+	ErrInvalidUserName             = &Error{ErrCode: ErrCodeInvalidUserName, ErrMsg: "invalid user name"}
+	ErrContactInfoEmpty            = &Error{ErrCode: ErrCodeContactInfoEmpty, ErrMsg: "weixinid, mobile and email can not be empty at the same time"}
+	ErrInvalidGender               = &Error{ErrCode: ErrCodeInvalidGender, ErrMsg: "invalid gender"}
+	ErrFollowedUserEmailNotAllowed = &Error{ErrCode: ErrCodeFollowedUserEmailNotAllowed, ErrMsg: "weixinid of subscribed user can not be modified"}
+	ErrExtAttrExisted              = &Error{ErrCode: ErrCodeExtAttrExisted, ErrMsg: "extattr existed"}
+	ErrEmptyUpdateField            = &Error{ErrCode: ErrCodeEmptyUpdateField, ErrMsg: "no valid invite field"}
+	ErrUserSubscribed              = &Error{ErrCode: ErrCodeUserSubscribed, ErrMsg: "user subscribed"}
+	ErrUserDisabled                = &Error{ErrCode: ErrCodeUserDisabled, ErrMsg: "user disabled"}
+	ErrUserNotFound                = &Error{ErrCode: ErrCodeUserNotFound, ErrMsg: "user not found"}
+	ErrEmailSubscribed             = &Error{ErrCode: ErrCodeEmailSubscribed, ErrMsg: "email used by outer admin"}
+	ErrInvalidPosition             = &Error{ErrCode: ErrCodeInvalidPosition, ErrMsg: "invalid party id"}
+	ErrInvalidParentDepartment     = &Error{ErrCode: ErrCodeInvalidParentDepartment, ErrMsg: "invalid parent party id"}
+	ErrNonDepartmentMember         = &Error{ErrCode: ErrCodeNonDepartmentMember, ErrMsg: "invalid party name"}
+	ErrCreateDepartmentFailed      = &Error{ErrCode: ErrCodeCreateDepartmentFailed, ErrMsg: "create party failed"}
+	ErrInvalidDepartmentParent     = &Error{ErrCode: ErrCodeInvalidDepartmentParent, ErrMsg: "party id missing"}
+	ErrInvalidMobileRepeat         = &Error{ErrCode: ErrCodeInvalidMobileRepeat, ErrMsg: "invalid field"}
+	ErrReliableDomainNotSet        = &Error{ErrCode: ErrCodeReliableDomainNotSet, ErrMsg: "invalid reliable domain"}
+	ErrDepartmentRangeOverflow     = &Error{ErrCode: ErrCodeDepartmentRangeOverflow, ErrMsg: "department node count out of limit"}
+	ErrDepartmentMemberOverflow    = &Error{ErrCode: ErrCodeDepartmentMemberOverflow, ErrMsg: "department level out of limit"}
+	ErrAllRecipientsInvalid        = &Error{ErrCode: ErrCodeAllRecipientsInvalid, ErrMsg: "all receivers are empty or invalid"}
+	ErrInvalidPartyListSize        = &Error{ErrCode: ErrCodeInvalidPartyListSize, ErrMsg: "invalid party list size"}
+	ErrInvalidTagListSize          = &Error{ErrCode: ErrCodeInvalidTagListSize, ErrMsg: "invalid tag list size"}
+	ErrWeixinVersionTooLow         = &Error{ErrCode: ErrCodeWeixinVersionTooLow, ErrMsg: "weixin version too low"}
+	ErrInvalidAgentPermission      = &Error{ErrCode: ErrCodeInvalidAgentPermission, ErrMsg: "user ticket expired"}
+	ErrInvalidUserTicket           = &Error{ErrCode: ErrCodeInvalidUserTicket, ErrMsg: "invalid user ticket"}
+	ErrUserTicketNotMatch          = &Error{ErrCode: ErrCodeUserTicketNotMatch, ErrMsg: "user ticket does not belong to the agent"}
+	ErrSafeModeNotSupported        = &Error{ErrCode: ErrCodeSafeModeNotSupported, ErrMsg: "safe mode not supported"}
+	ErrInvalidChatId               = &Error{ErrCode: ErrCodeInvalidChatId, ErrMsg: "invalid chatid"}
+	ErrChatNotExist                = &Error{ErrCode: ErrCodeChatNotExist, ErrMsg: "chatid no exist"}
+	ErrInvalidChatName             = &Error{ErrCode: ErrCodeInvalidChatName, ErrMsg: "invalid chat name"}
+	ErrInvalidChatOwner            = &Error{ErrCode: ErrCodeInvalidChatOwner, ErrMsg: "invalid chat owner"}
+	ErrChatMemberCountLimit        = &Error{ErrCode: ErrCodeChatMemberCountLimit, ErrMsg: "chat member count out of limit"}
+	ErrInvalidChatMember           = &Error{ErrCode: ErrCodeInvalidChatMember, ErrMsg: "invalid chat member"}
+	ErrNonChatMember               = &Error{ErrCode: ErrCodeNonChatMember, ErrMsg: "not owner of the chat"}
+	ErrChatOwnerRequired           = &Error{ErrCode: ErrCodeChatOwnerRequired, ErrMsg: "owner or admin required"}
+	ErrInvalidReceiverType         = &Error{ErrCode: ErrCodeInvalidReceiverType, ErrMsg: "chatid required"}
+	ErrChatReceiverMissing         = &Error{ErrCode: ErrCodeChatReceiverMissing, ErrMsg: "receiver required"}
+	ErrInvalidReceiver             = &Error{ErrCode: ErrCodeInvalidReceiver, ErrMsg: "sender required"}
+	ErrInvalidMessageContent       = &Error{ErrCode: ErrCodeInvalidMessageContent, ErrMsg: "msg required"}
+	ErrInvalidSender               = &Error{ErrCode: ErrCodeInvalidSender, ErrMsg: "invalid sender"}
+	ErrInvalidReceiverId           = &Error{ErrCode: ErrCodeInvalidReceiverId, ErrMsg: "invalid receiver"}
+	ErrInvalidChatMessageType      = &Error{ErrCode: ErrCodeInvalidChatMessageType, ErrMsg: "invalid msgtype"}
+	ErrInvalidChatMessage          = &Error{ErrCode: ErrCodeInvalidChatMessage, ErrMsg: "invalid msg"}
+	ErrInvalidMessageSize          = &Error{ErrCode: ErrCodeInvalidMessageSize, ErrMsg: "msg size out of limit"}
+	ErrSenderNotChatMember         = &Error{ErrCode: ErrCodeSenderNotChatMember, ErrMsg: "sender is not a member of the chat"}
+	ErrReceiverNotChatMember       = &Error{ErrCode: ErrCodeReceiverNotChatMember, ErrMsg: "receiver is not a member of the chat"}
+	ErrInvalidChatOperation        = &Error{ErrCode: ErrCodeInvalidChatOperation, ErrMsg: "invalid operation"}
+	ErrChatMemberExisted           = &Error{ErrCode: ErrCodeChatMemberExisted, ErrMsg: "chat member existed"}
+	ErrChatMemberNotExist          = &Error{ErrCode: ErrCodeChatMemberNotExist, ErrMsg: "chat member no exist"}
+	ErrChatOwnerCannotQuit         = &Error{ErrCode: ErrCodeChatOwnerCannotQuit, ErrMsg: "owner can not quit the chat"}
+	ErrInvalidMessageId            = &Error{ErrCode: ErrCodeInvalidMessageId, ErrMsg: "invalid msgid"}
+	ErrChatMessageSendFailed       = &Error{ErrCode: ErrCodeChatMessageSendFailed, ErrMsg: "send msg failed"}
+)
+
+var errCodeInfos = map[int]errCodeInfo{
+	ErrCodeSystemBusy:                  {"系统繁忙", "system busy", true, false},
+	ErrCodeInvalidCredential:           {"获取 access_token 时 Secret 错误，或者 access_token 无效", "invalid credential", false, true},
+	ErrCodeInvalidGrantType:            {"不合法的凭证类型", "invalid grant_type", false, false},
+	ErrCodeInvalidUserId:               {"不合法的 UserID", "invalid userid", false, false},
+	ErrCodeInvalidMediaType:            {"不合法的媒体文件类型", "invalid media type", false, false},
+	ErrCodeInvalidFileType:             {"不合法的文件类型", "invalid file type", false, false},
+	ErrCodeInvalidFileSize:             {"不合法的文件大小", "invalid file size", false, false},
+	ErrCodeInvalidMediaId:              {"不合法的媒体文件 id", "invalid media_id", false, false},
+	ErrCodeInvalidMessageType:          {"不合法的消息类型", "invalid message type", false, false},
+	ErrCodeInvalidCorpId:               {"不合法的 corpid", "invalid corpid", false, false},
+	ErrCodeInvalidAccessToken:          {"不合法的 access_token", "invalid access_token", false, true},
+	ErrCodeInvalidMenuType:             {"不合法的菜单类型", "invalid menu type", false, false},
+	ErrCodeInvalidButtonCount:          {"不合法的按钮个数", "invalid button count", false, false},
+	ErrCodeInvalidButtonType:           {"不合法的按钮类型", "invalid button type", false, false},
+	ErrCodeInvalidButtonNameSize:       {"不合法的按钮名字长度", "invalid button name size", false, false},
+	ErrCodeInvalidButtonKeySize:        {"不合法的按钮 KEY 长度", "invalid button key size", false, false},
+	ErrCodeInvalidButtonURLSize:        {"不合法的按钮 URL 长度", "invalid button url size", false, false},
+	ErrCodeInvalidSubMenuLevel:         {"不合法的子菜单级数", "invalid sub menu level", false, false},
+	ErrCodeInvalidSubButtonCount:       {"不合法的子菜单按钮个数", "invalid sub button count", false, false},
+	ErrCodeInvalidSubButtonType:        {"不合法的子菜单按钮类型", "invalid sub button type", false, false},
+	ErrCodeInvalidSubButtonNameSize:    {"不合法的子菜单按钮名字长度", "invalid sub button name size", false, false},
+	ErrCodeInvalidSubButtonKeySize:     {"不合法的子菜单按钮 KEY 长度", "invalid sub button key size", false, false},
+	ErrCodeInvalidSubButtonURLSize:     {"不合法的子菜单按钮 URL 长度", "invalid sub button url size", false, false},
+	ErrCodeInvalidOAuthCode:            {"不合法的 oauth_code", "invalid oauth code", false, false},
+	ErrCodeInvalidUserList:             {"不合法的 UserID 列表", "invalid userid list", false, false},
+	ErrCodeInvalidUserListSize:         {"不合法的 UserID 列表长度", "invalid userid list size", false, false},
+	ErrCodeInvalidCharset:              {"不合法的请求字符，不能包含 \\uxxxx 格式的字符", "invalid charset, \\uxxxx is not allowed", false, false},
+	ErrCodeInvalidParameter:            {"不合法的参数", "invalid parameter", false, false},
+	ErrCodeInvalidRequestFormat:        {"不合法的请求格式", "invalid request format", false, false},
+	ErrCodeInvalidURLSize:              {"不合法的 URL 长度", "invalid url size", false, false},
+	ErrCodeInvalidSubMenuURLDomain:     {"不合法的子菜单 url 域名", "invalid sub menu url domain", false, false},
+	ErrCodeInvalidMenuURLDomain:        {"不合法的菜单 url 域名", "invalid menu url domain", false, false},
+	ErrCodeInvalidAgentId:              {"不合法的 agentid", "invalid agentid", false, false},
+	ErrCodeInvalidCallbackURL:          {"不合法的 callbackurl", "invalid callback url", false, false},
+	ErrCodeInvalidRedirectURL:          {"不合法的红包参数", "invalid redirect url", false, false},
+	ErrCodeInvalidReportLocationFlag:   {"不合法的上报地理位置标志位", "invalid report location flag", false, false},
+	ErrCodeInvalidDepartmentId:         {"删除部门时不能删除有子部门或成员的部门", "department has sub department or member", false, false},
+	ErrCodeInvalidAgentLogo:            {"设置应用头像失败", "set agent logo failed", false, false},
+	ErrCodeInvalidIsReportEnter:        {"不合法的应用模式", "invalid agent mode", false, false},
+	ErrCodeEmptyParameter:              {"参数为空", "parameter is empty", false, false},
+	ErrCodeDuplicateAgentName:          {"管理组名字已存在", "duplicate group name", false, false},
+	ErrCodeInvalidGroupNameSize:        {"不合法的管理组名字长度", "invalid group name size", false, false},
+	ErrCodeInvalidDepartmentList:       {"不合法的部门列表", "invalid department list", false, false},
+	ErrCodeInvalidTitleSize:            {"标题长度不合法", "invalid title size", false, false},
+	ErrCodeInvalidTagId:                {"不合法的标签 ID", "invalid tagid", false, false},
+	ErrCodeInvalidTagIdList:            {"不合法的标签 ID 列表", "invalid tagid list", false, false},
+	ErrCodeInvalidTagUserList:          {"列表中所有标签（用户）ID 都不合法", "all tag or user ids are invalid", false, false},
+	ErrCodeInvalidTagName:              {"不合法的标签名字，标签名字已经存在", "invalid tag name, already exists", false, false},
+	ErrCodeInvalidTagNameSize:          {"不合法的标签名字长度", "invalid tag name size", false, false},
+	ErrCodeInvalidOpenId:               {"不合法的 openid", "invalid openid", false, false},
+	ErrCodeNewsMessageNotSupported:     {"news 消息不支持指定为高保密消息", "news message can not be safe", false, false},
+	ErrCodeAccessTokenMissing:          {"缺少 access_token 参数", "access_token missing", false, false},
+	ErrCodeCorpIdMissing:               {"缺少 corpid 参数", "corpid missing", false, false},
+	ErrCodeSecretMissing:               {"缺少 secret 参数", "secret missing", false, false},
+	ErrCodeMediaDataMissing:            {"缺少多媒体文件数据", "media data missing", false, false},
+	ErrCodeMediaIdMissing:              {"缺少 media_id 参数", "media_id missing", false, false},
+	ErrCodeSubMenuDataMissing:          {"缺少子菜单数据", "sub menu data missing", false, false},
+	ErrCodeOAuthCodeMissing:            {"缺少 oauth code", "oauth code missing", false, false},
+	ErrCodeUserIdMissing:               {"缺少 UserID", "userid missing", false, false},
+	ErrCodeURLMissing:                  {"缺少 url", "url missing", false, false},
+	ErrCodeAgentIdMissing:              {"缺少 agentid", "agentid missing", false, false},
+	ErrCodeAgentLogoMissing:            {"缺少应用头像 mediaid", "agent logo media_id missing", false, false},
+	ErrCodeAgentNameMissing:            {"缺少应用名字", "agent name missing", false, false},
+	ErrCodeAgentDescriptionMissing:     {"缺少应用描述", "agent description missing", false, false},
+	ErrCodeContentMissing:              {"缺少 Content", "content missing", false, false},
+	ErrCodeTitleMissing:                {"缺少标题", "title missing", false, false},
+	ErrCodeTagIdMissing:                {"缺少标签 ID", "tagid missing", false, false},
+	ErrCodeTagNameMissing:              {"缺少标签名字", "tag name missing", false, false},
+	ErrCodeAccessTokenExpired:          {"access_token 超时", "access_token expired", false, true},
+	ErrCodeRefreshTokenExpired:         {"refresh_token 超时", "refresh_token expired", false, false},
+	ErrCodeOAuthCodeExpired:            {"oauth_code 超时", "oauth code expired", false, false},
+	ErrCodePluginTokenExpired:          {"插件 token 超时", "plugin token expired", false, false},
+	ErrCodeRequireGETMethod:            {"需要 GET 请求", "require GET method", false, false},
+	ErrCodeRequirePOSTMethod:           {"需要 POST 请求", "require POST method", false, false},
+	ErrCodeRequireHTTPS:                {"需要 HTTPS", "require https", false, false},
+	ErrCodeRequireSubscribe:            {"需要成员已关注", "require subscribe", false, false},
+	ErrCodeRequireFriendRelations:      {"需要好友关系", "require friend relations", false, false},
+	ErrCodeRequireNotFrozen:            {"需要订阅", "require not frozen", false, false},
+	ErrCodeRequireAuthorization:        {"需要授权", "require authorization", false, false},
+	ErrCodeRequirePaymentAuthorization: {"需要支付授权", "require payment authorization", false, false},
+	ErrCodeRequireCallbackMode:         {"需要处于回调模式", "require callback mode", false, false},
+	ErrCodeRequireEnterpriseAuth:       {"需要企业授权", "require enterprise authorization", false, false},
+	ErrCodeEmptyMediaData:              {"多媒体文件为空", "empty media data", false, false},
+	ErrCodeEmptyPostData:               {"POST 的数据包为空", "empty post data", false, false},
+	ErrCodeEmptyNewsData:               {"图文消息内容为空", "empty news data", false, false},
+	ErrCodeEmptyContent:                {"文本消息内容为空", "empty content", false, false},
+	ErrCodeMediaSizeOutOfLimit:         {"多媒体文件大小超过限制", "media size out of limit", false, false},
+	ErrCodeContentSizeOutOfLimit:       {"消息内容超过限制", "content size out of limit", false, false},
+	ErrCodeTitleSizeOutOfLimit:         {"标题字段超过限制", "title size out of limit", false, false},
+	ErrCodeDescriptionSizeOutOfLimit:   {"描述字段超过限制", "description size out of limit", false, false},
+	ErrCodeURLSizeOutOfLimit:           {"链接字段超过限制", "url size out of limit", false, false},
+	ErrCodePicURLSizeOutOfLimit:        {"图片链接字段超过限制", "picurl size out of limit", false, false},
+	ErrCodePlaytimeOutOfLimit:          {"语音播放时间超过限制", "playtime out of limit", false, false},
+	ErrCodeArticleSizeOutOfLimit:       {"图文消息超过限制", "article size out of limit", false, false},
+	ErrCodeAPIQuotaExceeded:            {"接口调用超过限制", "api freq out of limit", false, false},
+	ErrCodeCreateMenuLimit:             {"创建菜单个数超过限制", "create menu limit", false, false},
+	ErrCodeResponseOutOfTime:           {"回复时间超过限制", "response out of time limit", false, false},
+	ErrCodeSystemGroupNotAllowed:       {"系统分组，不允许修改", "system group can not be modified", false, false},
+	ErrCodeGroupNameTooLong:            {"分组名字过长", "group name too long", false, false},
+	ErrCodeGroupCountLimit:             {"分组数量超过上限", "too many group now", false, false},
+	ErrCodeAccountCountLimit:           {"账号数量超过上限", "account count limit", false, false},
+	ErrCodeConcurrentCallLimit:         {"接口并发调用超过限制", "api concurrent call out of limit", true, false},
+	ErrCodeMediaDataNotExist:           {"不存在媒体数据", "media data no exist", false, false},
+	ErrCodeMenuVersionNotExist:         {"不存在的菜单版本", "menu version no exist", false, false},
+	ErrCodeMenuDataNotExist:            {"不存在的菜单数据", "menu no exist", false, false},
+	ErrCodeUserNotExist:                {"不存在的成员", "user no exist", false, false},
+	ErrCodeDataFormatError:             {"解析 JSON/XML 内容错误", "data format error", false, false},
+	ErrCodeAPIForbidden:                {"API 接口无权限调用", "api forbidden", false, false},
+	ErrCodeSuiteNotAuthorized:          {"不合法的 suiteid", "invalid suiteid", false, false},
+	ErrCodeAuthorizationRevoked:        {"授权关系无效", "authorization revoked", false, false},
+	ErrCodeAPIDeprecated:               {"API 接口已废弃", "api deprecated", false, false},
+	ErrCodeRedirectURLUnauthorized:     {"redirect_uri 未授权", "redirect_uri unauthorized", false, false},
+	ErrCodeUserOutOfScope:              {"成员不在权限范围", "user out of scope", false, false},
+	ErrCodeAgentDisabled:               {"应用已停用", "agent disabled", false, false},
+	ErrCodeUserStatusInvalid:           {"成员状态不正确，需要成员为企业验证中状态", "invalid user status", false, false},
+	ErrCodeCorpDisabled:                {"企业已禁用", "corp disabled", false, false},
+	ErrCodeInvalidDepartmentNameSize:   {"部门长度不符合限制", "invalid department name size", false, false},
+	ErrCodeDepartmentLevelLimit:        {"部门层级深度超过限制", "department level out of limit", false, false},
+	ErrCodeDepartmentNotExist:          {"部门不存在", "department no exist", false, false},
+	ErrCodeParentDepartmentNotExist:    {"父亲部门不存在", "parent department no exist", false, false},
+	ErrCodeDepartmentHasMember:         {"不允许删除有成员的部门", "department has member", false, false},
+	ErrCodeDepartmentHasSubDepartment:  {"不允许删除有子部门的部门", "department has sub department", false, false},
+	ErrCodeRootDepartmentNotAllowed:    {"不允许删除根部门", "root department can not be deleted", false, false},
+	ErrCodeDepartmentNameExisted:       {"部门 ID 或者部门名称已存在", "department id or name existed", false, false},
+	ErrCodeInvalidDepartmentName:       {"部门名称含有非法字符", "invalid department name", false, false},
+	ErrCodeDepartmentCycle:             {"部门存在循环关系", "department cycle", false, false},
+	ErrCodeAdminPermissionDenied:       {"管理员权限不足，（user/department/agent）无权限", "no privilege to access/modify contact/party/agent", false, false},
+	ErrCodeDefaultDepartmentNotAllowed: {"不允许删除默认应用", "default agent can not be deleted", false, false},
+	ErrCodeAgentClosed:                 {"不允许关闭应用", "agent can not be closed", false, false},
+	ErrCodeAgentEnabled:                {"不允许开启应用", "agent can not be opened", false, false},
+	ErrCodeDefaultAgentNotAllowed:      {"不允许修改默认应用可见范围", "default agent visible range can not be modified", false, false},
+	ErrCodeDepartmentNotAllowed:        {"不允许删除存在成员的标签", "tag has member", false, false},
+	ErrCodeDepartmentSettingDenied:     {"不允许设置企业", "corp setting not allowed", false, false},
+	ErrCodeUserIdExisted:               {"UserID 已存在", "userid existed", false, false},
+	ErrCodeInvalidMobile:               {"手机号码不合法", "invalid mobile", false, false},
+	ErrCodeMobileExisted:               {"手机号码已存在", "mobile existed", false, false},
+	ErrCodeInvalidEmail:                {"邮箱不合法", "invalid email", false, false},
+	ErrCodeEmailExisted:                {"邮箱已存在", "email existed", false, false},
+	ErrCodeInvalidWeixinId:             {"微信号不合法", "invalid weixinid", false, false},
+	ErrCodeWeixinIdExisted:             {"微信号已存在", "weixinid existed", false, false},
+	ErrCodeQQExisted:                   {"QQ 号已存在", "qq existed", false, false},
+	ErrCodeDepartmentCountLimit:        {"用户同时归属部门超过 20 个", "department count of user out of limit", false, false},
+	ErrCodeUserIdNotExist:              {"UserID 不存在", "userid no exist", false, false},
+	ErrCodeInvalidUserName:             {"成员姓名不合法", "invalid user name", false, false},
+	ErrCodeContactInfoEmpty:            {"身份认证信息（微信号/手机/邮箱）不能同时为空", "weixinid, mobile and email can not be empty at the same time", false, false},
+	ErrCodeInvalidGender:               {"性别不合法", "invalid gender", false, false},
+	ErrCodeFollowedUserEmailNotAllowed: {"已关注成员微信不能修改", "weixinid of subscribed user can not be modified", false, false},
+	ErrCodeExtAttrExisted:              {"扩展属性已存在", "extattr existed", false, false},
+	ErrCodeEmptyUpdateField:            {"成员无有效邀请字段，详情参考(邀请成员关注)的接口说明", "no valid invite field", false, false},
+	ErrCodeUserSubscribed:              {"成员已关注", "user subscribed", false, false},
+	ErrCodeUserDisabled:                {"成员已禁用", "user disabled", false, false},
+	ErrCodeUserNotFound:                {"找不到该成员", "user not found", false, false},
+	ErrCodeEmailSubscribed:             {"邮箱已被外部管理员使用", "email used by outer admin", false, false},
+	ErrCodeInvalidPosition:             {"无效的部门 id", "invalid party id", false, false},
+	ErrCodeInvalidParentDepartment:     {"无效的父部门 id", "invalid parent party id", false, false},
+	ErrCodeNonDepartmentMember:         {"非法部门名字，长度超过限制、重名等", "invalid party name", false, false},
+	ErrCodeCreateDepartmentFailed:      {"创建部门失败", "create party failed", false, false},
+	ErrCodeInvalidDepartmentParent:     {"缺少部门 id", "party id missing", false, false},
+	ErrCodeInvalidMobileRepeat:         {"字段不合法，可能存在主键冲突或者格式错误", "invalid field", false, false},
+	ErrCodeReliableDomainNotSet:        {"可信域名不正确，或者无 ICP 备案", "invalid reliable domain", false, false},
+	ErrCodeDepartmentRangeOverflow:     {"部门下的结点数超过限制（3W）", "department node count out of limit", false, false},
+	ErrCodeDepartmentMemberOverflow:    {"部门最多 15 层", "department level out of limit", false, false},
+	ErrCodeAllRecipientsInvalid:        {"发送消息或者邀请的参数全部为空或者全部不合法", "all receivers are empty or invalid", false, false},
+	ErrCodeInvalidPartyListSize:        {"不合法的 PartyID 列表长度", "invalid party list size", false, false},
+	ErrCodeInvalidTagListSize:          {"不合法的 TagID 列表长度", "invalid tag list size", false, false},
+	ErrCodeWeixinVersionTooLow:         {"微信版本号过低", "weixin version too low", false, false},
+	ErrCodeInvalidAgentPermission:      {"成员票据过期", "user ticket expired", false, false},
+	ErrCodeInvalidUserTicket:           {"成员票据无效", "invalid user ticket", false, false},
+	ErrCodeUserTicketNotMatch:          {"成员票据不属于当前应用", "user ticket does not belong to the agent", false, false},
+	ErrCodeSafeModeNotSupported:        {"不支持安全模式", "safe mode not supported", false, false},
+	ErrCodeInvalidChatId:               {"参数 chatid 不合法", "invalid chatid", false, false},
+	ErrCodeChatNotExist:                {"参数 chatid 不存在", "chatid no exist", false, false},
+	ErrCodeInvalidChatName:             {"参数群名不合法", "invalid chat name", false, false},
+	ErrCodeInvalidChatOwner:            {"参数群主不合法", "invalid chat owner", false, false},
+	ErrCodeChatMemberCountLimit:        {"群成员数过多或过少", "chat member count out of limit", false, false},
+	ErrCodeInvalidChatMember:           {"不合法的群成员", "invalid chat member", false, false},
+	ErrCodeNonChatMember:               {"非法操作非自己创建的群", "not owner of the chat", false, false},
+	ErrCodeChatOwnerRequired:           {"需要群主或管理员权限", "owner or admin required", false, false},
+	ErrCodeInvalidReceiverType:         {"参数需要 chatid", "chatid required", false, false},
+	ErrCodeChatReceiverMissing:         {"参数需要 receiver", "receiver required", false, false},
+	ErrCodeInvalidReceiver:             {"参数需要 sender", "sender required", false, false},
+	ErrCodeInvalidMessageContent:       {"参数需要 msg", "msg required", false, false},
+	ErrCodeInvalidSender:               {"参数 sender 不合法", "invalid sender", false, false},
+	ErrCodeInvalidReceiverId:           {"参数 receiver 不合法", "invalid receiver", false, false},
+	ErrCodeInvalidChatMessageType:      {"不合法的消息类型", "invalid msgtype", false, false},
+	ErrCodeInvalidChatMessage:          {"参数 msg 不合法", "invalid msg", false, false},
+	ErrCodeInvalidMessageSize:          {"消息长度超过限制", "msg size out of limit", false, false},
+	ErrCodeSenderNotChatMember:         {"发送者不是群成员", "sender is not a member of the chat", false, false},
+	ErrCodeReceiverNotChatMember:       {"接收者不是群成员", "receiver is not a member of the chat", false, false},
+	ErrCodeInvalidChatOperation:        {"不合法的操作", "invalid operation", false, false},
+	ErrCodeChatMemberExisted:           {"群成员已存在", "chat member existed", false, false},
+	ErrCodeChatMemberNotExist:          {"群成员不存在", "chat member no exist", false, false},
+	ErrCodeChatOwnerCannotQuit:         {"群主不能退群", "owner can not quit the chat", false, false},
+	ErrCodeInvalidMessageId:            {"不合法的消息 id", "invalid msgid", false, false},
+	ErrCodeChatMessageSendFailed:       {"发送消息失败", "send msg failed", true, false},
+}
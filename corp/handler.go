@@ -0,0 +1,30 @@
+package corp
+
+import (
+	"context"
+	"net/http"
+)
+
+// 微信服务器推送过来的一个消息(事件).
+type Request struct {
+	HttpRequest *http.Request
+
+	Timestamp    int64
+	Nonce        string
+	MsgSignature string
+
+	RawMsgXML    []byte        // 解密后的消息(事件)的 XML
+	MixedMessage *MixedMessage // RawMsgXML 解析后的消息
+}
+
+// 处理微信服务器推送过来的消息(事件).
+//  reply 为被动回复的消息(比如 NewResText 的返回值), 加密后写回; reply 为 nil 表示不需要回复.
+type Handler interface {
+	ServeMessage(ctx context.Context, req *Request) (reply interface{}, err error)
+}
+
+type HandlerFunc func(ctx context.Context, req *Request) (reply interface{}, err error)
+
+func (fn HandlerFunc) ServeMessage(ctx context.Context, req *Request) (reply interface{}, err error) {
+	return fn(ctx, req)
+}
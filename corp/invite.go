@@ -5,6 +5,8 @@
 
 package corp
 
+import "context"
+
 // 邀请成员关注
 //  UserId:     用户的userid
 //  InviteTips: 推送到微信上的提示语（只有认证号可以使用）。
@@ -12,6 +14,10 @@ package corp
 //
 //  Type:       1:微信邀请 2.邮件邀请
 func (clt *Client) InviteSend(UserId, InviteTips string) (Type int, err error) {
+	return clt.InviteSendContext(context.Background(), UserId, InviteTips)
+}
+
+func (clt *Client) InviteSendContext(ctx context.Context, UserId, InviteTips string) (Type int, err error) {
 	var request = struct {
 		UserId     string `json:"userid"`
 		InviteTips string `json:"invite_tips"`
@@ -25,8 +31,8 @@ func (clt *Client) InviteSend(UserId, InviteTips string) (Type int, err error) {
 		Type int `json:"type"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/invite/send?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/invite/send?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
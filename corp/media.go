@@ -6,6 +6,7 @@
 package corp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,25 +30,33 @@ type MediaInfo struct {
 
 // 获取上media下载URL, 用于保存到文件服务器
 func (clt *Client) GetMediaDownloadURL(mediaId string) string {
-	token, err := clt.Token()
+	return clt.GetMediaDownloadURLContext(context.Background(), mediaId)
+}
+
+func (clt *Client) GetMediaDownloadURLContext(ctx context.Context, mediaId string) string {
+	token, err := clt.TokenContext(ctx)
 	if err != nil {
 		return ""
 	}
-	uri := "https://qyapi.weixin.qq.com/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
+	uri := clt.apiBaseURL + "/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
 		"&access_token=" + url.QueryEscape(token)
 
 	return uri
 }
 
 func (clt *Client) UploadMediaFromReader(mediaType, filename string, reader io.Reader) (info *MediaInfo, err error) {
+	return clt.UploadMediaFromReaderContext(context.Background(), mediaType, filename, reader)
+}
+
+func (clt *Client) UploadMediaFromReaderContext(ctx context.Context, mediaType, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	var result struct {
 		Error
 		MediaInfo
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/media/upload?type=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/media/upload?type=" +
 		url.QueryEscape(mediaType) + "&access_token="
-	if err = clt.UploadFromReader(incompleteURL, filename, reader, &result); err != nil {
+	if err = clt.UploadFromReaderContext(ctx, incompleteURL, filename, reader, &result); err != nil {
 		return
 	}
 
@@ -61,17 +70,21 @@ func (clt *Client) UploadMediaFromReader(mediaType, filename string, reader io.R
 
 // 下载多媒体到 io.Writer.
 func (clt *Client) DownloadMediaToWriter(mediaId string, writer io.Writer) (err error) {
-	token, err := clt.Token()
+	return clt.DownloadMediaToWriterContext(context.Background(), mediaId, writer)
+}
+
+func (clt *Client) DownloadMediaToWriterContext(ctx context.Context, mediaId string, writer io.Writer) (err error) {
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := "https://qyapi.weixin.qq.com/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
-		"&access_token=" + url.QueryEscape(token)
+	finalURL := clt.apiBaseURL + "/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
+		"&access_token=" + url.QueryEscape(token.Value)
 
-	httpResp, err := clt.httpClient.Get(finalURL)
+	httpResp, err := clt.httpGet(ctx, finalURL)
 	if err != nil {
 		return
 	}
@@ -101,7 +114,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -5,7 +5,10 @@
 
 package corp
 
-import "strconv"
+import (
+	"context"
+	"strconv"
+)
 
 const (
 	MenuButtonCountLimit    = 3 // 一级菜单最多包含 3 个按钮
@@ -141,11 +144,15 @@ func (btn *Button) SetAsLocationSelectButton(name, key string) {
 
 // 创建自定义菜单.
 func (clt *Client) CreateMenu(agentId int64, menu Menu) (err error) {
+	return clt.CreateMenuContext(context.Background(), agentId, menu)
+}
+
+func (clt *Client) CreateMenuContext(ctx context.Context, agentId int64, menu Menu) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/menu/create?agentid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/menu/create?agentid=" +
 		strconv.FormatInt(agentId, 10) + "&access_token="
-	if err = clt.PostJSON(incompleteURL, menu, &result); err != nil {
+	if err = clt.PostJSONContext(ctx, incompleteURL, menu, &result); err != nil {
 		return
 	}
 
@@ -158,11 +165,15 @@ func (clt *Client) CreateMenu(agentId int64, menu Menu) (err error) {
 
 // 删除自定义菜单
 func (clt *Client) DeleteMenu(agentId int64) (err error) {
+	return clt.DeleteMenuContext(context.Background(), agentId)
+}
+
+func (clt *Client) DeleteMenuContext(ctx context.Context, agentId int64) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/menu/delete?agentid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/menu/delete?agentid=" +
 		strconv.FormatInt(agentId, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -175,14 +186,18 @@ func (clt *Client) DeleteMenu(agentId int64) (err error) {
 
 // 获取自定义菜单
 func (clt *Client) GetMenu(agentId int64) (menu Menu, err error) {
+	return clt.GetMenuContext(context.Background(), agentId)
+}
+
+func (clt *Client) GetMenuContext(ctx context.Context, agentId int64) (menu Menu, err error) {
 	var result struct {
 		Error
 		Menu Menu `json:"menu"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/menu/get?agentid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/menu/get?agentid=" +
 		strconv.FormatInt(agentId, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
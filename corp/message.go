@@ -1,6 +1,7 @@
 package corp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -142,46 +143,70 @@ type Result struct {
 }
 
 func (clt *Client) SendText(msg *Text) (r *Result, err error) {
+	return clt.SendTextContext(context.Background(), msg)
+}
+
+func (clt *Client) SendTextContext(ctx context.Context, msg *Text) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) SendImage(msg *Image) (r *Result, err error) {
+	return clt.SendImageContext(context.Background(), msg)
+}
+
+func (clt *Client) SendImageContext(ctx context.Context, msg *Image) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) SendVoice(msg *Voice) (r *Result, err error) {
+	return clt.SendVoiceContext(context.Background(), msg)
+}
+
+func (clt *Client) SendVoiceContext(ctx context.Context, msg *Voice) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) SendVideo(msg *Video) (r *Result, err error) {
+	return clt.SendVideoContext(context.Background(), msg)
+}
+
+func (clt *Client) SendVideoContext(ctx context.Context, msg *Video) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) SendFile(msg *File) (r *Result, err error) {
+	return clt.SendFileContext(context.Background(), msg)
+}
+
+func (clt *Client) SendFileContext(ctx context.Context, msg *File) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) SendNews(msg *News) (r *Result, err error) {
+	return clt.SendNewsContext(context.Background(), msg)
+}
+
+func (clt *Client) SendNewsContext(ctx context.Context, msg *News) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
@@ -189,10 +214,14 @@ func (clt *Client) SendNews(msg *News) (r *Result, err error) {
 	if err = msg.CheckValid(); err != nil {
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) SendMPNews(msg *MPNews) (r *Result, err error) {
+	return clt.SendMPNewsContext(context.Background(), msg)
+}
+
+func (clt *Client) SendMPNewsContext(ctx context.Context, msg *MPNews) (r *Result, err error) {
 	if msg == nil {
 		err = errors.New("nil msg")
 		return
@@ -200,17 +229,21 @@ func (clt *Client) SendMPNews(msg *MPNews) (r *Result, err error) {
 	if err = msg.CheckValid(); err != nil {
 		return
 	}
-	return clt.Send(msg)
+	return clt.SendContext(ctx, msg)
 }
 
 func (clt *Client) Send(msg interface{}) (r *Result, err error) {
+	return clt.SendContext(context.Background(), msg)
+}
+
+func (clt *Client) SendContext(ctx context.Context, msg interface{}) (r *Result, err error) {
 	var result struct {
 		Error
 		Result
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/message/send?access_token="
-	if err = clt.PostJSON(incompleteURL, msg, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/message/send?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
 		return
 	}
 
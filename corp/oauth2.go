@@ -6,6 +6,7 @@
 package corp
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/skynology/go-crypto"
@@ -41,15 +42,19 @@ type AuthUserInfo struct {
 //  code:    通过员工授权获取到的code，每次员工授权带上的code将不一样，
 //           code只能使用一次，5分钟未被使用自动过期
 func (clt *Client) GetUserIdByCode(code string) (info *AuthUserInfo, err error) {
+	return clt.GetUserIdByCodeContext(context.Background(), code)
+}
+
+func (clt *Client) GetUserIdByCodeContext(ctx context.Context, code string) (info *AuthUserInfo, err error) {
 	var result struct {
 		Error
 		AuthUserInfo
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/getuserinfo?code=" + url.QueryEscape(code) +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/getuserinfo?code=" + url.QueryEscape(code) +
 		"&access_token="
 	//fmt.Println("url:", incompleteURL)
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -0,0 +1,120 @@
+package corp
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	var got []string
+	record := func(name string) { got = append(got, name) }
+
+	router := NewRouter()
+	router.OnText(func(ctx context.Context, req *Request, msg *ReqText) (interface{}, error) {
+		record("text:" + msg.Content)
+		return nil, nil
+	})
+	router.OnClick(Key("V1001_TODAY"), func(ctx context.Context, req *Request, event *ClickEvent) (interface{}, error) {
+		record("click:" + event.EventKey)
+		return nil, nil
+	})
+	router.OnClick(KeyPrefix("V1001_"), func(ctx context.Context, req *Request, event *ClickEvent) (interface{}, error) {
+		record("click-prefix:" + event.EventKey)
+		return nil, nil
+	})
+	router.OnScanCodePush(KeyRegexp(regexp.MustCompile(`^scan_\d+$`)), func(ctx context.Context, req *Request, event *ScanCodePushEvent) (interface{}, error) {
+		record("scan:" + event.ScanCodeInfo.ScanResult)
+		return nil, nil
+	})
+	router.OnEnterAgent(func(ctx context.Context, req *Request, event *EnterAgentEvent) (interface{}, error) {
+		record("enter_agent")
+		return nil, nil
+	})
+	router.SetFallback(HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		record("fallback:" + req.MixedMessage.MsgType)
+		return nil, nil
+	}))
+	for _, name := range []string{"mw1", "mw2"} {
+		name := name
+		router.Use(func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+				record(name)
+				return next.ServeMessage(ctx, req)
+			})
+		})
+	}
+
+	scan := &MixedMessage{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeScanCodePush, EventKey: "scan_1"}
+	scan.ScanCodeInfo.ScanResult = "RESULT"
+	messages := []*MixedMessage{
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeText}, Content: "hi"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeClick, EventKey: "V1001_TODAY"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeClick, EventKey: "V1001_GOOD"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeClick, EventKey: "V1002_GOOD"},
+		scan,
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeScanCodePush, EventKey: "scan_x"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeEnterAgent},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeImage}},
+	}
+	for _, msg := range messages {
+		if _, err := router.ServeMessage(context.Background(), &Request{MixedMessage: msg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"mw1", "mw2", "text:hi",
+		"mw1", "mw2", "click:V1001_TODAY",
+		"mw1", "mw2", "click-prefix:V1001_GOOD",
+		"mw1", "mw2", "fallback:event",
+		"mw1", "mw2", "scan:RESULT",
+		"mw1", "mw2", "fallback:event",
+		"mw1", "mw2", "enter_agent",
+		"mw1", "mw2", "fallback:image",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRouterEventFallback(t *testing.T) {
+	var got string
+	router := NewRouter()
+	router.OnClick(Key("V1001_TODAY"), func(ctx context.Context, req *Request, event *ClickEvent) (interface{}, error) {
+		got = "click"
+		return nil, nil
+	})
+	// EventKey 没有匹配上时按照 Event 匹配, 然后是 MsgTypeEvent
+	router.HandleMessage(MsgTypeEvent, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		got = "event:" + req.MixedMessage.Event
+		return nil, nil
+	}))
+	msg := &MixedMessage{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeClick, EventKey: "OTHER"}
+	if _, err := router.ServeMessage(context.Background(), &Request{MixedMessage: msg}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "event:"+EventTypeClick {
+		t.Errorf("got %q", got)
+	}
+	router.HandleEvent(EventTypeClick, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		got = "click-event"
+		return nil, nil
+	}))
+	if _, err := router.ServeMessage(context.Background(), &Request{MixedMessage: msg}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "click-event" {
+		t.Errorf("got %q", got)
+	}
+
+	// 没有任何匹配并且没有 Fallback 时不回复
+	if reply, err := router.ServeMessage(context.Background(), &Request{MixedMessage: &MixedMessage{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeImage}}}); reply != nil || err != nil {
+		t.Errorf("reply = %v, err = %v", reply, err)
+	}
+}
@@ -0,0 +1,308 @@
+package corp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+const (
+	DefaultTimestampTolerance = 5 * time.Minute // 默认允许的 timestamp 和本地时间的误差
+
+	maxRequestBodySize = 1 << 20 // 消息(事件)的 http body 最大 1MB
+)
+
+var (
+	ErrInvalidSignature = errors.New("corp: invalid signature")
+	ErrInvalidTimestamp = errors.New("corp: invalid timestamp")
+	ErrNoAgentHandler   = errors.New("corp: no handler for the agent")
+)
+
+// 处理 Server 的错误, 比如签名错误, 消息格式错误, Handler 返回的错误.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// 默认的 ErrorHandler, 请求本身有问题(签名错误, 格式错误, 没有对应应用的 Handler 等)返回 400, 其他错误返回 500.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	var badReq *badRequestError
+	if errors.As(err, &badReq) {
+		status = http.StatusBadRequest
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+type badRequestError struct {
+	err error
+}
+
+func (e *badRequestError) Error() string { return e.err.Error() }
+func (e *badRequestError) Unwrap() error { return e.err }
+
+func badRequest(err error) error {
+	return &badRequestError{err: err}
+}
+
+// 接收微信服务器推送的企业号消息(事件)的 http.Handler, 用法:
+//
+//    srv, err := corp.NewServer(corpId, token, encodedAESKey)
+//    if err != nil {
+//        ...
+//    }
+//    srv.Handle(1, router)            // 应用 1 的消息(事件)交给 router 处理
+//    srv.SetDefaultHandler(fallback) // 其他应用的消息(事件)
+//    http.Handle("/wechat/corp/callback", srv)
+//
+//  NOTE:
+//  1. 企业号的回调都是加密的, GET 请求用于验证回调 URL, 校验 msg_signature 后解密 echostr 并返回明文;
+//  2. POST 请求校验 msg_signature, 解密 Encrypt 字段并校验 CorpID, 然后按照消息的 AgentID 交给对应的 Handler,
+//     被动回复的消息也加密;
+//  3. 同时校验 timestamp 和本地时间的误差, 默认为 DefaultTimestampTolerance.
+type Server struct {
+	corpId string
+	token  string
+	aesKey [32]byte
+
+	mu             sync.RWMutex
+	agentHandlers  map[int64]Handler
+	defaultHandler Handler
+
+	errorHandler       ErrorHandler
+	timestampTolerance time.Duration
+}
+
+// encodedAESKey 为回调模式的 EncodingAESKey(43 个字符).
+func NewServer(corpId, token, encodedAESKey string) (srv *Server, err error) {
+	key, err := util.AESKeyDecode(encodedAESKey)
+	if err != nil {
+		return
+	}
+	if len(key) != 32 {
+		err = fmt.Errorf("the length of AES key must be equal to 32, now is %d", len(key))
+		return
+	}
+
+	srv = &Server{
+		corpId:             corpId,
+		token:              token,
+		agentHandlers:      make(map[int64]Handler),
+		errorHandler:       DefaultErrorHandler,
+		timestampTolerance: DefaultTimestampTolerance,
+	}
+	copy(srv.aesKey[:], key)
+	return
+}
+
+// 设置应用 agentId 的 Handler, handler 为 nil 时删除.
+func (srv *Server) Handle(agentId int64, handler Handler) {
+	srv.mu.Lock()
+	if handler == nil {
+		delete(srv.agentHandlers, agentId)
+	} else {
+		srv.agentHandlers[agentId] = handler
+	}
+	srv.mu.Unlock()
+}
+
+// 设置没有通过 Handle 注册的应用的 Handler, 默认为 nil,
+// 这时没有对应 Handler 的消息(事件)交给 ErrorHandler 处理, 错误为 ErrNoAgentHandler.
+func (srv *Server) SetDefaultHandler(handler Handler) {
+	srv.mu.Lock()
+	srv.defaultHandler = handler
+	srv.mu.Unlock()
+}
+
+// 设置处理错误的 ErrorHandler, 默认为 DefaultErrorHandler.
+func (srv *Server) SetErrorHandler(handler ErrorHandler) {
+	if handler == nil {
+		handler = DefaultErrorHandler
+	}
+	srv.errorHandler = handler
+}
+
+// 设置允许的 timestamp 和本地时间的误差, 默认为 DefaultTimestampTolerance; <= 0 表示不校验.
+func (srv *Server) SetTimestampTolerance(d time.Duration) {
+	srv.timestampTolerance = d
+}
+
+func (srv *Server) agentHandler(agentId int64) Handler {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+
+	if handler, ok := srv.agentHandlers[agentId]; ok {
+		return handler
+	}
+	return srv.defaultHandler
+}
+
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		srv.serveEcho(w, r)
+	case http.MethodPost:
+		srv.serveMessage(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// 验证回调 URL, 返回解密后的 echostr
+func (srv *Server) serveEcho(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	echoStr := query.Get("echostr")
+	if _, err := srv.checkSignature(query.Get("msg_signature"), query.Get("timestamp"), query.Get("nonce"), echoStr); err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+	echo, err := srv.decrypt(echoStr)
+	if err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+	w.Write(echo)
+}
+
+func (srv *Server) serveMessage(w http.ResponseWriter, r *http.Request) {
+	req, err := srv.parseRequest(r)
+	if err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+
+	handler := srv.agentHandler(req.MixedMessage.AgentId)
+	if handler == nil {
+		srv.errorHandler(w, r, badRequest(fmt.Errorf("%w: %d", ErrNoAgentHandler, req.MixedMessage.AgentId)))
+		return
+	}
+	reply, err := handler.ServeMessage(r.Context(), req)
+	if err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+	if err = srv.writeReply(w, req, reply); err != nil {
+		srv.errorHandler(w, r, err)
+	}
+}
+
+// 校验 msg_signature 和时间戳, 返回解析后的 timestamp
+func (srv *Server) checkSignature(signature, timestampStr, nonce, encryptedMsg string) (timestamp int64, err error) {
+	timestamp, err = strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		err = badRequest(ErrInvalidTimestamp)
+		return
+	}
+	if srv.timestampTolerance > 0 {
+		d := time.Since(time.Unix(timestamp, 0))
+		if d > srv.timestampTolerance || d < -srv.timestampTolerance {
+			err = badRequest(ErrInvalidTimestamp)
+			return
+		}
+	}
+	// 常量时间比较签名, 避免计时攻击
+	want := util.MsgSign(srv.token, timestampStr, nonce, encryptedMsg)
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(want)) != 1 {
+		err = badRequest(ErrInvalidSignature)
+	}
+	return
+}
+
+// 解密 base64 编码的密文, 同时校验 CorpID
+func (srv *Server) decrypt(base64EncryptedMsg string) (rawMsg []byte, err error) {
+	encryptedMsg, err := base64.StdEncoding.DecodeString(base64EncryptedMsg)
+	if err != nil {
+		err = badRequest(err)
+		return
+	}
+	if _, rawMsg, err = util.AESDecryptMsg(encryptedMsg, srv.corpId, srv.aesKey); err != nil {
+		err = badRequest(err)
+	}
+	return
+}
+
+// 回调 http body 的格式
+type requestHttpBody struct {
+	XMLName      struct{} `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	AgentId      int64    `xml:"AgentID"`
+	EncryptedMsg string   `xml:"Encrypt"`
+}
+
+func (srv *Server) parseRequest(r *http.Request) (req *Request, err error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+	if err != nil {
+		return
+	}
+	if len(body) > maxRequestBodySize {
+		err = badRequest(errors.New("corp: request body too large"))
+		return
+	}
+
+	var httpBody requestHttpBody
+	if err = xml.Unmarshal(body, &httpBody); err != nil {
+		err = badRequest(err)
+		return
+	}
+
+	query := r.URL.Query()
+	req = &Request{
+		HttpRequest:  r,
+		Nonce:        query.Get("nonce"),
+		MsgSignature: query.Get("msg_signature"),
+	}
+	if req.Timestamp, err = srv.checkSignature(req.MsgSignature, query.Get("timestamp"), req.Nonce, httpBody.EncryptedMsg); err != nil {
+		return
+	}
+	if req.RawMsgXML, err = srv.decrypt(httpBody.EncryptedMsg); err != nil {
+		return
+	}
+
+	var msg MixedMessage
+	if err = xml.Unmarshal(req.RawMsgXML, &msg); err != nil {
+		err = badRequest(err)
+		return
+	}
+	req.MixedMessage = &msg
+	return
+}
+
+// 加密后写回被动回复的消息, reply 为 nil 时回复空串
+func (srv *Server) writeReply(w http.ResponseWriter, req *Request, reply interface{}) (err error) {
+	if reply == nil {
+		return
+	}
+
+	random := make([]byte, 16)
+	if _, err = rand.Read(random); err != nil {
+		return
+	}
+	param := &RequestParam{
+		AppId:     srv.corpId,
+		Random:    random,
+		Timestamp: time.Now().Unix(),
+		Token:     srv.token,
+		AESKey:    srv.aesKey,
+		Nonce:     req.Nonce,
+	}
+	httpBody, err := GetResponse(param, reply)
+	if err != nil {
+		return
+	}
+	body, err := xml.Marshal(&httpBody)
+	if err != nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	_, err = w.Write(body)
+	return
+}
@@ -0,0 +1,110 @@
+package corp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+const (
+	testCorpId = "wx_test_corpid"
+	testToken  = "test_token"
+	testAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+func testEncrypt(t *testing.T, corpId, msg string) string {
+	aesKey, err := util.AESKeyDecode(testAESKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var key [32]byte
+	copy(key[:], aesKey)
+	return base64.StdEncoding.EncodeToString(util.AESEncryptMsg([]byte("0123456789abcdef"), []byte(msg), corpId, key))
+}
+
+func testQuery(encrypted string) url.Values {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return url.Values{
+		"timestamp":     {timestamp},
+		"nonce":         {"nonce"},
+		"msg_signature": {util.MsgSign(testToken, timestamp, "nonce", encrypted)},
+	}
+}
+
+func TestServer(t *testing.T) {
+	srv, err := NewServer(testCorpId, testToken, testAESKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Handle(1, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		msg := req.MixedMessage
+		return NewResText(msg.FromUserName, msg.ToUserName, msg.CreateTime, "re: "+msg.Content), nil
+	}))
+
+	// 验证回调 URL
+	echo := testEncrypt(t, testCorpId, "echo123")
+	query := testQuery(echo)
+	query.Set("echostr", echo)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", "/?"+query.Encode(), nil))
+	if w.Code != http.StatusOK || w.Body.String() != "echo123" {
+		t.Errorf("echo = %d %q", w.Code, w.Body.String())
+	}
+
+	post := func(corpId string, agentId int) *httptest.ResponseRecorder {
+		msg := `<xml><ToUserName><![CDATA[` + testCorpId + `]]></ToUserName><FromUserName><![CDATA[userid]]></FromUserName>` +
+			`<CreateTime>1348831860</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hello]]></Content>` +
+			`<MsgId>1234567890123456</MsgId><AgentID>` + strconv.Itoa(agentId) + `</AgentID></xml>`
+		encrypted := testEncrypt(t, corpId, msg)
+		body := `<xml><ToUserName><![CDATA[` + testCorpId + `]]></ToUserName><AgentID>` + strconv.Itoa(agentId) +
+			`</AgentID><Encrypt><![CDATA[` + encrypted + `]]></Encrypt></xml>`
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+testQuery(encrypted).Encode(), strings.NewReader(body)))
+		return w
+	}
+
+	w = post(testCorpId, 1)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var resp ResponseHttpBody
+	if err = xml.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.MsgSignature != util.MsgSign(testToken, strconv.FormatInt(resp.TimeStamp, 10), resp.Nonce, resp.EncryptedMsg) {
+		t.Error("invalid reply msg_signature")
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(resp.EncryptedMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rawXML, err := util.AESDecryptMsg(cipherText, testCorpId, srv.aesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reply ResText
+	if err = xml.Unmarshal(rawXML, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Content != "re: hello" || reply.ToUserName != "userid" {
+		t.Errorf("reply = %+v", reply)
+	}
+
+	// 没有对应应用的 Handler
+	if w = post(testCorpId, 2); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+	// CorpID 不匹配
+	if w = post("wx_other_corpid", 1); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
@@ -6,12 +6,17 @@
 package corp
 
 import (
+	"context"
 	"strconv"
 	"strings"
 )
 
 // 创建标签
 func (clt *Client) TagCreate(tagName string) (id int64, err error) {
+	return clt.TagCreateContext(context.Background(), tagName)
+}
+
+func (clt *Client) TagCreateContext(ctx context.Context, tagName string) (id int64, err error) {
 	var request = struct {
 		TagName string `json:"tagname"`
 	}{
@@ -23,8 +28,8 @@ func (clt *Client) TagCreate(tagName string) (id int64, err error) {
 		TagId int64 `json:"tagid"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -38,6 +43,10 @@ func (clt *Client) TagCreate(tagName string) (id int64, err error) {
 
 // 更新标签名字
 func (clt *Client) TagUpdate(id int64, name string) (err error) {
+	return clt.TagUpdateContext(context.Background(), id, name)
+}
+
+func (clt *Client) TagUpdateContext(ctx context.Context, id int64, name string) (err error) {
 	var request = struct {
 		TagId   int64  `json:"tagid"`
 		TagName string `json:"tagname"`
@@ -48,8 +57,8 @@ func (clt *Client) TagUpdate(id int64, name string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/update?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -62,11 +71,15 @@ func (clt *Client) TagUpdate(id int64, name string) (err error) {
 
 // 删除标签
 func (clt *Client) TagDelete(id int64) (err error) {
+	return clt.TagDeleteContext(context.Background(), id)
+}
+
+func (clt *Client) TagDeleteContext(ctx context.Context, id int64) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/delete?tagid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/delete?tagid=" +
 		strconv.FormatInt(id, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -79,15 +92,19 @@ func (clt *Client) TagDelete(id int64) (err error) {
 
 // 获取标签成员
 func (clt *Client) TagInfo(id int64) (userList []UserBaseInfo, departmentList []int64, err error) {
+	return clt.TagInfoContext(context.Background(), id)
+}
+
+func (clt *Client) TagInfoContext(ctx context.Context, id int64) (userList []UserBaseInfo, departmentList []int64, err error) {
 	var result struct {
 		Error
 		UserList       []UserBaseInfo `json:"userlist"`
 		DepartmentList []int64        `json:"partylist"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/get?tagid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/get?tagid=" +
 		strconv.FormatInt(id, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -103,6 +120,11 @@ func (clt *Client) TagInfo(id int64) (userList []UserBaseInfo, departmentList []
 // 增加标签成员
 func (clt *Client) TagAddUser(id int64, userList []string,
 	departmentList []int64) (invalidUserList []string, invalidDepartmentList []int64, err error) {
+	return clt.TagAddUserContext(context.Background(), id, userList, departmentList)
+}
+
+func (clt *Client) TagAddUserContext(ctx context.Context, id int64, userList []string,
+	departmentList []int64) (invalidUserList []string, invalidDepartmentList []int64, err error) {
 
 	if len(userList) <= 0 && len(departmentList) <= 0 {
 		return
@@ -124,8 +146,8 @@ func (clt *Client) TagAddUser(id int64, userList []string,
 		InvalidDepartmentList []int64 `json:"invalidparty"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/addtagusers?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/addtagusers?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -149,6 +171,11 @@ func (clt *Client) TagAddUser(id int64, userList []string,
 // 删除标签成员
 func (clt *Client) TagDeleteUser(id int64, userList []string,
 	departmentList []int64) (invalidUserList []string, invalidDepartmentList []int64, err error) {
+	return clt.TagDeleteUserContext(context.Background(), id, userList, departmentList)
+}
+
+func (clt *Client) TagDeleteUserContext(ctx context.Context, id int64, userList []string,
+	departmentList []int64) (invalidUserList []string, invalidDepartmentList []int64, err error) {
 
 	if len(userList) <= 0 && len(departmentList) <= 0 {
 		return
@@ -170,8 +197,8 @@ func (clt *Client) TagDeleteUser(id int64, userList []string,
 		InvalidDepartmentList []int64 `json:"invalidparty"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/deltagusers?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/deltagusers?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -199,13 +226,17 @@ type Tag struct {
 
 // 获取标签列表
 func (clt *Client) TagList() (list []Tag, err error) {
+	return clt.TagListContext(context.Background())
+}
+
+func (clt *Client) TagListContext(ctx context.Context) (list []Tag, err error) {
 	var result struct {
 		Error
 		TagList []Tag `json:"taglist,omitempty"`
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/tag/list?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tag/list?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
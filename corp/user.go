@@ -6,6 +6,7 @@
 package corp
 
 import (
+	"context"
 	"errors"
 	"strconv"
 )
@@ -31,6 +32,10 @@ type UserCreateParameters struct {
 
 // 创建成员
 func (clt *Client) UserCreate(para *UserCreateParameters) (err error) {
+	return clt.UserCreateContext(context.Background(), para)
+}
+
+func (clt *Client) UserCreateContext(ctx context.Context, para *UserCreateParameters) (err error) {
 	if para == nil {
 		err = errors.New("nil parameters")
 		return
@@ -38,8 +43,8 @@ func (clt *Client) UserCreate(para *UserCreateParameters) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/create?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -74,6 +79,10 @@ func (para *UserUpdateParameters) SetEnable(b bool) {
 
 // 更新成员
 func (clt *Client) UserUpdate(para *UserUpdateParameters) (err error) {
+	return clt.UserUpdateContext(context.Background(), para)
+}
+
+func (clt *Client) UserUpdateContext(ctx context.Context, para *UserUpdateParameters) (err error) {
 	if para == nil {
 		err = errors.New("nil parameters")
 		return
@@ -81,8 +90,8 @@ func (clt *Client) UserUpdate(para *UserUpdateParameters) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/update?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -95,11 +104,15 @@ func (clt *Client) UserUpdate(para *UserUpdateParameters) (err error) {
 
 // 删除成员
 func (clt *Client) UserDelete(userId string) (err error) {
+	return clt.UserDeleteContext(context.Background(), userId)
+}
+
+func (clt *Client) UserDeleteContext(ctx context.Context, userId string) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/delete?userid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/delete?userid=" +
 		userId + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -112,6 +125,10 @@ func (clt *Client) UserDelete(userId string) (err error) {
 
 // 批量删除成员
 func (clt *Client) UserBatchDelete(UserIdList []string) (err error) {
+	return clt.UserBatchDeleteContext(context.Background(), UserIdList)
+}
+
+func (clt *Client) UserBatchDeleteContext(ctx context.Context, UserIdList []string) (err error) {
 	if len(UserIdList) <= 0 {
 		return
 	}
@@ -124,8 +141,8 @@ func (clt *Client) UserBatchDelete(UserIdList []string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/batchdelete?access_token="
-	if err = clt.PostJSON(incompleteURL, request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/batchdelete?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, request, &result); err != nil {
 		return
 	}
 
@@ -152,14 +169,18 @@ type UserInfo struct {
 }
 
 func (clt *Client) UserInfo(userId string) (info *UserInfo, err error) {
+	return clt.UserInfoContext(context.Background(), userId)
+}
+
+func (clt *Client) UserInfoContext(ctx context.Context, userId string) (info *UserInfo, err error) {
 	var result struct {
 		Error
 		UserInfo
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/get?userid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/get?userid=" +
 		userId + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -183,6 +204,11 @@ type UserBaseInfo struct {
 //                status可叠加（可用逻辑运算符 | 来叠加, 一般都是后面 3 个叠加）。
 func (clt *Client) UserSimpleList(departmentId int64,
 	fetchChild bool, status int) (UserList []UserBaseInfo, err error) {
+	return clt.UserSimpleListContext(context.Background(), departmentId, fetchChild, status)
+}
+
+func (clt *Client) UserSimpleListContext(ctx context.Context, departmentId int64,
+	fetchChild bool, status int) (UserList []UserBaseInfo, err error) {
 
 	var result struct {
 		Error
@@ -196,12 +222,12 @@ func (clt *Client) UserSimpleList(departmentId int64,
 		fetchChildStr = "0"
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/simplelist" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/simplelist" +
 		"?department_id=" + strconv.FormatInt(departmentId, 10) +
 		"&fetch_child=" + fetchChildStr +
 		"&status=" + strconv.FormatInt(int64(status), 10) +
 		"&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -220,6 +246,11 @@ func (clt *Client) UserSimpleList(departmentId int64,
 //                status可叠加（可用逻辑运算符 | 来叠加, 一般都是后面 3 个叠加）。
 func (clt *Client) UserList(departmentId int64,
 	fetchChild bool, status int) (UserList []UserInfo, err error) {
+	return clt.UserListContext(context.Background(), departmentId, fetchChild, status)
+}
+
+func (clt *Client) UserListContext(ctx context.Context, departmentId int64,
+	fetchChild bool, status int) (UserList []UserInfo, err error) {
 
 	var result struct {
 		Error
@@ -233,12 +264,12 @@ func (clt *Client) UserList(departmentId int64,
 		fetchChildStr = "0"
 	}
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/list" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/list" +
 		"?department_id=" + strconv.FormatInt(departmentId, 10) +
 		"&fetch_child=" + fetchChildStr +
 		"&status=" + strconv.FormatInt(int64(status), 10) +
 		"&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -6,6 +6,7 @@
 package corp
 
 import (
+	"context"
 	"strconv"
 )
 
@@ -17,11 +18,15 @@ import (
 //
 //  企业在员工验证成功后，调用如下接口即可让员工关注成功。
 func (clt *Client) UserAuthSuccess(userId int64) (err error) {
+	return clt.UserAuthSuccessContext(context.Background(), userId)
+}
+
+func (clt *Client) UserAuthSuccessContext(ctx context.Context, userId int64) (err error) {
 	var result Error
 
-	incompleteURL := "https://qyapi.weixin.qq.com/cgi-bin/user/authsucc?userid=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/authsucc?userid=" +
 		strconv.FormatInt(userId, 10) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
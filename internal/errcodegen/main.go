@@ -0,0 +1,148 @@
+// errcodegen 根据错误码目录文件生成错误码常量, 用于 errors.Is 的错误变量和错误码说明表.
+//
+//  目录文件每行一个错误码, 字段之间用 '|' 分隔:
+//
+//    错误码 | 名称 | 标记 | 中文说明 | 英文说明
+//
+//  标记: T 表示临时性错误(稍后重试可能成功), R 表示刷新 access_token 后可以重试, - 表示没有标记;
+//  以 '#' 开头的行和空行忽略.
+//
+//  用法(在 package 目录里通过 go generate 调用):
+//
+//    go run ../internal/errcodegen -pkg mp -type int -in errcode.txt -out errcode.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type entry struct {
+	Code  string
+	Name  string
+	Flags string
+	ZH    string
+	EN    string
+}
+
+func main() {
+	pkg := flag.String("pkg", "", "package name")
+	typ := flag.String("type", "int", "type of error code: int or string")
+	in := flag.String("in", "errcode.txt", "input file")
+	out := flag.String("out", "errcode.go", "output file")
+	flag.Parse()
+
+	if *pkg == "" {
+		log.Fatal("-pkg is required")
+	}
+	if *typ != "int" && *typ != "string" {
+		log.Fatal("-type must be int or string")
+	}
+
+	entries, err := parse(*in, *typ)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(*pkg, *typ, *in, entries)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parse(filename, typ string) (entries []entry, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	names := make(map[string]bool)
+	codes := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) != 5 {
+			err = fmt.Errorf("%s:%d: want 5 fields, have %d", filename, lineNo, len(fields))
+			return
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		e := entry{Code: fields[0], Name: fields[1], Flags: fields[2], ZH: fields[3], EN: fields[4]}
+		if typ == "int" {
+			if _, err = strconv.Atoi(e.Code); err != nil {
+				err = fmt.Errorf("%s:%d: invalid code %q", filename, lineNo, e.Code)
+				return
+			}
+		} else {
+			e.Code = strconv.Quote(e.Code)
+		}
+		if strings.Trim(e.Flags, "TR-") != "" {
+			err = fmt.Errorf("%s:%d: invalid flags %q", filename, lineNo, e.Flags)
+			return
+		}
+		if names[e.Name] {
+			err = fmt.Errorf("%s:%d: duplicate name %s", filename, lineNo, e.Name)
+			return
+		}
+		if codes[e.Code] {
+			err = fmt.Errorf("%s:%d: duplicate code %s", filename, lineNo, e.Code)
+			return
+		}
+		names[e.Name] = true
+		codes[e.Code] = true
+		entries = append(entries, e)
+	}
+	err = scanner.Err()
+	return
+}
+
+func generate(pkg, typ, in string, entries []entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by errcodegen from %s; DO NOT EDIT.\n\n", in)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	buf.WriteString("const (\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\tErrCode%s = %s // %s\n", e.Name, e.Code, e.ZH)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// 可以用 errors.Is(err, ErrXxx) 判断错误码\n")
+	buf.WriteString("var (\n")
+	for _, e := range entries {
+		if typ == "int" {
+			fmt.Fprintf(&buf, "\tErr%s = &Error{ErrCode: ErrCode%s, ErrMsg: %q}\n", e.Name, e.Name, e.EN)
+		} else {
+			fmt.Fprintf(&buf, "\tErr%s = &Error{ErrCode: ErrCode%s, ErrCodeDes: %q}\n", e.Name, e.Name, e.EN)
+		}
+	}
+	buf.WriteString(")\n\n")
+
+	fmt.Fprintf(&buf, "var errCodeInfos = map[%s]errCodeInfo{\n", typ)
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\tErrCode%s: {%q, %q, %t, %t},\n", e.Name, e.ZH, e.EN,
+			strings.Contains(e.Flags, "T"), strings.Contains(e.Flags, "R"))
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -2,23 +2,35 @@ package pay
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/skynology/wechat/util"
 )
 
+const DefaultAPIBaseURL = "https://api.mch.weixin.qq.com"
+
 type Client struct {
 	appId      string
 	mchId      string
 	apiKey     string
+	signType   string
+	apiBaseURL string
+	sandbox    bool
 	httpClient *http.Client
+
+	retryPolicy        *util.RetryPolicy
+	retryNonIdempotent bool
+	interceptors       []util.Interceptor
 }
 
 func (cli *Client) SetHttpClient(c *http.Client) {
@@ -32,10 +44,82 @@ func NewClient(appId, mchId, apiKey string) *Client {
 		appId:      appId,
 		mchId:      mchId,
 		apiKey:     apiKey,
+		apiBaseURL: DefaultAPIBaseURL,
 		httpClient: http.DefaultClient,
 	}
 }
 
+// 设置接口的域名(替换 DefaultAPIBaseURL), 比如指向本地的测试桩, 就近的接入点或者出口代理.
+func (clt *Client) SetBaseURL(apiBaseURL string) {
+	clt.apiBaseURL = strings.TrimSuffix(apiBaseURL, "/")
+}
+
+// 设置是否使用仿真测试系统, 开启后所有接口的路径都加上 /sandboxnew 前缀.
+//  NOTE: 仿真测试系统要用 /sandboxnew/pay/getsignkey 获取的沙箱密钥作为 apiKey.
+func (clt *Client) SetSandbox(sandbox bool) {
+	clt.sandbox = sandbox
+}
+
+// 设置请求的签名类型, 默认为 SignTypeMD5.
+//  设置为 SignTypeHMACSHA256 后, 没有设置 sign_type 的请求会自动加上 sign_type 并且重新签名;
+//  返回结果和通知按照各自声明的 sign_type 校验签名, 没有声明时返回结果和请求的签名类型一致, 通知为 MD5.
+//  NOTE: 企业付款等不支持 HMAC-SHA256 的接口不受影响.
+func (clt *Client) SetSignType(signType string) (err error) {
+	if signType == "" {
+		signType = SignTypeMD5
+	}
+	if !isValidSignType(signType) {
+		err = fmt.Errorf("unsupported sign_type: %q", signType)
+		return
+	}
+	clt.signType = signType
+	return
+}
+
+// 设置重试策略, 默认不重试.
+//  设置后网络错误, http 5xx, 临时性的错误码(比如 SYSTEMERROR)会按照策略用相同的参数重试, 如:
+//    clt.SetRetryPolicy(&util.DefaultRetryPolicy)
+//  NOTE: 非幂等的接口(申请退款, 企业付款, 发红包, 刷卡支付)默认不重试, 见 SetRetryNonIdempotent.
+func (clt *Client) SetRetryPolicy(policy *util.RetryPolicy) {
+	clt.retryPolicy = policy
+}
+
+// 设置非幂等的接口(申请退款, 企业付款, 发红包, 刷卡支付)是否也按照重试策略重试.
+//  NOTE: 只有在确保每次重试使用相同的商户单号(out_refund_no, partner_trade_no, mch_billno, out_trade_no)时才能开启,
+//  否则可能重复退款或者付款.
+func (clt *Client) SetRetryNonIdempotent(b bool) {
+	clt.retryNonIdempotent = b
+}
+
+// 非幂等的接口, 重复请求可能导致重复退款或者付款
+var nonIdempotentPaths = []string{
+	"/secapi/pay/refund",
+	"/pay/micropay",
+	"/mmpaymkttransfers/promotion/transfers",
+	"/mmpaymkttransfers/sendredpack",
+	"/mmpaymkttransfers/sendgroupredpack",
+}
+
+func isIdempotent(url string) bool {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		url = url[:i]
+	}
+	for _, path := range nonIdempotentPaths {
+		if strings.HasSuffix(url, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// 接口的完整 URL, path 如 "/pay/unifiedorder"
+func (clt *Client) apiURL(path string) string {
+	if clt.sandbox {
+		return clt.apiBaseURL + "/sandboxnew" + path
+	}
+	return clt.apiBaseURL + path
+}
+
 // NewTLSHttpClient 创建支持双向证书认证的 http.Client
 func NewTLSHttpClient(certFile, keyFile string) (httpClient *http.Client, err error) {
 	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
@@ -61,77 +145,100 @@ func NewTLSHttpClient(certFile, keyFile string) (httpClient *http.Client, err er
 	return
 }
 
-// 微信支付通用请求方法.
+// 设置拦截器, 每一个发往微信支付服务器的请求都会依次经过这些拦截器, 用于记录日志, 统计指标, 链路追踪等, 如:
+//    metrics := util.NewMetrics("wechat_pay", nil)
+//    clt.SetInterceptors(util.NewLogInterceptor(slog.Default(), false), metrics.Interceptor())
+func (clt *Client) SetInterceptors(interceptors ...util.Interceptor) {
+	clt.interceptors = interceptors
+}
+
+// 发送请求, 经过 SetInterceptors 设置的拦截器.
+func (clt *Client) do(httpReq *http.Request) (*http.Response, error) {
+	return util.DoHTTP(clt.httpClient, clt.interceptors, httpReq)
+}
+
+// 发送 POST 请求, ctx 用于控制请求的取消和超时.
+func (clt *Client) httpPost(ctx context.Context, url, bodyType string, body io.Reader) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", bodyType)
+	return clt.do(httpReq)
+}
+
+// 微信支付通用请求方法, 用于返回结果没有签名的接口(比如企业付款), 不校验返回结果的签名.
+//  请求同 PostXML 自动填写 appid, mch_id, nonce_str 和 sign.
 //  注意: err == nil 表示协议状态都为 SUCCESS.
 func (clt *Client) PostXMLWithoutSign(url string, request interface{}) (resp map[string]string, err error) {
-	b, err := xml.Marshal(request)
+	return clt.PostXMLWithoutSignContext(context.Background(), url, request)
+}
+
+// 同 PostXMLWithoutSign, ctx 用于控制请求的取消和超时.
+func (clt *Client) PostXMLWithoutSignContext(ctx context.Context, url string, request interface{}) (resp map[string]string, err error) {
+	b, _, err := clt.marshalRequest(url, request)
 	if err != nil {
 		return
 	}
 
-	// fmt.Println(string(b))
+	return clt.postXMLWithRetry(ctx, url, b, "")
+}
 
-	httpResp, err := clt.httpClient.Post(url, "text/xml; charset=utf-8", bytes.NewReader(b))
+// 微信支付通用请求方法.
+//  request 为请求的 struct 或者 map[string]string, appid, mch_id, nonce_str 和 sign 为空时自动填写;
+//  缺少接口的必填参数时不发送请求, 返回 ErrIncompleteRequest.
+//  注意: err == nil 表示协议状态都为 SUCCESS.
+func (clt *Client) PostXML(url string, request interface{}) (resp map[string]string, err error) {
+	return clt.PostXMLContext(context.Background(), url, request)
+}
+
+// 同 PostXML, ctx 用于控制请求的取消和超时.
+//  NOTE: 所有高层次的封装方法都有对应的 XxxContext 版本, 原来的方法等价于传入 context.Background().
+func (clt *Client) PostXMLContext(ctx context.Context, url string, request interface{}) (resp map[string]string, err error) {
+	b, signType, err := clt.marshalRequest(url, request)
 	if err != nil {
 		return
 	}
-	defer httpResp.Body.Close()
 
-	if httpResp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("http.Status: %s", httpResp.Status)
-		return
-	}
+	return clt.postXMLWithRetry(ctx, url, b, signType)
+}
 
-	if resp, err = util.ParseXMLToMap(httpResp.Body); err != nil {
-		return
+// 按照重试策略 POST body 到 url, 业务结果为 FAIL 并且 err_code 是临时性的错误(比如 SYSTEMERROR)时也会重试;
+// 最终业务结果仍然为 FAIL 时和原来一样返回 resp 和 nil error, 由调用者根据 result_code 判断.
+//  signType 为请求的签名类型, 为 "" 时不校验返回结果的签名.
+func (clt *Client) postXMLWithRetry(ctx context.Context, url string, body []byte, signType string) (resp map[string]string, err error) {
+	policy := clt.retryPolicy
+	if !clt.retryNonIdempotent && !isIdempotent(url) {
+		policy = nil
 	}
-
-	// 判断协议状态
-	ReturnCode, ok := resp["return_code"]
-	if !ok {
-		err = errors.New("no return_code parameter")
-		return
+	if policy == nil {
+		return clt.postXML(ctx, url, body, signType)
 	}
-	if ReturnCode != ReturnCodeSuccess {
-		err = &Error{
-			ReturnCode: ReturnCode,
-			ReturnMsg:  resp["return_msg"],
+
+	err = policy.Do(ctx, func() (err error) {
+		if resp, err = clt.postXML(ctx, url, body, signType); err != nil {
+			return
+		}
+		if resp["result_code"] == ResultCodeFail {
+			return &ResultError{ErrCode: resp["err_code"], ErrCodeDes: resp["err_code_des"]}
 		}
 		return
+	})
+	if _, ok := err.(*ResultError); ok {
+		err = nil
 	}
-
-	// // 认证签名
-	// signature1, ok := resp["sign"]
-	// if !ok {
-	// 	err = errors.New("no sign parameter")
-	// 	return
-	// }
-	// signature2 := clt.Sign(resp)
-	// if signature1 != signature2 {
-	// 	err = fmt.Errorf("check signature failed, \r\ninput: %q, \r\nlocal: %q", signature1, signature2)
-	// 	return
-	// }
 	return
 }
 
-// 微信支付通用请求方法.
-//  注意: err == nil 表示协议状态都为 SUCCESS.
-func (clt *Client) PostXML(url string, request interface{}) (resp map[string]string, err error) {
-	b, err := xml.Marshal(request)
-	if err != nil {
-		return
-	}
-
-	fmt.Println(string(b))
-
-	httpResp, err := clt.httpClient.Post(url, "text/xml; charset=utf-8", bytes.NewReader(b))
+func (clt *Client) postXML(ctx context.Context, url string, body []byte, signType string) (resp map[string]string, err error) {
+	httpResp, err := clt.httpPost(ctx, url, "text/xml; charset=utf-8", bytes.NewReader(body))
 	if err != nil {
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("http.Status: %s", httpResp.Status)
+		err = &util.HTTPError{StatusCode: httpResp.StatusCode, Status: httpResp.Status}
 		return
 	}
 
@@ -149,48 +256,50 @@ func (clt *Client) PostXML(url string, request interface{}) (resp map[string]str
 		err = &Error{
 			ReturnCode: ReturnCode,
 			ReturnMsg:  resp["return_msg"],
+			ErrCode:    resp["err_code"],
+			ErrCodeDes: resp["err_code_des"],
 		}
 		return
 	}
 
-	// 认证签名
-	signature1, ok := resp["sign"]
-	if !ok {
-		err = errors.New("no sign parameter")
-		return
-	}
-	signature2 := clt.Sign(resp)
-	if signature1 != signature2 {
-		err = fmt.Errorf("check signature failed, \r\ninput: %q, \r\nlocal: %q", signature1, signature2)
+	if signType == "" {
 		return
 	}
+
+	// 认证签名
+	err = clt.checkSign(resp, signType)
 	return
 }
 
 // 测速上报.
 func (clt *Client) Report(req map[string]string) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/payitil/report", req)
+	return clt.ReportContext(context.Background(), req)
+}
+
+func (clt *Client) ReportContext(ctx context.Context, req map[string]string) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/payitil/report"), req)
 }
 
 // 下载对账单.
 func (clt *Client) DownloadBill(req map[string]string) (data []byte, err error) {
-	bodyBuf := textBufferPool.Get().(*bytes.Buffer)
-	bodyBuf.Reset()
-	defer textBufferPool.Put(bodyBuf)
+	return clt.DownloadBillContext(context.Background(), req)
+}
 
-	if err = util.FormatMapToXML(bodyBuf, req); err != nil {
+func (clt *Client) DownloadBillContext(ctx context.Context, req map[string]string) (data []byte, err error) {
+	url := clt.apiURL("/pay/downloadbill")
+	body, _, err := clt.marshalRequest(url, req)
+	if err != nil {
 		return
 	}
 
-	url := "https://api.mch.weixin.qq.com/pay/downloadbill"
-	httpResp, err := clt.httpClient.Post(url, "text/xml; charset=utf-8", bodyBuf)
+	httpResp, err := clt.httpPost(ctx, url, "text/xml; charset=utf-8", bytes.NewReader(body))
 	if err != nil {
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("http.Status: %s", httpResp.Status)
+		err = &util.HTTPError{StatusCode: httpResp.StatusCode, Status: httpResp.Status}
 		return
 	}
 
@@ -213,5 +322,9 @@ func (clt *Client) DownloadBill(req map[string]string) (data []byte, err error)
 // 撤销支付API.
 //  NOTE: 请求需要双向证书.
 func (clt *Client) Reverse(req map[string]string) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/secapi/pay/reverse", req)
+	return clt.ReverseContext(context.Background(), req)
+}
+
+func (clt *Client) ReverseContext(ctx context.Context, req map[string]string) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/secapi/pay/reverse"), req)
 }
@@ -0,0 +1,76 @@
+package pay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clt := NewClient("appid", "mchid", "apikey")
+	clt.SetBaseURL(server.URL)
+	clt.SetRetryPolicy(&util.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	orderQuery := OrderQuery{OutTradeNo: "1001"}
+	refund := Refund{OutTradeNo: "1001", OutRefundNo: "R1001", TotalFee: 100, RefundFee: 100}
+	transfer := Transfer{ParnetTradeNo: "T1001", OpenId: "openid", CheckName: "NO_CHECK", Amount: 100, Description: "desc", SpbillCreateIP: "127.0.0.1"}
+	for _, tc := range []struct {
+		name          string
+		call          func() error
+		nonIdempotent bool
+		want          int32
+	}{
+		{"OrderQuery", func() error { _, err := clt.OrderQuery(orderQuery); return err }, false, 3},
+		{"Refund", func() error { _, err := clt.Refund(refund); return err }, false, 1},
+		{"Transfer", func() error { _, err := clt.Transfer(transfer); return err }, false, 1},
+		{"Refund opt-in", func() error { _, err := clt.Refund(refund); return err }, true, 3},
+	} {
+		atomic.StoreInt32(&calls, 0)
+		clt.SetRetryNonIdempotent(tc.nonIdempotent)
+		if err := tc.call(); err == nil {
+			t.Errorf("%s: want error", tc.name)
+		}
+		if calls != tc.want {
+			t.Errorf("%s: want %d calls, have %d", tc.name, tc.want, calls)
+		}
+	}
+}
+
+func TestRetryResultCodeFail(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			fmt.Fprint(w, `<xml><return_code>SUCCESS</return_code><result_code>FAIL</result_code><err_code>SYSTEMERROR</err_code></xml>`)
+			return
+		}
+		fmt.Fprint(w, `<xml><return_code>SUCCESS</return_code><result_code>FAIL</result_code><err_code>ORDERNOTEXIST</err_code></xml>`)
+	}))
+	defer server.Close()
+
+	clt := NewClient("appid", "mchid", "apikey")
+	clt.SetBaseURL(server.URL)
+	clt.SetRetryPolicy(&util.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	resp, err := clt.PostXMLWithoutSign(clt.apiURL("/pay/orderquery"), OrderQuery{OutTradeNo: "1001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("want 2 calls, have %d", calls)
+	}
+	if resp["err_code"] != ErrCodeOrderNotExist {
+		t.Errorf("want err_code %s, have %s", ErrCodeOrderNotExist, resp["err_code"])
+	}
+}
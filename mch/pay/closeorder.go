@@ -1,5 +1,7 @@
 package pay
 
+import "context"
+
 // 关闭订单
 type CloseOrder struct {
 	XMLName    struct{} `xml:"xml" json:"-"`
@@ -8,9 +10,41 @@ type CloseOrder struct {
 	OutTradeNo string   `xml:"out_trade_no" json:"out_trade_no"`
 	NonceStr   string   `xml:"nonce_str" json:"nonce_str"`
 	Sign       string   `xml:"sign" json:"sign"`
+	SignType   string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
+}
+
+// 关闭订单的返回参数
+type CloseOrderResponse struct {
+	CommonResponse
+	ResultMsg string `xml:"result_msg" json:"result_msg"` // 对业务结果的补充说明
+}
+
+func (resp *CloseOrderResponse) decode(m map[string]string) error {
+	return decodeFields(m, resp)
 }
 
 // 关闭订单.
+//
+// Deprecated: 使用 CloseOrderTyped, 它返回解析好的 *CloseOrderResponse.
 func (clt *Client) CloseOrder(req CloseOrder) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/pay/closeorder", req)
+	return clt.CloseOrderContext(context.Background(), req)
+}
+
+// Deprecated: 使用 CloseOrderTypedContext.
+func (clt *Client) CloseOrderContext(ctx context.Context, req CloseOrder) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/pay/closeorder"), req)
+}
+
+// 关闭订单, 返回解析好的 *CloseOrderResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError; 其他错误时 resp 为 nil.
+func (clt *Client) CloseOrderTyped(req CloseOrder) (resp *CloseOrderResponse, err error) {
+	return clt.CloseOrderTypedContext(context.Background(), req)
+}
+
+func (clt *Client) CloseOrderTypedContext(ctx context.Context, req CloseOrder) (resp *CloseOrderResponse, err error) {
+	resp = new(CloseOrderResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/pay/closeorder"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
@@ -0,0 +1,157 @@
+// Code generated by errcodegen from errcode.txt; DO NOT EDIT.
+
+package pay
+
+const (
+	ErrCodeSystemError          = "SYSTEMERROR"           // 系统错误, 请用相同参数再次调用 API
+	ErrCodeBizNeedRetry         = "BIZERR_NEED_RETRY"     // 退款业务流程错误, 需要商户触发重试来解决
+	ErrCodeUserPaying           = "USERPAYING"            // 用户支付中, 需要输入密码
+	ErrCodeBankError            = "BANKERROR"             // 银行系统异常
+	ErrCodeFrequencyLimited     = "FREQUENCY_LIMITED"     // 频率限制, 请降低请求接口频率
+	ErrCodeFreqLimit            = "FREQ_LIMIT"            // 超过频率限制, 请稍后再试
+	ErrCodeProcessing           = "PROCESSING"            // 请求处理中, 请稍后查询
+	ErrCodeTradeError           = "TRADE_ERROR"           // 交易错误, 请稍后重试
+	ErrCodeNoAuth               = "NOAUTH"                // 商户无此接口权限
+	ErrCodeNotEnough            = "NOTENOUGH"             // 余额不足
+	ErrCodeOrderPaid            = "ORDERPAID"             // 商户订单已支付
+	ErrCodeOrderClosed          = "ORDERCLOSED"           // 订单已关闭
+	ErrCodeOrderReversed        = "ORDERREVERSED"         // 订单已撤销
+	ErrCodeOrderNotExist        = "ORDERNOTEXIST"         // 此交易订单号不存在
+	ErrCodeAppIdNotExist        = "APPID_NOT_EXIST"       // APPID 不存在
+	ErrCodeMchIdNotExist        = "MCHID_NOT_EXIST"       // MCHID 不存在
+	ErrCodeAppIdMchIdNotMatch   = "APPID_MCHID_NOT_MATCH" // appid 和 mch_id 不匹配
+	ErrCodeLackParams           = "LACK_PARAMS"           // 缺少参数
+	ErrCodeOutTradeNoUsed       = "OUT_TRADE_NO_USED"     // 商户订单号重复
+	ErrCodeSignError            = "SIGNERROR"             // 签名错误
+	ErrCodeXMLFormatError       = "XML_FORMAT_ERROR"      // XML 格式错误
+	ErrCodeRequirePostMethod    = "REQUIRE_POST_METHOD"   // 请使用 post 方法
+	ErrCodePostDataEmpty        = "POST_DATA_EMPTY"       // post 数据为空
+	ErrCodeNotUTF8              = "NOT_UTF8"              // 编码格式错误, 请使用 UTF-8 编码
+	ErrCodeParamError           = "PARAM_ERROR"           // 参数错误
+	ErrCodeInvalidRequest       = "INVALID_REQUEST"       // 参数错误, 请求参数未按指引进行填写
+	ErrCodeInvalidTransactionId = "INVALID_TRANSACTIONID" // 无效 transaction_id
+	ErrCodeAuthCodeExpire       = "AUTHCODEEXPIRE"        // 二维码已过期, 请用户在微信上刷新后再试
+	ErrCodeAuthCodeError        = "AUTH_CODE_ERROR"       // 授权码参数错误
+	ErrCodeAuthCodeInvalid      = "AUTH_CODE_INVALID"     // 授权码检验错误
+	ErrCodeNotSupportCard       = "NOTSUPORTCARD"         // 不支持卡类型
+	ErrCodeBuyerMismatch        = "BUYER_MISMATCH"        // 支付帐号错误
+	ErrCodeRefundNotExist       = "REFUNDNOTEXIST"        // 退款订单查询失败
+	ErrCodeUserAccountAbnormal  = "USER_ACCOUNT_ABNORMAL" // 退款请求失败, 用户帐号注销
+	ErrCodeBusiness             = "ERROR"                 // 业务错误
+	ErrCodeAmountLimit          = "AMOUNT_LIMIT"          // 付款金额超出限制
+	ErrCodeNameMismatch         = "NAME_MISMATCH"         // 收款用户真实姓名不一致
+	ErrCodeOpenIdError          = "OPENID_ERROR"          // openid 与商户 appid 不匹配
+	ErrCodeV2AccountSimpleBan   = "V2_ACCOUNT_SIMPLE_BAN" // 无法给非实名用户付款
+	ErrCodeSendFailed           = "SEND_FAILED"           // 付款错误, 请查单确认付款结果
+	ErrCodeMoneyLimit           = "MONEY_LIMIT"           // 已经达到今日付款总额上限/已达到付款给此用户额度上限
+	ErrCodeCAError              = "CA_ERROR"              // 商户 API 证书校验出错
+	ErrCodeNoAuthority          = "NO_AUTH"               // 发放失败, 此请求可能存在风险, 已被微信拦截
+	ErrCodeSendNumLimit         = "SENDNUM_LIMIT"         // 该用户今日领取红包个数超过限制
+	ErrCodeIllegalAppId         = "ILLEGAL_APPID"         // 非法 appid, 请确认是否为公众号的 appid
+	ErrCodeFatalError           = "FATAL_ERROR"           // 两次请求参数不一致
+	ErrCodeRecvFailed           = "RECV_FAILED"           // 领取失败
+	ErrCodeRefundFeeInvalid     = "REFUND_FEE_INVALID"    // 退款金额大于支付金额
+)
+
+// 可以用 errors.Is(err, ErrXxx) 判断错误码
+var (
+	ErrSystemError          = &Error{ErrCode: ErrCodeSystemError, ErrCodeDes: "system error"}
+	ErrBizNeedRetry         = &Error{ErrCode: ErrCodeBizNeedRetry, ErrCodeDes: "business error, need retry"}
+	ErrUserPaying           = &Error{ErrCode: ErrCodeUserPaying, ErrCodeDes: "user paying, waiting for password"}
+	ErrBankError            = &Error{ErrCode: ErrCodeBankError, ErrCodeDes: "bank system error"}
+	ErrFrequencyLimited     = &Error{ErrCode: ErrCodeFrequencyLimited, ErrCodeDes: "frequency limited"}
+	ErrFreqLimit            = &Error{ErrCode: ErrCodeFreqLimit, ErrCodeDes: "frequency limit"}
+	ErrProcessing           = &Error{ErrCode: ErrCodeProcessing, ErrCodeDes: "processing"}
+	ErrTradeError           = &Error{ErrCode: ErrCodeTradeError, ErrCodeDes: "trade error"}
+	ErrNoAuth               = &Error{ErrCode: ErrCodeNoAuth, ErrCodeDes: "no permission"}
+	ErrNotEnough            = &Error{ErrCode: ErrCodeNotEnough, ErrCodeDes: "balance not enough"}
+	ErrOrderPaid            = &Error{ErrCode: ErrCodeOrderPaid, ErrCodeDes: "order paid"}
+	ErrOrderClosed          = &Error{ErrCode: ErrCodeOrderClosed, ErrCodeDes: "order closed"}
+	ErrOrderReversed        = &Error{ErrCode: ErrCodeOrderReversed, ErrCodeDes: "order reversed"}
+	ErrOrderNotExist        = &Error{ErrCode: ErrCodeOrderNotExist, ErrCodeDes: "order not exist"}
+	ErrAppIdNotExist        = &Error{ErrCode: ErrCodeAppIdNotExist, ErrCodeDes: "appid not exist"}
+	ErrMchIdNotExist        = &Error{ErrCode: ErrCodeMchIdNotExist, ErrCodeDes: "mch_id not exist"}
+	ErrAppIdMchIdNotMatch   = &Error{ErrCode: ErrCodeAppIdMchIdNotMatch, ErrCodeDes: "appid and mch_id not match"}
+	ErrLackParams           = &Error{ErrCode: ErrCodeLackParams, ErrCodeDes: "lack of params"}
+	ErrOutTradeNoUsed       = &Error{ErrCode: ErrCodeOutTradeNoUsed, ErrCodeDes: "out_trade_no used"}
+	ErrSignError            = &Error{ErrCode: ErrCodeSignError, ErrCodeDes: "sign error"}
+	ErrXMLFormatError       = &Error{ErrCode: ErrCodeXMLFormatError, ErrCodeDes: "xml format error"}
+	ErrRequirePostMethod    = &Error{ErrCode: ErrCodeRequirePostMethod, ErrCodeDes: "require post method"}
+	ErrPostDataEmpty        = &Error{ErrCode: ErrCodePostDataEmpty, ErrCodeDes: "post data empty"}
+	ErrNotUTF8              = &Error{ErrCode: ErrCodeNotUTF8, ErrCodeDes: "not utf8"}
+	ErrParamError           = &Error{ErrCode: ErrCodeParamError, ErrCodeDes: "param error"}
+	ErrInvalidRequest       = &Error{ErrCode: ErrCodeInvalidRequest, ErrCodeDes: "invalid request"}
+	ErrInvalidTransactionId = &Error{ErrCode: ErrCodeInvalidTransactionId, ErrCodeDes: "invalid transaction_id"}
+	ErrAuthCodeExpire       = &Error{ErrCode: ErrCodeAuthCodeExpire, ErrCodeDes: "auth_code expired"}
+	ErrAuthCodeError        = &Error{ErrCode: ErrCodeAuthCodeError, ErrCodeDes: "auth_code error"}
+	ErrAuthCodeInvalid      = &Error{ErrCode: ErrCodeAuthCodeInvalid, ErrCodeDes: "auth_code invalid"}
+	ErrNotSupportCard       = &Error{ErrCode: ErrCodeNotSupportCard, ErrCodeDes: "card type not supported"}
+	ErrBuyerMismatch        = &Error{ErrCode: ErrCodeBuyerMismatch, ErrCodeDes: "buyer mismatch"}
+	ErrRefundNotExist       = &Error{ErrCode: ErrCodeRefundNotExist, ErrCodeDes: "refund not exist"}
+	ErrUserAccountAbnormal  = &Error{ErrCode: ErrCodeUserAccountAbnormal, ErrCodeDes: "user account abnormal"}
+	ErrBusiness             = &Error{ErrCode: ErrCodeBusiness, ErrCodeDes: "business error"}
+	ErrAmountLimit          = &Error{ErrCode: ErrCodeAmountLimit, ErrCodeDes: "amount limit"}
+	ErrNameMismatch         = &Error{ErrCode: ErrCodeNameMismatch, ErrCodeDes: "name mismatch"}
+	ErrOpenIdError          = &Error{ErrCode: ErrCodeOpenIdError, ErrCodeDes: "openid error"}
+	ErrV2AccountSimpleBan   = &Error{ErrCode: ErrCodeV2AccountSimpleBan, ErrCodeDes: "can not pay to unverified user"}
+	ErrSendFailed           = &Error{ErrCode: ErrCodeSendFailed, ErrCodeDes: "send failed"}
+	ErrMoneyLimit           = &Error{ErrCode: ErrCodeMoneyLimit, ErrCodeDes: "money limit"}
+	ErrCAError              = &Error{ErrCode: ErrCodeCAError, ErrCodeDes: "ca error"}
+	ErrNoAuthority          = &Error{ErrCode: ErrCodeNoAuthority, ErrCodeDes: "no authority"}
+	ErrSendNumLimit         = &Error{ErrCode: ErrCodeSendNumLimit, ErrCodeDes: "send num limit"}
+	ErrIllegalAppId         = &Error{ErrCode: ErrCodeIllegalAppId, ErrCodeDes: "illegal appid"}
+	ErrFatalError           = &Error{ErrCode: ErrCodeFatalError, ErrCodeDes: "fatal error"}
+	ErrRecvFailed           = &Error{ErrCode: ErrCodeRecvFailed, ErrCodeDes: "receive failed"}
+	ErrRefundFeeInvalid     = &Error{ErrCode: ErrCodeRefundFeeInvalid, ErrCodeDes: "refund_fee invalid"}
+)
+
+var errCodeInfos = map[string]errCodeInfo{
+	ErrCodeSystemError:          {"系统错误, 请用相同参数再次调用 API", "system error", true, false},
+	ErrCodeBizNeedRetry:         {"退款业务流程错误, 需要商户触发重试来解决", "business error, need retry", true, false},
+	ErrCodeUserPaying:           {"用户支付中, 需要输入密码", "user paying, waiting for password", true, false},
+	ErrCodeBankError:            {"银行系统异常", "bank system error", true, false},
+	ErrCodeFrequencyLimited:     {"频率限制, 请降低请求接口频率", "frequency limited", true, false},
+	ErrCodeFreqLimit:            {"超过频率限制, 请稍后再试", "frequency limit", true, false},
+	ErrCodeProcessing:           {"请求处理中, 请稍后查询", "processing", true, false},
+	ErrCodeTradeError:           {"交易错误, 请稍后重试", "trade error", true, false},
+	ErrCodeNoAuth:               {"商户无此接口权限", "no permission", false, false},
+	ErrCodeNotEnough:            {"余额不足", "balance not enough", false, false},
+	ErrCodeOrderPaid:            {"商户订单已支付", "order paid", false, false},
+	ErrCodeOrderClosed:          {"订单已关闭", "order closed", false, false},
+	ErrCodeOrderReversed:        {"订单已撤销", "order reversed", false, false},
+	ErrCodeOrderNotExist:        {"此交易订单号不存在", "order not exist", false, false},
+	ErrCodeAppIdNotExist:        {"APPID 不存在", "appid not exist", false, false},
+	ErrCodeMchIdNotExist:        {"MCHID 不存在", "mch_id not exist", false, false},
+	ErrCodeAppIdMchIdNotMatch:   {"appid 和 mch_id 不匹配", "appid and mch_id not match", false, false},
+	ErrCodeLackParams:           {"缺少参数", "lack of params", false, false},
+	ErrCodeOutTradeNoUsed:       {"商户订单号重复", "out_trade_no used", false, false},
+	ErrCodeSignError:            {"签名错误", "sign error", false, false},
+	ErrCodeXMLFormatError:       {"XML 格式错误", "xml format error", false, false},
+	ErrCodeRequirePostMethod:    {"请使用 post 方法", "require post method", false, false},
+	ErrCodePostDataEmpty:        {"post 数据为空", "post data empty", false, false},
+	ErrCodeNotUTF8:              {"编码格式错误, 请使用 UTF-8 编码", "not utf8", false, false},
+	ErrCodeParamError:           {"参数错误", "param error", false, false},
+	ErrCodeInvalidRequest:       {"参数错误, 请求参数未按指引进行填写", "invalid request", false, false},
+	ErrCodeInvalidTransactionId: {"无效 transaction_id", "invalid transaction_id", false, false},
+	ErrCodeAuthCodeExpire:       {"二维码已过期, 请用户在微信上刷新后再试", "auth_code expired", false, false},
+	ErrCodeAuthCodeError:        {"授权码参数错误", "auth_code error", false, false},
+	ErrCodeAuthCodeInvalid:      {"授权码检验错误", "auth_code invalid", false, false},
+	ErrCodeNotSupportCard:       {"不支持卡类型", "card type not supported", false, false},
+	ErrCodeBuyerMismatch:        {"支付帐号错误", "buyer mismatch", false, false},
+	ErrCodeRefundNotExist:       {"退款订单查询失败", "refund not exist", false, false},
+	ErrCodeUserAccountAbnormal:  {"退款请求失败, 用户帐号注销", "user account abnormal", false, false},
+	ErrCodeBusiness:             {"业务错误", "business error", false, false},
+	ErrCodeAmountLimit:          {"付款金额超出限制", "amount limit", false, false},
+	ErrCodeNameMismatch:         {"收款用户真实姓名不一致", "name mismatch", false, false},
+	ErrCodeOpenIdError:          {"openid 与商户 appid 不匹配", "openid error", false, false},
+	ErrCodeV2AccountSimpleBan:   {"无法给非实名用户付款", "can not pay to unverified user", false, false},
+	ErrCodeSendFailed:           {"付款错误, 请查单确认付款结果", "send failed", false, false},
+	ErrCodeMoneyLimit:           {"已经达到今日付款总额上限/已达到付款给此用户额度上限", "money limit", false, false},
+	ErrCodeCAError:              {"商户 API 证书校验出错", "ca error", false, false},
+	ErrCodeNoAuthority:          {"发放失败, 此请求可能存在风险, 已被微信拦截", "no authority", false, false},
+	ErrCodeSendNumLimit:         {"该用户今日领取红包个数超过限制", "send num limit", false, false},
+	ErrCodeIllegalAppId:         {"非法 appid, 请确认是否为公众号的 appid", "illegal appid", false, false},
+	ErrCodeFatalError:           {"两次请求参数不一致", "fatal error", false, false},
+	ErrCodeRecvFailed:           {"领取失败", "receive failed", false, false},
+	ErrCodeRefundFeeInvalid:     {"退款金额大于支付金额", "refund_fee invalid", false, false},
+}
@@ -7,12 +7,88 @@ package pay
 
 import "fmt"
 
+//go:generate go run ../../internal/errcodegen -pkg pay -type string -in errcode.txt -out errcode.go
+
 type Error struct {
-	XMLName    struct{} `xml:"xml"                  json:"-"`
-	ReturnCode string   `xml:"return_code"          json:"return_code"`
-	ReturnMsg  string   `xml:"return_msg,omitempty" json:"return_msg,omitempty"`
+	XMLName    struct{} `xml:"xml"                    json:"-"`
+	ReturnCode string   `xml:"return_code"            json:"return_code"`
+	ReturnMsg  string   `xml:"return_msg,omitempty"   json:"return_msg,omitempty"`
+	ErrCode    string   `xml:"err_code,omitempty"     json:"err_code,omitempty"`
+	ErrCodeDes string   `xml:"err_code_des,omitempty" json:"err_code_des,omitempty"`
 }
 
 func (e *Error) Error() string {
+	if e.ErrCode != "" {
+		return fmt.Sprintf("return_code: %q, return_msg: %q, err_code: %q, err_code_des: %q",
+			e.ReturnCode, e.ReturnMsg, e.ErrCode, e.ErrCodeDes)
+	}
 	return fmt.Sprintf("return_code: %q, return_msg: %q", e.ReturnCode, e.ReturnMsg)
 }
+
+// 可以用 errors.Is(err, ErrSystemError) 判断错误码.
+//  target 的 ErrCode 不为空时比较 ErrCode, 否则比较 ReturnCode.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.ErrCode != "" {
+		return t.ErrCode == e.ErrCode
+	}
+	return t.ReturnCode == e.ReturnCode
+}
+
+// 是否是临时性的错误(系统错误, 频率限制, 用户支付中等), 稍后用相同的参数重试可能成功.
+func (e *Error) Temporary() bool {
+	return errCodeInfos[e.ErrCode].temporary
+}
+
+// 是否可以重试, 目前等价于 Temporary.
+func (e *Error) Retryable() bool {
+	return e.Temporary()
+}
+
+// 业务结果为 FAIL(result_code 为 FAIL)的错误, 和协议错误(return_code 为 FAIL)的 Error 区分开.
+//  同样可以用 errors.Is(err, ErrOrderNotExist) 判断错误码.
+type ResultError struct {
+	ErrCode    string `xml:"err_code"     json:"err_code"`
+	ErrCodeDes string `xml:"err_code_des" json:"err_code_des"`
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("result_code: %q, err_code: %q, err_code_des: %q", ResultCodeFail, e.ErrCode, e.ErrCodeDes)
+}
+
+// target 为 *Error 或者 *ResultError 时比较 ErrCode.
+func (e *ResultError) Is(target error) bool {
+	switch t := target.(type) {
+	case *Error:
+		return t.ErrCode != "" && t.ErrCode == e.ErrCode
+	case *ResultError:
+		return t.ErrCode == e.ErrCode
+	}
+	return false
+}
+
+// 是否是临时性的错误(系统错误, 频率限制, 用户支付中等), 稍后用相同的参数重试可能成功.
+func (e *ResultError) Temporary() bool {
+	return errCodeInfos[e.ErrCode].temporary
+}
+
+// 是否可以重试, 目前等价于 Temporary.
+func (e *ResultError) Retryable() bool {
+	return e.Temporary()
+}
+
+// 错误码的中文说明和英文说明, 如果错误码不在目录(errcode.txt)中, ok == false.
+func ErrCodeDescription(errCode string) (zh, en string, ok bool) {
+	info, ok := errCodeInfos[errCode]
+	return info.zh, info.en, ok
+}
+
+type errCodeInfo struct {
+	zh           string
+	en           string
+	temporary    bool // 临时性错误
+	refreshToken bool // 支付接口没有 access_token, 总是 false
+}
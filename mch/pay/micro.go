@@ -1,5 +1,7 @@
 package pay
 
+import "context"
+
 // 扫码支付
 type MicroPay struct {
 	XMLName        struct{} `xml:"xml" json:"-"`
@@ -8,6 +10,7 @@ type MicroPay struct {
 	DeviceInfo     string   `xml:"device_info" json:"device_info"`
 	NonceStr       string   `xml:"nonce_str" json:"nonce_str"`
 	Sign           string   `xml:"sign" json:"sign"`
+	SignType       string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
 	Body           string   `xml:"body" json:"body"`
 	Detail         string   `xml:"detail,omitempty" json:"detail,omitempty"`
 	Attach         string   `xml:"attach,omitempty" json:"attach,omitempty"`
@@ -21,7 +24,51 @@ type MicroPay struct {
 	AuthCode       string   `xml:"auth_code" json:"auth_code"`
 }
 
+// 提交被扫支付的返回参数
+type MicroPayResponse struct {
+	CommonResponse
+	OpenId             string `xml:"openid"               json:"openid"`
+	IsSubscribe        string `xml:"is_subscribe"         json:"is_subscribe"`
+	TradeType          string `xml:"trade_type"           json:"trade_type"`
+	BankType           string `xml:"bank_type"            json:"bank_type"`
+	FeeType            string `xml:"fee_type"             json:"fee_type"`
+	TotalFee           int    `xml:"total_fee"            json:"total_fee"`
+	SettlementTotalFee int    `xml:"settlement_total_fee" json:"settlement_total_fee"`
+	CouponFee          int    `xml:"coupon_fee"           json:"coupon_fee"`
+	CashFeeType        string `xml:"cash_fee_type"        json:"cash_fee_type"`
+	CashFee            int    `xml:"cash_fee"             json:"cash_fee"`
+	TransactionId      string `xml:"transaction_id"       json:"transaction_id"`
+	OutTradeNo         string `xml:"out_trade_no"         json:"out_trade_no"`
+	Attach             string `xml:"attach"               json:"attach"`
+	TimeEnd            string `xml:"time_end"             json:"time_end"`
+}
+
+func (resp *MicroPayResponse) decode(m map[string]string) error {
+	return decodeFields(m, resp)
+}
+
 // 提交被扫支付API.
+//
+// Deprecated: 使用 MicroPayTyped, 它返回解析好的 *MicroPayResponse.
 func (clt *Client) MicroPay(req MicroPay) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/pay/micropay", req)
+	return clt.MicroPayContext(context.Background(), req)
+}
+
+// Deprecated: 使用 MicroPayTypedContext.
+func (clt *Client) MicroPayContext(ctx context.Context, req MicroPay) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/pay/micropay"), req)
+}
+
+// 提交被扫支付API, 返回解析好的 *MicroPayResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError(比如 ErrUserPaying); 其他错误时 resp 为 nil.
+func (clt *Client) MicroPayTyped(req MicroPay) (resp *MicroPayResponse, err error) {
+	return clt.MicroPayTypedContext(context.Background(), req)
+}
+
+func (clt *Client) MicroPayTypedContext(ctx context.Context, req MicroPay) (resp *MicroPayResponse, err error) {
+	resp = new(MicroPayResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/pay/micropay"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
@@ -1,39 +1,251 @@
 package pay
 
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+const (
+	// 微信支付在 24 小时 4 分钟内重复发送通知(15s/15s/30s/3m/10m/20m/30m/30m/30m/60m/3h/3h/3h/6h/6h), 所以默认记录 25 小时.
+	DefaultNotifyDedupeTTL = 25 * time.Hour
+
+	// 正在处理的通知的记录保存的时间, 进程崩溃等原因没有处理完时, 之后的重试可以重新处理.
+	notifyProcessingTTL = 10 * time.Minute
+
+	maxNotifyBodySize = 1 << 20 // 通知的 http body 最大 1MB
+)
+
+var ErrInvalidSignature = errors.New("pay: invalid signature")
+
+// 支付结果通知
 type PayNotify struct {
-	XMLName        struct{} `xml:"xml" json:"-"`
-	AppId          string   `xml:"appid"   json:"appid"`
-	MchId          string   `xml:"mch_id" json:"mch_id"`
-	DeviceInfo     string   `xml:"device_info" json:"device_info"`
-	NonceStr       string   `xml:"nonce_str" json:"nonce_str"`
-	Sign           string   `xml:"sign" json:"sign"`
-	ResultCode     string   `xml:"result_code" json:"result_code"`
-	ErrCode        string   `xml:"err_code,omitempty" json:"err_code,omitempty"`
-	ErrDescription string   `xml:"err_code_des,omitempty" json:"err_code_des,omitempty"`
-	OpenId         string   `xml:"openid" json:"openid"`
-	IsSubscribe    string   `xml:"is_subscribe" json:"is_subscribe"`
-	TradeType      string   `xml:"trade_type" json:"trade_type"`
-	BankType       string   `xml:"bank_type" json:"bank_type"`
-	TotalFee       int      `xml:"total_fee" json:"total_fee"`
-	FeeType        string   `xml:"fee_type,omitempty" json:"fee_type,omitempty"`
-	CashFee        string   `xml:"cash_fee" json:"cash_fee"`
-	CashFeeType    string   `xml:"cash_fee_type" json:"cash_fee_type"`
-	CouponFee      int      `xml:"coupon_fee" json:"coupon_fee"`
-	CouponCount    int      `xml:"coupon_count" json:"coupon_count"`
-	TransactionId  string   `xml:"transaction_id" json:"transaction_id"`
-	OutTradeNo     string   `xml:"out_trade_no" json:"out_trade_no"`
-	Attach         string   `xml:"attach,omitempty" json:"attach,omitempty"`
-	TimeEnd        string   `xml:"time_end" json:"time_end"`
-	CouponBachId1  string   `xml:"coupon_batch_id_1,omitempty" json:"coupon_batch_id_1,omitempty"`
-	CouponId1      string   `xml:"coupon_id_1,omitempty" json:"coupon_id_1,omitempty"`
-	CouponFee1     int      `xml:"coupon_fee_1,omitempty" json:"coupon_fee_1,omitempty"`
-	CouponBachId2  string   `xml:"coupon_batch_id_2,omitempty" json:"coupon_batch_id_2,omitempty"`
-	CouponId2      string   `xml:"coupon_id_2,omitempty" json:"coupon_id_2,omitempty"`
-	CouponFee2     int      `xml:"coupon_fee2" json:"coupon_fee2"`
-	CouponBachId3  string   `xml:"coupon_batch_id_3,omitempty" json:"coupon_batch_id_3,omitempty"`
-	CouponId3      string   `xml:"coupon_id_3,omitempty" json:"coupon_id_3,omitempty"`
-	CouponFee3     int      `xml:"coupon_fee_3,omitempty" json:"coupon_fee_3,omitempty"`
-	CouponBachId4  string   `xml:"coupon_batch_id_4,omitempty" json:"coupon_batch_id_4,omitempty"`
-	CouponId4      string   `xml:"coupon_id_4,omitempty" json:"coupon_id_4,omitempty"`
-	CouponFee4     int      `xml:"coupon_fee_4,omitempty" json:"coupon_fee_4,omitempty"`
+	CommonResponse
+	ReturnCode         string   `xml:"return_code"          json:"return_code"`
+	ReturnMsg          string   `xml:"return_msg"           json:"return_msg"`
+	OpenId             string   `xml:"openid"               json:"openid"`
+	IsSubscribe        string   `xml:"is_subscribe"         json:"is_subscribe"`
+	TradeType          string   `xml:"trade_type"           json:"trade_type"`
+	BankType           string   `xml:"bank_type"            json:"bank_type"`
+	TotalFee           int      `xml:"total_fee"            json:"total_fee"`
+	SettlementTotalFee int      `xml:"settlement_total_fee" json:"settlement_total_fee"`
+	FeeType            string   `xml:"fee_type"             json:"fee_type"`
+	CashFee            int      `xml:"cash_fee"             json:"cash_fee"`
+	CashFeeType        string   `xml:"cash_fee_type"        json:"cash_fee_type"`
+	CouponFee          int      `xml:"coupon_fee"           json:"coupon_fee"`
+	CouponCount        int      `xml:"coupon_count"         json:"coupon_count"`
+	Coupons            []Coupon `xml:"-"                    json:"coupons,omitempty"` // coupon_id_$n, coupon_type_$n, coupon_fee_$n
+	TransactionId      string   `xml:"transaction_id"       json:"transaction_id"`
+	OutTradeNo         string   `xml:"out_trade_no"         json:"out_trade_no"`
+	Attach             string   `xml:"attach"               json:"attach"`
+	TimeEnd            string   `xml:"time_end"             json:"time_end"`
+}
+
+func (notify *PayNotify) decode(m map[string]string) (err error) {
+	if err = decodeFields(m, notify); err != nil {
+		return
+	}
+	notify.Coupons, err = decodeCoupons(m, "coupon_id", "coupon_type", "coupon_fee", "")
+	return
+}
+
+// 支付是否成功
+func (notify *PayNotify) Succeeded() bool {
+	return notify.ResultCode == ResultCodeSuccess
+}
+
+// 解析支付结果通知的 http body, 校验签名, appid 和 mch_id.
+//  NOTE: 一般直接使用 NotifyHandler, 不需要调用这个函数.
+func (clt *Client) ParsePayNotify(r io.Reader) (notify *PayNotify, err error) {
+	m, err := clt.parseNotify(r, true)
+	if err != nil {
+		return
+	}
+	notify = new(PayNotify)
+	notify.setRaw(m)
+	if err = notify.decode(m); err != nil {
+		notify = nil
+		return
+	}
+	return
+}
+
+// 解析通知的参数, 校验 return_code, 签名(checkSign 为 true 时), appid 和 mch_id.
+//  NOTE: 退款结果通知没有签名, 用 req_info 的加密保证.
+func (clt *Client) parseNotify(r io.Reader, checkSign bool) (m map[string]string, err error) {
+	if m, err = util.ParseXMLToMap(io.LimitReader(r, maxNotifyBodySize)); err != nil {
+		return
+	}
+
+	if returnCode := m["return_code"]; returnCode != ReturnCodeSuccess {
+		err = &Error{ReturnCode: returnCode, ReturnMsg: m["return_msg"]}
+		return
+	}
+	if checkSign {
+		if err = clt.checkSign(m, SignTypeMD5); err != nil {
+			return
+		}
+	}
+	if appId := m["appid"]; appId != clt.appId {
+		err = fmt.Errorf("appid mismatch, have: %q, want: %q", appId, clt.appId)
+		return
+	}
+	if mchId := m["mch_id"]; mchId != clt.mchId {
+		err = fmt.Errorf("mch_id mismatch, have: %q, want: %q", mchId, clt.mchId)
+		return
+	}
+	return
+}
+
+// 校验 m 的签名, 签名不一致时返回 ErrInvalidSignature.
+//  签名类型为 m 里的 sign_type, 没有 sign_type 时为 signType.
+func (clt *Client) checkSign(m map[string]string, signType string) error {
+	signature, ok := m["sign"]
+	if !ok {
+		return errors.New("no sign parameter")
+	}
+	if declared := m["sign_type"]; declared != "" {
+		signType = declared
+	}
+	if !isValidSignType(signType) {
+		return fmt.Errorf("unsupported sign_type: %q", signType)
+	}
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(clt.sign(m, signType))) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// 接收微信支付异步通知的 http.Handler, 用法:
+//
+//    handler := pay.NewNotifyHandler(clt, func(ctx context.Context, notify *pay.PayNotify) error {
+//        if !notify.Succeeded() {
+//            return nil
+//        }
+//        return markOrderPaid(notify.OutTradeNo, notify.TransactionId, notify.TotalFee)
+//    })
+//    http.Handle("/pay/notify", handler)
+//
+//  NOTE:
+//  1. 签名错误, appid 或者 mch_id 不一致, handler 返回错误时回复 FAIL, 微信支付之后会重新发送通知;
+//     handler 返回 nil 时回复 SUCCESS;
+//  2. 默认按照 transaction_id 去重, 已经处理成功的重复通知直接回复 SUCCESS, 不再调用 handler;
+//     正在处理的通知的重复通知回复 FAIL, 微信支付之后重新发送时根据处理结果决定; handler 返回错误时删除记录;
+//     SeenStore 出错时不去重, 所以 handler 仍然需要保证幂等(比如检查订单状态);
+//  3. 回复的 return_msg 为固定的文字, 不包含错误的详细信息; 错误默认不记录, 需要时用 SetErrorHandler 设置.
+type NotifyHandler struct {
+	parse  func(r io.Reader) (notify interface{}, dedupeKey string, err error)
+	handle func(ctx context.Context, notify interface{}) error
+
+	seenStore    util.SeenStore
+	dedupeTTL    time.Duration
+	errorHandler func(r *http.Request, err error)
+}
+
+// 处理支付结果通知的 NotifyHandler, 支付失败的通知同样会调用 handler.
+func NewNotifyHandler(clt *Client, handler func(ctx context.Context, notify *PayNotify) error) *NotifyHandler {
+	return &NotifyHandler{
+		parse: func(r io.Reader) (notify interface{}, dedupeKey string, err error) {
+			payNotify, err := clt.ParsePayNotify(r)
+			if err != nil {
+				return
+			}
+			if payNotify.TransactionId != "" {
+				dedupeKey = "pay:" + payNotify.MchId + ":transaction:" + payNotify.TransactionId
+			}
+			notify = payNotify
+			return
+		},
+		handle: func(ctx context.Context, notify interface{}) error {
+			return handler(ctx, notify.(*PayNotify))
+		},
+		seenStore: util.NewMemorySeenStore(0),
+		dedupeTTL: DefaultNotifyDedupeTTL,
+	}
+}
+
+// 设置去重的存储和记录保存的时间, 多个进程(机器)接收通知时可以使用基于 Redis 等实现的 SeenStore.
+//  store 为 nil 时不去重, ttl <= 0 时使用 DefaultNotifyDedupeTTL.
+func (h *NotifyHandler) SetDedupe(store util.SeenStore, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultNotifyDedupeTTL
+	}
+	h.seenStore = store
+	h.dedupeTTL = ttl
+}
+
+// 设置回复 FAIL 时调用的函数, 用于记录日志等.
+func (h *NotifyHandler) SetErrorHandler(handler func(r *http.Request, err error)) {
+	h.errorHandler = handler
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	notify, key, err := h.parse(r.Body)
+	if err != nil {
+		msg := "参数格式校验错误"
+		if errors.Is(err, ErrInvalidSignature) {
+			msg = "签名失败"
+		}
+		h.fail(w, r, err, msg)
+		return
+	}
+
+	dedupe := key != "" && h.seenStore != nil
+	if dedupe {
+		state, err := h.seenStore.Claim(key, notifyProcessingTTL)
+		if err == nil {
+			switch state {
+			case util.SeenDone:
+				writeNotifyReply(w, ReturnCodeSuccess, "OK")
+				return
+			case util.SeenProcessing:
+				writeNotifyReply(w, ReturnCodeFail, "正在处理")
+				return
+			}
+		}
+		dedupe = err == nil // store 出错时不去重
+	}
+
+	if err = h.handle(r.Context(), notify); err != nil {
+		if dedupe {
+			h.seenStore.Forget(key)
+		}
+		h.fail(w, r, err, "处理失败")
+		return
+	}
+	if dedupe {
+		h.seenStore.Done(key, h.dedupeTTL)
+	}
+	writeNotifyReply(w, ReturnCodeSuccess, "OK")
+}
+
+// 回复 FAIL, msg 为回复给微信支付的固定的 return_msg, 不包含 err 的内容; err 交给 errorHandler 记录.
+func (h *NotifyHandler) fail(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if h.errorHandler != nil {
+		h.errorHandler(r, err)
+	}
+	writeNotifyReply(w, ReturnCodeFail, msg)
+}
+
+func writeNotifyReply(w http.ResponseWriter, returnCode, returnMsg string) {
+	var buf bytes.Buffer
+	util.FormatMapToXML(&buf, map[string]string{
+		"return_code": returnCode,
+		"return_msg":  returnMsg,
+	})
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.Write(buf.Bytes())
 }
@@ -0,0 +1,181 @@
+package pay
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/skynology/wechat/util"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+
+	var notifies []*PayNotify
+	var handlerErr error
+	handler := NewNotifyHandler(clt, func(ctx context.Context, notify *PayNotify) error {
+		notifies = append(notifies, notify)
+		return handlerErr
+	})
+
+	var reply map[string]string
+	post := func(m map[string]string) string {
+		var body bytes.Buffer
+		util.FormatMapToXML(&body, m)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay/notify", &body))
+		var err error
+		if reply, err = util.ParseXMLToMap(w.Body); err != nil {
+			t.Fatal(err)
+		}
+		return reply["return_code"]
+	}
+	notify := map[string]string{
+		"return_code":    ReturnCodeSuccess,
+		"result_code":    ResultCodeSuccess,
+		"appid":          "appid",
+		"mch_id":         "mchid",
+		"nonce_str":      "5K8264ILTKCH16CQ2502SI8ZNMTM67VS",
+		"transaction_id": "4200000001",
+		"out_trade_no":   "1001",
+		"total_fee":      "100",
+		"coupon_count":   "1",
+		"coupon_id_0":    "C0",
+		"coupon_fee_0":   "10",
+	}
+	notify["sign"] = clt.Sign(notify)
+
+	// handler 返回错误时回复 FAIL, 重试时再次调用 handler
+	handlerErr = errors.New("db error")
+	if code := post(notify); code != ReturnCodeFail {
+		t.Errorf("return_code = %s, want FAIL", code)
+	}
+	handlerErr = nil
+	if code := post(notify); code != ReturnCodeSuccess {
+		t.Errorf("return_code = %s, want SUCCESS", code)
+	}
+	// 重复的通知不再调用 handler
+	if code := post(notify); code != ReturnCodeSuccess {
+		t.Errorf("return_code = %s, want SUCCESS", code)
+	}
+	if len(notifies) != 2 {
+		t.Fatalf("handler called %d times, want 2", len(notifies))
+	}
+	if n := notifies[1]; n.TransactionId != "4200000001" || n.TotalFee != 100 || len(n.Coupons) != 1 || n.Coupons[0].Fee != 10 || !n.Succeeded() {
+		t.Errorf("notify = %+v", n)
+	}
+
+	// 签名错误
+	var gotErr error
+	handler.SetErrorHandler(func(r *http.Request, err error) { gotErr = err })
+	notify["transaction_id"] = "4200000002"
+	if code := post(notify); code != ReturnCodeFail || gotErr != ErrInvalidSignature || reply["return_msg"] != "签名失败" {
+		t.Errorf("reply = %v, err = %v", reply, gotErr)
+	}
+	// 错误的详细信息只交给 errorHandler, 不回复给微信支付
+	notify["appid"] = "other"
+	notify["sign"] = clt.Sign(notify)
+	if code := post(notify); code != ReturnCodeFail || gotErr == nil || strings.Contains(reply["return_msg"], "other") {
+		t.Errorf("reply = %v, err = %v", reply, gotErr)
+	}
+	if len(notifies) != 2 {
+		t.Errorf("handler called %d times, want 2", len(notifies))
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pay/notify", strings.NewReader("")))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d", w.Code)
+	}
+}
+
+// 通知还在处理时, 重复的通知回复 FAIL; 处理失败后, 之后的重试再次调用 handler.
+func TestNotifyHandlerInProgress(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+
+	var payNotify bytes.Buffer
+	m := map[string]string{
+		"return_code":    ReturnCodeSuccess,
+		"result_code":    ResultCodeSuccess,
+		"appid":          "appid",
+		"mch_id":         "mchid",
+		"nonce_str":      "5K8264ILTKCH16CQ2502SI8ZNMTM67VS",
+		"transaction_id": "4200000001",
+		"out_trade_no":   "1001",
+		"total_fee":      "100",
+	}
+	m["sign"] = clt.Sign(m)
+	util.FormatMapToXML(&payNotify, m)
+
+	var reqInfo bytes.Buffer
+	util.FormatMapToXML(&reqInfo, map[string]string{"refund_id": "50000000001", "out_refund_no": "R1001"})
+	encrypted, err := util.AESECBEncrypt(reqInfo.Bytes(), clt.reqInfoKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refundNotify bytes.Buffer
+	util.FormatMapToXML(&refundNotify, map[string]string{
+		"return_code": ReturnCodeSuccess,
+		"appid":       "appid",
+		"mch_id":      "mchid",
+		"req_info":    base64.StdEncoding.EncodeToString(encrypted),
+	})
+
+	for _, tc := range []struct {
+		name       string
+		body       []byte
+		newHandler func(handle func() error) *NotifyHandler
+	}{
+		{"pay", payNotify.Bytes(), func(handle func() error) *NotifyHandler {
+			return NewNotifyHandler(clt, func(ctx context.Context, notify *PayNotify) error { return handle() })
+		}},
+		{"refund", refundNotify.Bytes(), func(handle func() error) *NotifyHandler {
+			return NewRefundNotifyHandler(clt, func(ctx context.Context, notify *RefundNotify) error { return handle() })
+		}},
+	} {
+		started, release := make(chan struct{}), make(chan error)
+		var calls int32
+		handler := tc.newHandler(func() error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+				return <-release
+			}
+			return nil
+		})
+		post := func() string {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay/notify", bytes.NewReader(tc.body)))
+			resp, err := util.ParseXMLToMap(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp["return_code"]
+		}
+
+		first := make(chan string)
+		go func() { first <- post() }()
+		<-started
+		if code := post(); code != ReturnCodeFail {
+			t.Errorf("%s: in-flight duplicate return_code = %s, want FAIL", tc.name, code)
+		}
+		release <- errors.New("db error")
+		if code := <-first; code != ReturnCodeFail {
+			t.Errorf("%s: return_code = %s, want FAIL", tc.name, code)
+		}
+		if code := post(); code != ReturnCodeSuccess {
+			t.Errorf("%s: retry return_code = %s, want SUCCESS", tc.name, code)
+		}
+		if code := post(); code != ReturnCodeSuccess {
+			t.Errorf("%s: duplicate return_code = %s, want SUCCESS", tc.name, code)
+		}
+		if n := atomic.LoadInt32(&calls); n != 2 {
+			t.Errorf("%s: handler called %d times, want 2", tc.name, n)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package pay
 
+import "context"
+
 // 查询订单
 type OrderQuery struct {
 	XMLName       struct{} `xml:"xml" json:"-"`
@@ -9,9 +11,72 @@ type OrderQuery struct {
 	OutTradeNo    string   `xml:"out_trade_no" json:"out_trade_no"`
 	NonceStr      string   `xml:"nonce_str" json:"nonce_str"`
 	Sign          string   `xml:"sign" json:"sign"`
+	SignType      string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
+}
+
+const (
+	TradeStateSuccess    = "SUCCESS"    // 支付成功
+	TradeStateRefund     = "REFUND"     // 转入退款
+	TradeStateNotPay     = "NOTPAY"     // 未支付
+	TradeStateClosed     = "CLOSED"     // 已关闭
+	TradeStateRevoked    = "REVOKED"    // 已撤销(刷卡支付)
+	TradeStateUserPaying = "USERPAYING" // 用户支付中
+	TradeStatePayError   = "PAYERROR"   // 支付失败(其他原因, 如银行返回失败)
+)
+
+// 订单查询的返回参数
+type OrderQueryResponse struct {
+	CommonResponse
+	OpenId             string   `xml:"openid"               json:"openid"`
+	IsSubscribe        string   `xml:"is_subscribe"         json:"is_subscribe"`
+	TradeType          string   `xml:"trade_type"           json:"trade_type"`
+	TradeState         string   `xml:"trade_state"          json:"trade_state"` // TradeStateSuccess 等
+	BankType           string   `xml:"bank_type"            json:"bank_type"`
+	TotalFee           int      `xml:"total_fee"            json:"total_fee"`
+	SettlementTotalFee int      `xml:"settlement_total_fee" json:"settlement_total_fee"`
+	FeeType            string   `xml:"fee_type"             json:"fee_type"`
+	CashFee            int      `xml:"cash_fee"             json:"cash_fee"`
+	CashFeeType        string   `xml:"cash_fee_type"        json:"cash_fee_type"`
+	CouponFee          int      `xml:"coupon_fee"           json:"coupon_fee"`
+	CouponCount        int      `xml:"coupon_count"         json:"coupon_count"`
+	Coupons            []Coupon `xml:"-"                    json:"coupons,omitempty"` // coupon_id_$n, coupon_type_$n, coupon_fee_$n
+	TransactionId      string   `xml:"transaction_id"       json:"transaction_id"`
+	OutTradeNo         string   `xml:"out_trade_no"         json:"out_trade_no"`
+	Attach             string   `xml:"attach"               json:"attach"`
+	TimeEnd            string   `xml:"time_end"             json:"time_end"`
+	TradeStateDesc     string   `xml:"trade_state_desc"     json:"trade_state_desc"`
+}
+
+func (resp *OrderQueryResponse) decode(m map[string]string) (err error) {
+	if err = decodeFields(m, resp); err != nil {
+		return
+	}
+	resp.Coupons, err = decodeCoupons(m, "coupon_id", "coupon_type", "coupon_fee", "")
+	return
 }
 
 // 订单查询.
+//
+// Deprecated: 使用 OrderQueryTyped, 它返回解析好的 *OrderQueryResponse.
 func (clt *Client) OrderQuery(req OrderQuery) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/pay/orderquery", req)
+	return clt.OrderQueryContext(context.Background(), req)
+}
+
+// Deprecated: 使用 OrderQueryTypedContext.
+func (clt *Client) OrderQueryContext(ctx context.Context, req OrderQuery) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/pay/orderquery"), req)
+}
+
+// 订单查询, 返回解析好的 *OrderQueryResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError; 其他错误时 resp 为 nil.
+func (clt *Client) OrderQueryTyped(req OrderQuery) (resp *OrderQueryResponse, err error) {
+	return clt.OrderQueryTypedContext(context.Background(), req)
+}
+
+func (clt *Client) OrderQueryTypedContext(ctx context.Context, req OrderQuery) (resp *OrderQueryResponse, err error) {
+	resp = new(OrderQueryResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/pay/orderquery"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
@@ -1,7 +1,13 @@
 package pay
 
+import "context"
+
 // 红包发放API.
 //  NOTE: 请求需要双向证书
 func (clt *Client) SendRedPack(req map[string]string) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/mmpaymkttransfers/sendredpack", req)
+	return clt.SendRedPackContext(context.Background(), req)
+}
+
+func (clt *Client) SendRedPackContext(ctx context.Context, req map[string]string) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/mmpaymkttransfers/sendredpack"), req)
 }
@@ -1,5 +1,10 @@
 package pay
 
+import (
+	"context"
+	"strconv"
+)
+
 // 退款申请
 type Refund struct {
 	XMLName       struct{} `xml:"xml" json:"-"`
@@ -8,6 +13,7 @@ type Refund struct {
 	DeviceInfo    string   `xml:"device_info" json:"device_info"`
 	NonceStr      string   `xml:"nonce_str" json:"nonce_str"`
 	Sign          string   `xml:"sign" json:"sign"`
+	SignType      string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
 	TransactionId string   `xml:"transaction_id" json:"transaction_id"`
 	OutTradeNo    string   `xml:"out_trade_no" json:"out_trade_no"`
 	OutRefundNo   string   `xml:"out_refund_no" json:"out_refund_no"`
@@ -17,10 +23,68 @@ type Refund struct {
 	OpUserId      string   `xml:"op_user_id" json:"op_user_id"`
 }
 
+const (
+	RefundStatusSuccess     = "SUCCESS"     // 退款成功
+	RefundStatusRefundClose = "REFUNDCLOSE" // 退款关闭
+	RefundStatusProcessing  = "PROCESSING"  // 退款处理中
+	RefundStatusChange      = "CHANGE"      // 退款异常, 需要商户人工处理
+)
+
+// 申请退款的返回参数
+type RefundResponse struct {
+	CommonResponse
+	TransactionId       string   `xml:"transaction_id"        json:"transaction_id"`
+	OutTradeNo          string   `xml:"out_trade_no"          json:"out_trade_no"`
+	OutRefundNo         string   `xml:"out_refund_no"         json:"out_refund_no"`
+	RefundId            string   `xml:"refund_id"             json:"refund_id"`
+	RefundChannel       string   `xml:"refund_channel"        json:"refund_channel"`
+	RefundFee           int      `xml:"refund_fee"            json:"refund_fee"`
+	SettlementRefundFee int      `xml:"settlement_refund_fee" json:"settlement_refund_fee"`
+	TotalFee            int      `xml:"total_fee"             json:"total_fee"`
+	SettlementTotalFee  int      `xml:"settlement_total_fee"  json:"settlement_total_fee"`
+	FeeType             string   `xml:"fee_type"              json:"fee_type"`
+	CashFee             int      `xml:"cash_fee"              json:"cash_fee"`
+	CashFeeType         string   `xml:"cash_fee_type"         json:"cash_fee_type"`
+	CashRefundFee       int      `xml:"cash_refund_fee"       json:"cash_refund_fee"`
+	CouponRefundFee     int      `xml:"coupon_refund_fee"     json:"coupon_refund_fee"`
+	CouponRefundCount   int      `xml:"coupon_refund_count"   json:"coupon_refund_count"`
+	Coupons             []Coupon `xml:"-"                     json:"coupons,omitempty"` // coupon_refund_id_$n, coupon_type_$n, coupon_refund_fee_$n
+}
+
+func (resp *RefundResponse) decode(m map[string]string) (err error) {
+	if err = decodeFields(m, resp); err != nil {
+		return
+	}
+	resp.Coupons, err = decodeCoupons(m, "coupon_refund_id", "coupon_type", "coupon_refund_fee", "")
+	return
+}
+
 // 申请退款.
 //  NOTE: 请求需要双向证书.
+//
+// Deprecated: 使用 RefundTyped, 它返回解析好的 *RefundResponse.
 func (clt *Client) Refund(req Refund) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/secapi/pay/refund", req)
+	return clt.RefundContext(context.Background(), req)
+}
+
+// Deprecated: 使用 RefundTypedContext.
+func (clt *Client) RefundContext(ctx context.Context, req Refund) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/secapi/pay/refund"), req)
+}
+
+// 申请退款, 返回解析好的 *RefundResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError; 其他错误时 resp 为 nil.
+//  NOTE: 请求需要双向证书.
+func (clt *Client) RefundTyped(req Refund) (resp *RefundResponse, err error) {
+	return clt.RefundTypedContext(context.Background(), req)
+}
+
+func (clt *Client) RefundTypedContext(ctx context.Context, req Refund) (resp *RefundResponse, err error) {
+	resp = new(RefundResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/secapi/pay/refund"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
 
 type RefundQuery struct {
@@ -30,13 +94,102 @@ type RefundQuery struct {
 	DeviceInfo    string   `xml:"device_info" json:"device_info"`
 	NonceStr      string   `xml:"nonce_str" json:"nonce_str"`
 	Sign          string   `xml:"sign" json:"sign"`
+	SignType      string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
 	TransactionId string   `xml:"transaction_id" json:"transaction_id"`
 	OutTradeNo    string   `xml:"out_trade_no" json:"out_trade_no"`
 	OutRefundNo   string   `xml:"out_refund_no" json:"out_refund_no"`
 	RefundId      string   `xml:"refund_id,omitempty" json:"refund_id,omitempty"`
 }
 
+// 退款查询返回的一笔退款, 对应 out_refund_no_$n, refund_id_$n 等参数
+type RefundQueryItem struct {
+	OutRefundNo         string   `json:"out_refund_no"`
+	RefundId            string   `json:"refund_id"`
+	RefundChannel       string   `json:"refund_channel"`
+	RefundFee           int      `json:"refund_fee"`
+	SettlementRefundFee int      `json:"settlement_refund_fee"`
+	CouponRefundFee     int      `json:"coupon_refund_fee"`
+	CouponRefundCount   int      `json:"coupon_refund_count"`
+	Coupons             []Coupon `json:"coupons,omitempty"` // coupon_refund_id_$n_$m, coupon_type_$n_$m, coupon_refund_fee_$n_$m
+	RefundStatus        string   `json:"refund_status"`     // RefundStatusSuccess 等
+	RefundAccount       string   `json:"refund_account"`
+	RefundRecvAccount   string   `json:"refund_recv_accout"` // 微信支付文档里的参数名就是 refund_recv_accout_$n
+	RefundSuccessTime   string   `json:"refund_success_time"`
+}
+
+// 退款查询的返回参数
+type RefundQueryResponse struct {
+	CommonResponse
+	TransactionId      string            `xml:"transaction_id"       json:"transaction_id"`
+	OutTradeNo         string            `xml:"out_trade_no"         json:"out_trade_no"`
+	TotalFee           int               `xml:"total_fee"            json:"total_fee"`
+	SettlementTotalFee int               `xml:"settlement_total_fee" json:"settlement_total_fee"`
+	FeeType            string            `xml:"fee_type"             json:"fee_type"`
+	CashFee            int               `xml:"cash_fee"             json:"cash_fee"`
+	RefundCount        int               `xml:"refund_count"         json:"refund_count"`
+	Refunds            []RefundQueryItem `xml:"-"                    json:"refunds,omitempty"`
+}
+
+func (resp *RefundQueryResponse) decode(m map[string]string) (err error) {
+	if err = decodeFields(m, resp); err != nil {
+		return
+	}
+	for i := 0; ; i++ {
+		n := "_" + strconv.Itoa(i)
+		outRefundNo, ok := m["out_refund_no"+n]
+		if !ok {
+			return
+		}
+		item := RefundQueryItem{
+			OutRefundNo:       outRefundNo,
+			RefundId:          m["refund_id"+n],
+			RefundChannel:     m["refund_channel"+n],
+			RefundStatus:      m["refund_status"+n],
+			RefundAccount:     m["refund_account"+n],
+			RefundRecvAccount: m["refund_recv_accout"+n],
+			RefundSuccessTime: m["refund_success_time"+n],
+		}
+		if item.RefundFee, err = intValue(m, "refund_fee"+n); err != nil {
+			return
+		}
+		if item.SettlementRefundFee, err = intValue(m, "settlement_refund_fee"+n); err != nil {
+			return
+		}
+		if item.CouponRefundFee, err = intValue(m, "coupon_refund_fee"+n); err != nil {
+			return
+		}
+		if item.CouponRefundCount, err = intValue(m, "coupon_refund_count"+n); err != nil {
+			return
+		}
+		if item.Coupons, err = decodeCoupons(m, "coupon_refund_id", "coupon_type", "coupon_refund_fee", n); err != nil {
+			return
+		}
+		resp.Refunds = append(resp.Refunds, item)
+	}
+}
+
 // 退款查询.
+//
+// Deprecated: 使用 RefundQueryTyped, 它返回解析好的 *RefundQueryResponse.
 func (clt *Client) RefundQuery(req RefundQuery) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/pay/refundquery", req)
+	return clt.RefundQueryContext(context.Background(), req)
+}
+
+// Deprecated: 使用 RefundQueryTypedContext.
+func (clt *Client) RefundQueryContext(ctx context.Context, req RefundQuery) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/pay/refundquery"), req)
+}
+
+// 退款查询, 返回解析好的 *RefundQueryResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError; 其他错误时 resp 为 nil.
+func (clt *Client) RefundQueryTyped(req RefundQuery) (resp *RefundQueryResponse, err error) {
+	return clt.RefundQueryTypedContext(context.Background(), req)
+}
+
+func (clt *Client) RefundQueryTypedContext(ctx context.Context, req RefundQuery) (resp *RefundQueryResponse, err error) {
+	resp = new(RefundQueryResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/pay/refundquery"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
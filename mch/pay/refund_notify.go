@@ -0,0 +1,124 @@
+package pay
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/skynology/wechat/util"
+)
+
+// 退款结果通知, 除了 ReturnCode, ReturnMsg, AppId, MchId, NonceStr 都是 req_info 解密后的参数
+type RefundNotify struct {
+	ReturnCode          string `xml:"return_code"           json:"return_code"`
+	ReturnMsg           string `xml:"return_msg"            json:"return_msg"`
+	AppId               string `xml:"appid"                 json:"appid"`
+	MchId               string `xml:"mch_id"                json:"mch_id"`
+	NonceStr            string `xml:"nonce_str"             json:"nonce_str"`
+	TransactionId       string `xml:"transaction_id"        json:"transaction_id"`
+	OutTradeNo          string `xml:"out_trade_no"          json:"out_trade_no"`
+	RefundId            string `xml:"refund_id"             json:"refund_id"`
+	OutRefundNo         string `xml:"out_refund_no"         json:"out_refund_no"`
+	TotalFee            int    `xml:"total_fee"             json:"total_fee"`
+	SettlementTotalFee  int    `xml:"settlement_total_fee"  json:"settlement_total_fee"`
+	RefundFee           int    `xml:"refund_fee"            json:"refund_fee"`
+	SettlementRefundFee int    `xml:"settlement_refund_fee" json:"settlement_refund_fee"`
+	RefundStatus        string `xml:"refund_status"         json:"refund_status"` // RefundStatusSuccess, RefundStatusChange, RefundStatusRefundClose
+	SuccessTime         string `xml:"success_time"          json:"success_time"`  // 退款成功时间, 格式为 2017-12-15 09:46:01
+	RefundRecvAccount   string `xml:"refund_recv_accout"    json:"refund_recv_accout"`
+	RefundAccount       string `xml:"refund_account"        json:"refund_account"`
+	RefundRequestSource string `xml:"refund_request_source" json:"refund_request_source"`
+
+	// 通知的所有参数, req_info 替换为解密后的参数
+	Raw map[string]string `xml:"-" json:"-"`
+}
+
+// 退款是否成功
+func (notify *RefundNotify) Succeeded() bool {
+	return notify.RefundStatus == RefundStatusSuccess
+}
+
+// 解析退款结果通知的 http body, 校验 appid 和 mch_id, 解密 req_info.
+//  req_info 用 AES-256-ECB 加密, 密钥为 API密钥 的 md5 值(32位小写), PKCS#7 补位.
+//  NOTE: 一般直接使用 NewRefundNotifyHandler, 不需要调用这个函数.
+func (clt *Client) ParseRefundNotify(r io.Reader) (notify *RefundNotify, err error) {
+	m, err := clt.parseNotify(r, false)
+	if err != nil {
+		return
+	}
+	reqInfo, ok := m["req_info"]
+	if !ok {
+		err = errors.New("no req_info parameter")
+		return
+	}
+	info, err := clt.decryptReqInfo(reqInfo)
+	if err != nil {
+		return
+	}
+
+	delete(m, "req_info")
+	for k, v := range info {
+		m[k] = v
+	}
+	notify = &RefundNotify{Raw: m}
+	if err = decodeFields(m, notify); err != nil {
+		notify = nil
+		return
+	}
+	return
+}
+
+func (clt *Client) decryptReqInfo(reqInfo string) (info map[string]string, err error) {
+	encrypted, err := base64.StdEncoding.DecodeString(reqInfo)
+	if err != nil {
+		err = fmt.Errorf("invalid req_info: %v", err)
+		return
+	}
+	plain, err := util.AESECBDecrypt(encrypted, clt.reqInfoKey())
+	if err != nil {
+		err = fmt.Errorf("decrypt req_info failed: %v", err)
+		return
+	}
+	return util.ParseXMLToMap(bytes.NewReader(plain))
+}
+
+// req_info 的密钥: API密钥 的 md5 值(32位小写)
+func (clt *Client) reqInfoKey() []byte {
+	sum := md5.Sum([]byte(clt.apiKey))
+	key := make([]byte, hex.EncodedLen(len(sum)))
+	hex.Encode(key, sum[:])
+	return key
+}
+
+// 处理退款结果通知的 NotifyHandler, 按照 refund_id 去重, 用法同 NewNotifyHandler:
+//
+//    http.Handle("/pay/refund_notify", pay.NewRefundNotifyHandler(clt, func(ctx context.Context, notify *pay.RefundNotify) error {
+//        return updateRefund(notify.OutRefundNo, notify.RefundStatus)
+//    }))
+//
+//  NOTE: req_info 解密失败时回复 FAIL, 一般是 API密钥 不正确.
+func NewRefundNotifyHandler(clt *Client, handler func(ctx context.Context, notify *RefundNotify) error) *NotifyHandler {
+	return &NotifyHandler{
+		parse: func(r io.Reader) (notify interface{}, dedupeKey string, err error) {
+			refundNotify, err := clt.ParseRefundNotify(r)
+			if err != nil {
+				return
+			}
+			if refundNotify.RefundId != "" {
+				dedupeKey = "pay:" + refundNotify.MchId + ":refund:" + refundNotify.RefundId
+			}
+			notify = refundNotify
+			return
+		},
+		handle: func(ctx context.Context, notify interface{}) error {
+			return handler(ctx, notify.(*RefundNotify))
+		},
+		seenStore: util.NewMemorySeenStore(0),
+		dedupeTTL: DefaultNotifyDedupeTTL,
+	}
+}
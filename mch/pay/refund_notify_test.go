@@ -0,0 +1,80 @@
+package pay
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skynology/wechat/util"
+)
+
+func TestRefundNotifyHandler(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+
+	var notifies []*RefundNotify
+	handler := NewRefundNotifyHandler(clt, func(ctx context.Context, notify *RefundNotify) error {
+		notifies = append(notifies, notify)
+		return nil
+	})
+	post := func(reqInfo string) string {
+		var body bytes.Buffer
+		util.FormatMapToXML(&body, map[string]string{
+			"return_code": ReturnCodeSuccess,
+			"appid":       "appid",
+			"mch_id":      "mchid",
+			"nonce_str":   "TeqClE3i0mvn3DrK",
+			"req_info":    reqInfo,
+		})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay/refund_notify", &body))
+		resp, err := util.ParseXMLToMap(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp["return_code"]
+	}
+
+	var info bytes.Buffer
+	util.FormatMapToXML(&info, map[string]string{
+		"transaction_id":        "4200000001",
+		"out_trade_no":          "1001",
+		"refund_id":             "50000000001",
+		"out_refund_no":         "R1001",
+		"total_fee":             "300",
+		"refund_fee":            "100",
+		"settlement_refund_fee": "100",
+		"refund_status":         RefundStatusSuccess,
+		"success_time":          "2017-12-15 09:46:01",
+		"refund_recv_accout":    "支付用户零钱",
+	})
+	encrypted, err := util.AESECBEncrypt(info.Bytes(), clt.reqInfoKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqInfo := base64.StdEncoding.EncodeToString(encrypted)
+
+	for i := 0; i < 2; i++ {
+		if code := post(reqInfo); code != ReturnCodeSuccess {
+			t.Errorf("return_code = %s, want SUCCESS", code)
+		}
+	}
+	if len(notifies) != 1 {
+		t.Fatalf("handler called %d times, want 1", len(notifies))
+	}
+	n := notifies[0]
+	if n.RefundId != "50000000001" || n.OutRefundNo != "R1001" || n.SettlementRefundFee != 100 || n.RefundRecvAccount != "支付用户零钱" || !n.Succeeded() || n.AppId != "appid" {
+		t.Errorf("notify = %+v", n)
+	}
+	if _, ok := n.Raw["req_info"]; ok {
+		t.Errorf("raw = %v", n.Raw)
+	}
+
+	// 其他密钥加密的 req_info
+	encrypted, _ = util.AESECBEncrypt(info.Bytes(), []byte("0123456789abcdef0123456789abcdef"))
+	if code := post(base64.StdEncoding.EncodeToString(encrypted)); code != ReturnCodeFail {
+		t.Errorf("return_code = %s, want FAIL", code)
+	}
+}
@@ -0,0 +1,201 @@
+package pay
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skynology/wechat/util"
+)
+
+// 请求参数不完整, 比如缺少必填参数, 金额为 0; 这时请求不会发送.
+//  可以用 errors.Is(err, ErrIncompleteRequest) 判断.
+var ErrIncompleteRequest = errors.New("pay: incomplete request")
+
+// 接口的必填参数等信息, appid, mch_id, nonce_str, sign 由 Client 自动填写, 不需要列出.
+type apiSpec struct {
+	required []string                        // 必填参数, "a|b" 表示 a 和 b 至少填一个
+	md5Only  bool                            // 只支持 MD5 签名, 不加 sign_type
+	check    func(m map[string]string) error // 其他的检查, 可以为 nil
+	appIdKey string                          // appid 的参数名, 为 "" 时是 "appid"
+	mchIdKey string                          // mch_id 的参数名, 为 "" 时是 "mch_id"
+}
+
+var apiSpecs = map[string]apiSpec{
+	"/pay/unifiedorder": {
+		required: []string{"body", "out_trade_no", "total_fee", "spbill_create_ip", "notify_url", "trade_type"},
+		check:    checkUnifiedOrder,
+	},
+	"/pay/micropay": {
+		required: []string{"body", "out_trade_no", "total_fee", "spbill_create_ip", "auth_code"},
+	},
+	"/pay/orderquery":     {required: []string{"transaction_id|out_trade_no"}},
+	"/pay/closeorder":     {required: []string{"out_trade_no"}},
+	"/secapi/pay/refund":  {required: []string{"transaction_id|out_trade_no", "out_refund_no", "total_fee", "refund_fee"}},
+	"/pay/refundquery":    {required: []string{"transaction_id|out_trade_no|out_refund_no|refund_id"}},
+	"/secapi/pay/reverse": {required: []string{"transaction_id|out_trade_no"}},
+	"/pay/downloadbill":   {required: []string{"bill_date"}},
+	"/tools/shorturl":     {required: []string{"long_url"}},
+
+	// 企业付款和红包只支持 MD5 签名, 企业付款的参数名为 mch_appid 和 mchid, 红包的为 wxappid 和 mch_id
+	"/mmpaymkttransfers/promotion/transfers": {
+		required: []string{"partner_trade_no", "openid", "check_name", "amount", "desc", "spbill_create_ip"},
+		md5Only:  true,
+		appIdKey: "mch_appid",
+		mchIdKey: "mchid",
+	},
+	"/mmpaymkttransfers/gettransferinfo": {
+		required: []string{"partner_trade_no"},
+		md5Only:  true,
+		appIdKey: "mch_appid",
+		mchIdKey: "mchid",
+	},
+	"/mmpaymkttransfers/sendredpack": {
+		required: []string{"mch_billno", "send_name", "re_openid", "total_amount", "total_num", "wishing", "client_ip", "act_name", "remark"},
+		md5Only:  true,
+		appIdKey: "wxappid",
+	},
+	"/mmpaymkttransfers/sendgroupredpack": {
+		required: []string{"mch_billno", "send_name", "re_openid", "total_amount", "total_num", "amt_type", "wishing", "act_name", "remark"},
+		md5Only:  true,
+		appIdKey: "wxappid",
+	},
+}
+
+func checkUnifiedOrder(m map[string]string) error {
+	switch m["trade_type"] {
+	case "JSAPI":
+		if m["openid"] == "" && m["sub_openid"] == "" {
+			return errors.New("missing required parameter openid for trade_type JSAPI")
+		}
+	case "NATIVE":
+		if m["product_id"] == "" {
+			return errors.New("missing required parameter product_id for trade_type NATIVE")
+		}
+	}
+	return nil
+}
+
+// url 对应的接口的 apiSpec, 没有时返回 nil(比如通过 PostXML 调用的其他接口).
+func lookupAPISpec(url string) (path string, spec *apiSpec) {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		url = url[:i]
+	}
+	for path, spec := range apiSpecs {
+		if strings.HasSuffix(url, path) {
+			return path, &spec
+		}
+	}
+	return
+}
+
+// 接口的 appid 和 mch_id 的参数名, spec 为 nil 时为 "appid" 和 "mch_id".
+func (spec *apiSpec) idKeys() (appIdKey, mchIdKey string) {
+	appIdKey, mchIdKey = "appid", "mch_id"
+	if spec == nil {
+		return
+	}
+	if spec.appIdKey != "" {
+		appIdKey = spec.appIdKey
+	}
+	if spec.mchIdKey != "" {
+		mchIdKey = spec.mchIdKey
+	}
+	return
+}
+
+func (spec *apiSpec) validate(m map[string]string) error {
+	for _, required := range spec.required {
+		keys := strings.Split(required, "|")
+		found := false
+		for _, key := range keys {
+			if value := m[key]; value != "" {
+				if value == "0" && isAmountParameter(key) {
+					return fmt.Errorf("%s must be positive", key)
+				}
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("missing required parameter %s", strings.Join(keys, " or "))
+		}
+	}
+	if spec.check != nil {
+		return spec.check(m)
+	}
+	return nil
+}
+
+// 金额, 数量等必须大于 0 的参数; 请求的 struct 里 int 字段的零值序列化后为 "0".
+func isAmountParameter(key string) bool {
+	return strings.HasSuffix(key, "_fee") || strings.HasSuffix(key, "amount") || key == "total_num"
+}
+
+// 序列化请求, request 为请求的 struct 或者 map[string]string, 返回请求的签名类型.
+//  1. appid(企业付款为 mch_appid, 红包为 wxappid), mch_id(企业付款为 mchid), nonce_str 为空时自动填写,
+//     参数名由 url 对应的接口决定, 其他接口为 appid 和 mch_id;
+//  2. 请求没有 sign_type 并且 SetSignType 设置了 SignTypeHMACSHA256 时, 加上 sign_type(企业付款和红包除外);
+//  3. sign 为空或者自动填写了以上参数时, 按照实际发送的参数重新签名;
+//  4. 检查接口的必填参数, 不完整时返回 ErrIncompleteRequest.
+func (clt *Client) marshalRequest(url string, request interface{}) (body []byte, signType string, err error) {
+	var m map[string]string
+	if req, ok := request.(map[string]string); ok {
+		m = make(map[string]string, len(req)+4)
+		for k, v := range req {
+			m[k] = v
+		}
+	} else {
+		var b []byte
+		if b, err = xml.Marshal(request); err != nil {
+			return
+		}
+		if m, err = util.ParseXMLToMap(bytes.NewReader(b)); err != nil {
+			return
+		}
+	}
+	path, spec := lookupAPISpec(url)
+
+	filled := false
+	fill := func(key, value string) {
+		if m[key] == "" {
+			m[key] = value
+			filled = true
+		}
+	}
+	appIdKey, mchIdKey := spec.idKeys()
+	fill(appIdKey, clt.appId)
+	fill(mchIdKey, clt.mchId)
+	fill("nonce_str", util.RandString(32))
+
+	signType = m["sign_type"]
+	if signType == "" && clt.signType != "" && clt.signType != SignTypeMD5 && (spec == nil || !spec.md5Only) {
+		signType = clt.signType
+		m["sign_type"] = signType
+		filled = true
+	}
+	if signType == "" {
+		signType = SignTypeMD5
+	}
+	if m["sign"] == "" || filled {
+		m["sign"] = clt.sign(m, signType)
+	}
+
+	if spec != nil {
+		if err = spec.validate(m); err != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrIncompleteRequest, path, err)
+			return
+		}
+	}
+
+	bodyBuf := textBufferPool.Get().(*bytes.Buffer)
+	bodyBuf.Reset()
+	defer textBufferPool.Put(bodyBuf)
+
+	if err = util.FormatMapToXML(bodyBuf, m); err != nil {
+		return
+	}
+	body = append([]byte(nil), bodyBuf.Bytes()...)
+	return
+}
@@ -0,0 +1,89 @@
+package pay
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skynology/wechat/util"
+)
+
+func TestMarshalRequest(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+	if err := clt.SetSignType(SignTypeHMACSHA256); err != nil {
+		t.Fatal(err)
+	}
+
+	var reqs []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, _ := util.ParseXMLToMap(r.Body)
+		reqs = append(reqs, req)
+		resp := map[string]string{"return_code": ReturnCodeSuccess, "result_code": ResultCodeSuccess}
+		resp["sign"] = clt.SignWithType(resp, req["sign_type"])
+		util.FormatMapToXML(w, resp)
+	}))
+	defer server.Close()
+	clt.SetBaseURL(server.URL)
+
+	// 自动填写 appid, mch_id, nonce_str, sign_type 和 sign
+	if _, err := clt.Refund(Refund{OutTradeNo: "1001", OutRefundNo: "R1001", TotalFee: 100, RefundFee: 100}); err != nil {
+		t.Fatal(err)
+	}
+	// 企业付款的参数名为 mch_appid 和 mchid, 只支持 MD5 签名
+	if _, err := clt.Transfer(Transfer{ParnetTradeNo: "T1001", OpenId: "openid", CheckName: "NO_CHECK", Amount: 100, Description: "desc", SpbillCreateIP: "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	// 红包的参数名为 wxappid, 请求里没有时同样自动填写
+	if _, err := clt.SendRedPack(map[string]string{
+		"mch_billno":   "B1001",
+		"send_name":    "商户",
+		"re_openid":    "openid",
+		"total_amount": "100",
+		"total_num":    "1",
+		"wishing":      "恭喜发财",
+		"client_ip":    "127.0.0.1",
+		"act_name":     "活动",
+		"remark":       "备注",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 3 {
+		t.Fatalf("requests = %d, want 3", len(reqs))
+	}
+	if req := reqs[0]; req["appid"] != "appid" || req["mch_id"] != "mchid" || len(req["nonce_str"]) != 32 ||
+		req["sign_type"] != SignTypeHMACSHA256 || req["sign"] != clt.Sign(req) {
+		t.Errorf("refund request = %v", req)
+	}
+	if req := reqs[1]; req["mch_appid"] != "appid" || req["mchid"] != "mchid" || req["appid"] != "" ||
+		req["sign_type"] != "" || req["sign"] != clt.Sign(req) {
+		t.Errorf("transfer request = %v", req)
+	}
+	if req := reqs[2]; req["wxappid"] != "appid" || req["mch_id"] != "mchid" || req["appid"] != "" ||
+		req["sign_type"] != "" || req["sign"] != clt.Sign(req) {
+		t.Errorf("redpack request = %v", req)
+	}
+
+	// 缺少必填参数时不发送请求
+	for _, tc := range []struct {
+		name string
+		call func() error
+	}{
+		{"OrderQuery", func() error { _, err := clt.OrderQuery(OrderQuery{}); return err }},
+		{"Refund", func() error {
+			_, err := clt.Refund(Refund{OutTradeNo: "1001", OutRefundNo: "R1001", TotalFee: 100})
+			return err
+		}},
+		{"UnifiedOrder", func() error {
+			_, err := clt.UnifiedOrder(UnifiedOrder{Body: "test", OutTradeNo: "1001", TotalFee: 1, SpbillCreateIP: "127.0.0.1", NotifyURL: "https://example.com/notify", TradeType: "JSAPI"})
+			return err
+		}},
+	} {
+		if err := tc.call(); !errors.Is(err, ErrIncompleteRequest) {
+			t.Errorf("%s: err = %v, want ErrIncompleteRequest", tc.name, err)
+		}
+	}
+	if len(reqs) != 3 {
+		t.Errorf("requests = %d, want 3", len(reqs))
+	}
+}
@@ -0,0 +1,137 @@
+package pay
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// 各个接口返回的公共参数
+type CommonResponse struct {
+	AppId      string `xml:"appid"        json:"appid"`
+	MchId      string `xml:"mch_id"       json:"mch_id"`
+	DeviceInfo string `xml:"device_info"  json:"device_info"`
+	NonceStr   string `xml:"nonce_str"    json:"nonce_str"`
+	Sign       string `xml:"sign"         json:"sign"`
+	ResultCode string `xml:"result_code"  json:"result_code"`
+	ErrCode    string `xml:"err_code"     json:"err_code"`
+	ErrCodeDes string `xml:"err_code_des" json:"err_code_des"`
+
+	// 返回的所有参数, 包括没有对应字段的参数(比如微信支付新增的参数)
+	Raw map[string]string `xml:"-" json:"-"`
+}
+
+func (resp *CommonResponse) setRaw(m map[string]string) {
+	resp.Raw = m
+}
+
+// 代金券或立减优惠
+type Coupon struct {
+	Id   string `json:"coupon_id"`   // 代金券或立减优惠ID
+	Type string `json:"coupon_type"` // CASH--充值代金券, NO_CASH--非充值代金券
+	Fee  int    `json:"coupon_fee"`  // 单个代金券或立减优惠支付金额
+}
+
+type responseDecoder interface {
+	setRaw(m map[string]string)
+	decode(m map[string]string) error
+}
+
+// POST request 到 url, 把返回的参数解析到 resp.
+//  业务结果为 FAIL 时 resp 同样会被解析, 返回 *ResultError.
+func (clt *Client) postXMLTo(ctx context.Context, url string, request interface{}, checkSign bool, resp responseDecoder) (err error) {
+	b, signType, err := clt.marshalRequest(url, request)
+	if err != nil {
+		return
+	}
+	if !checkSign {
+		signType = ""
+	}
+	m, err := clt.postXMLWithRetry(ctx, url, b, signType)
+	if err != nil {
+		return
+	}
+
+	resp.setRaw(m)
+	if err = resp.decode(m); err != nil {
+		return
+	}
+	if m["result_code"] == ResultCodeFail {
+		err = &ResultError{ErrCode: m["err_code"], ErrCodeDes: m["err_code_des"]}
+	}
+	return
+}
+
+func isResultError(err error) bool {
+	_, ok := err.(*ResultError)
+	return ok
+}
+
+// 按照 xml tag 把 m 里的参数解析到 v 的 string, int, int64 字段, 包括嵌入的结构体.
+//  下标形式的参数(比如 coupon_fee_$n)由各个类型自己解析.
+func decodeFields(m map[string]string, v interface{}) error {
+	return decodeStruct(m, reflect.ValueOf(v).Elem())
+}
+
+func decodeStruct(m map[string]string, v reflect.Value) (err error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err = decodeStruct(m, v.Field(i)); err != nil {
+				return
+			}
+			continue
+		}
+
+		key, _ := parseTag(field.Tag.Get("xml"))
+		if key == "" || key == "-" || key == "xml" {
+			continue
+		}
+		value, ok := m[key]
+		if !ok || value == "" {
+			continue
+		}
+		switch fv := v.Field(i); fv.Kind() {
+		case reflect.String:
+			fv.SetString(value)
+		case reflect.Int, reflect.Int64:
+			var n int64
+			if n, err = strconv.ParseInt(value, 10, 64); err != nil {
+				err = fmt.Errorf("invalid %s: %q", key, value)
+				return
+			}
+			fv.SetInt(n)
+		}
+	}
+	return
+}
+
+// 解析整数参数, 没有这个参数时返回 0
+func intValue(m map[string]string, key string) (n int, err error) {
+	value := m[key]
+	if value == "" {
+		return
+	}
+	if n, err = strconv.Atoi(value); err != nil {
+		err = fmt.Errorf("invalid %s: %q", key, value)
+	}
+	return
+}
+
+// 解析 coupon_id_$n, coupon_type_$n, coupon_fee_$n 这样的参数, 退款查询的 coupon_refund_id_$n_$m 等参数 suffix 为 "_$n"
+func decodeCoupons(m map[string]string, idKey, typeKey, feeKey, suffix string) (coupons []Coupon, err error) {
+	for i := 0; ; i++ {
+		index := suffix + "_" + strconv.Itoa(i)
+		id, ok := m[idKey+index]
+		if !ok {
+			return
+		}
+		coupon := Coupon{Id: id, Type: m[typeKey+index]}
+		if coupon.Fee, err = intValue(m, feeKey+index); err != nil {
+			return
+		}
+		coupons = append(coupons, coupon)
+	}
+}
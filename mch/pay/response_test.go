@@ -0,0 +1,109 @@
+package pay
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skynology/wechat/util"
+)
+
+func newResponseServer(clt *Client, m map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m["sign"] = clt.Sign(m)
+		util.FormatMapToXML(w, m)
+	}))
+}
+
+func TestRefundQueryResponse(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+	server := newResponseServer(clt, map[string]string{
+		"return_code":            ReturnCodeSuccess,
+		"result_code":            ResultCodeSuccess,
+		"appid":                  "appid",
+		"transaction_id":         "4200000001",
+		"total_fee":              "300",
+		"refund_count":           "2",
+		"out_refund_no_0":        "R0",
+		"refund_fee_0":           "100",
+		"refund_status_0":        RefundStatusSuccess,
+		"coupon_refund_count_0":  "2",
+		"coupon_refund_id_0_0":   "C0",
+		"coupon_refund_fee_0_0":  "10",
+		"coupon_refund_id_0_1":   "C1",
+		"coupon_refund_fee_0_1":  "20",
+		"out_refund_no_1":        "R1",
+		"refund_fee_1":           "200",
+		"refund_status_1":        RefundStatusProcessing,
+		"refund_recv_accout_1":   "招商银行信用卡0403",
+		"new_field_from_upgrade": "x",
+	})
+	defer server.Close()
+	clt.SetBaseURL(server.URL)
+
+	resp, err := clt.RefundQueryTyped(RefundQuery{OutTradeNo: "1001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AppId != "appid" || resp.TransactionId != "4200000001" || resp.TotalFee != 300 || resp.RefundCount != 2 {
+		t.Errorf("resp = %+v", resp)
+	}
+	if len(resp.Refunds) != 2 {
+		t.Fatalf("refunds = %+v", resp.Refunds)
+	}
+	if item := resp.Refunds[0]; item.OutRefundNo != "R0" || item.RefundFee != 100 || len(item.Coupons) != 2 || item.Coupons[1].Id != "C1" || item.Coupons[1].Fee != 20 {
+		t.Errorf("refunds[0] = %+v", item)
+	}
+	if item := resp.Refunds[1]; item.RefundStatus != RefundStatusProcessing || item.RefundRecvAccount != "招商银行信用卡0403" || item.Coupons != nil {
+		t.Errorf("refunds[1] = %+v", item)
+	}
+	if resp.Raw["new_field_from_upgrade"] != "x" {
+		t.Errorf("raw = %v", resp.Raw)
+	}
+}
+
+func TestResultError(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+	server := newResponseServer(clt, map[string]string{
+		"return_code":  ReturnCodeSuccess,
+		"result_code":  ResultCodeFail,
+		"err_code":     ErrCodeOrderNotExist,
+		"err_code_des": "此交易订单号不存在",
+	})
+	defer server.Close()
+	clt.SetBaseURL(server.URL)
+
+	resp, err := clt.OrderQueryTyped(OrderQuery{OutTradeNo: "1001"})
+	if !errors.Is(err, ErrOrderNotExist) {
+		t.Fatalf("err = %v, want ErrOrderNotExist", err)
+	}
+	var resultErr *ResultError
+	if !errors.As(err, &resultErr) || resultErr.ErrCodeDes != "此交易订单号不存在" {
+		t.Errorf("err = %#v", err)
+	}
+	if resp == nil || resp.ErrCode != ErrCodeOrderNotExist {
+		t.Errorf("resp = %+v", resp)
+	}
+
+	// 原来返回 map 的方法不变: 业务结果为 FAIL 时不返回错误
+	m, err := clt.OrderQuery(OrderQuery{OutTradeNo: "1001"})
+	if err != nil || m["err_code"] != ErrCodeOrderNotExist {
+		t.Errorf("OrderQuery = %v, %v", m, err)
+	}
+}
+
+func TestDownloadBillHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	clt := NewClient("appid", "mchid", "apikey")
+	clt.SetBaseURL(server.URL)
+
+	_, err := clt.DownloadBill(map[string]string{"bill_date": "20260101", "bill_type": "ALL"})
+	var httpErr *util.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want *util.HTTPError", err)
+	}
+}
@@ -7,22 +7,36 @@ package pay
 
 import (
 	"bytes"
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"reflect"
 	"sort"
 	"strings"
 )
 
+const (
+	SignTypeMD5        = "MD5"
+	SignTypeHMACSHA256 = "HMAC-SHA256"
+)
+
 // 微信支付签名.
-//  parameters: 待签名的参数
-//  apiKey:     API密钥
-//  fn:         func() hash.Hash, 如果 fn == nil 则默认用 md5.New
+//  data: 待签名的参数, map[string]string 或者请求的 struct
+//  签名类型为参数里的 sign_type, 没有 sign_type 时为 MD5(和微信支付一致).
 func (cli *Client) Sign(data interface{}) string {
 	parameters := convertStructToMap(data)
-	//	fmt.Println("sign param:", parameters)
+	return cli.sign(parameters, parameters["sign_type"])
+}
+
+// 用 signType 签名, 忽略参数里的 sign_type; signType 不是 SignTypeHMACSHA256 时使用 MD5.
+func (cli *Client) SignWithType(data interface{}, signType string) string {
+	return cli.sign(convertStructToMap(data), signType)
+}
 
+func (cli *Client) sign(parameters map[string]string, signType string) string {
 	ks := make([]string, 0, len(parameters))
 	for k := range parameters {
 		if k == "sign" {
@@ -32,7 +46,12 @@ func (cli *Client) Sign(data interface{}) string {
 	}
 	sort.Strings(ks)
 
-	h := md5.New()
+	var h hash.Hash
+	if signType == SignTypeHMACSHA256 {
+		h = hmac.New(sha256.New, []byte(cli.apiKey))
+	} else {
+		h = md5.New()
+	}
 	signature := make([]byte, h.Size()*2)
 
 	for _, k := range ks {
@@ -52,6 +71,10 @@ func (cli *Client) Sign(data interface{}) string {
 	return string(bytes.ToUpper(signature))
 }
 
+func isValidSignType(signType string) bool {
+	return signType == SignTypeMD5 || signType == SignTypeHMACSHA256
+}
+
 // 把支付相关函数所用到的struct参数转为map, 以便排序并加sign
 func convertStructToMap(data interface{}) map[string]string {
 	if check, ok := data.(map[string]string); ok {
@@ -0,0 +1,72 @@
+package pay
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skynology/wechat/util"
+)
+
+func TestSign(t *testing.T) {
+	// 微信支付文档 "安全规范" 里签名算法的示例
+	clt := NewClient("wxd930ea5d5a258f4f", "10000100", "192006250b4c09247ec02edce69f6a2d")
+	m := map[string]string{
+		"appid":       "wxd930ea5d5a258f4f",
+		"mch_id":      "10000100",
+		"device_info": "1000",
+		"body":        "test",
+		"nonce_str":   "ibuaiVcKdpRxkhJA",
+	}
+	if sign := clt.Sign(m); sign != "9A0A8659F005D6984697E2CA0A9CF3B7" {
+		t.Errorf("MD5 sign = %s", sign)
+	}
+	if sign := clt.SignWithType(m, SignTypeHMACSHA256); sign != "6A9AE1657590FD6257D693A078E1C3E4BB6BA4DC30B23E0EE2496E54170DACD6" {
+		t.Errorf("HMAC-SHA256 sign = %s", sign)
+	}
+
+	// 参数里声明的 sign_type 同样参与签名
+	m["sign_type"] = SignTypeHMACSHA256
+	if sign := clt.Sign(m); sign != clt.SignWithType(m, SignTypeHMACSHA256) {
+		t.Errorf("sign = %s", sign)
+	}
+
+	if err := clt.SetSignType("SHA1"); err == nil {
+		t.Error("want unsupported sign_type error")
+	}
+}
+
+func TestSignTypeHMACSHA256(t *testing.T) {
+	clt := NewClient("appid", "mchid", "apikey")
+	if err := clt.SetSignType(SignTypeHMACSHA256); err != nil {
+		t.Fatal(err)
+	}
+
+	var req map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, _ = util.ParseXMLToMap(r.Body)
+		// 返回结果没有 sign_type, 签名类型和请求一致
+		resp := map[string]string{
+			"return_code": ReturnCodeSuccess,
+			"result_code": ResultCodeSuccess,
+			"short_url":   "weixin://wxpay/s/XXXXXX",
+		}
+		resp["sign"] = clt.SignWithType(resp, SignTypeHMACSHA256)
+		util.FormatMapToXML(w, resp)
+	}))
+	defer server.Close()
+	clt.SetBaseURL(server.URL)
+
+	shortURL := ShortURL{AppId: "appid", MchId: "mchid", LongURL: "weixin://wxpay/bizpayurl?pr=XXXXXX", NonceStr: "nonce"}
+	shortURL.Sign = clt.Sign(shortURL)
+	resp, err := clt.ShortURLTyped(shortURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ShortURL != "weixin://wxpay/s/XXXXXX" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if req["sign_type"] != SignTypeHMACSHA256 || req["sign"] != clt.SignWithType(req, SignTypeHMACSHA256) {
+		t.Errorf("req = %v", req)
+	}
+}
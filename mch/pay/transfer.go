@@ -1,5 +1,7 @@
 package pay
 
+import "context"
+
 type Transfer struct {
 	XMLName        struct{} `xml:"xml" json:"-"`
 	AppId          string   `xml:"mch_appid"   json:"mch_appid"`
@@ -16,10 +18,47 @@ type Transfer struct {
 	SpbillCreateIP string   `xml:"spbill_create_ip" json:"spbill_create_ip"`
 }
 
+// 企业付款的返回参数
+//  NOTE: 企业付款返回的是 mch_appid 和 mchid, CommonResponse 的 AppId 和 MchId 为空.
+type TransferResponse struct {
+	CommonResponse
+	MchAppId       string `xml:"mch_appid"        json:"mch_appid"`
+	MchIdentity    string `xml:"mchid"            json:"mchid"`
+	PartnerTradeNo string `xml:"partner_trade_no" json:"partner_trade_no"` // 商户订单号
+	PaymentNo      string `xml:"payment_no"       json:"payment_no"`       // 企业付款成功, 返回的微信订单号
+	PaymentTime    string `xml:"payment_time"     json:"payment_time"`     // 企业付款成功时间
+}
+
+func (resp *TransferResponse) decode(m map[string]string) error {
+	return decodeFields(m, resp)
+}
+
 // 企业付款
 //  NOTE: 请求需要双向证书.
+//
+// Deprecated: 使用 TransferTyped, 它返回解析好的 *TransferResponse.
 func (clt *Client) Transfer(req Transfer) (resp map[string]string, err error) {
-	return clt.PostXMLWithoutSign("https://api.mch.weixin.qq.com/mmpaymkttransfers/promotion/transfers", req)
+	return clt.TransferContext(context.Background(), req)
+}
+
+// Deprecated: 使用 TransferTypedContext.
+func (clt *Client) TransferContext(ctx context.Context, req Transfer) (resp map[string]string, err error) {
+	return clt.PostXMLWithoutSignContext(ctx, clt.apiURL("/mmpaymkttransfers/promotion/transfers"), req)
+}
+
+// 企业付款, 返回解析好的 *TransferResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError(比如 ErrSendFailed 需要查单确认); 其他错误时 resp 为 nil.
+//  NOTE: 请求需要双向证书.
+func (clt *Client) TransferTyped(req Transfer) (resp *TransferResponse, err error) {
+	return clt.TransferTypedContext(context.Background(), req)
+}
+
+func (clt *Client) TransferTypedContext(ctx context.Context, req Transfer) (resp *TransferResponse, err error) {
+	resp = new(TransferResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/mmpaymkttransfers/promotion/transfers"), req, false, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
 
 type TransferQuery struct {
@@ -34,5 +73,9 @@ type TransferQuery struct {
 // 查询企业付款
 //  NOTE: 请求需要双向证书.
 func (clt *Client) FindTransfer(req TransferQuery) (resp map[string]string, err error) {
-	return clt.PostXMLWithoutSign("https://api.mch.weixin.qq.com/mmpaymkttransfers/gettransferinfo", req)
+	return clt.FindTransferContext(context.Background(), req)
+}
+
+func (clt *Client) FindTransferContext(ctx context.Context, req TransferQuery) (resp map[string]string, err error) {
+	return clt.PostXMLWithoutSignContext(ctx, clt.apiURL("/mmpaymkttransfers/gettransferinfo"), req)
 }
@@ -1,5 +1,7 @@
 package pay
 
+import "context"
+
 // 统一下单
 type UnifiedOrder struct {
 	XMLName        struct{} `xml:"xml" json:"-"`
@@ -8,6 +10,7 @@ type UnifiedOrder struct {
 	DeviceInfo     string   `xml:"device_info" json:"device_info"`
 	NonceStr       string   `xml:"nonce_str" json:"nonce_str"`
 	Sign           string   `xml:"sign" json:"sign"`
+	SignType       string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
 	Body           string   `xml:"body" json:"body"`
 	Detail         string   `xml:"detail,omitempty" json:"detail,omitempty"`
 	Attach         string   `xml:"attach,omitempty" json:"attach,omitempty"`
@@ -24,7 +27,41 @@ type UnifiedOrder struct {
 	OpenId         string   `xml:"openid,omitempty" json:"openid,omitempty"`
 }
 
+// 统一下单的返回参数
+type UnifiedOrderResponse struct {
+	CommonResponse
+	TradeType string `xml:"trade_type" json:"trade_type"` // 交易类型
+	PrepayId  string `xml:"prepay_id"  json:"prepay_id"`  // 预支付交易会话标识, 有效期为2小时
+	CodeURL   string `xml:"code_url"   json:"code_url"`   // trade_type 为 NATIVE 时返回的二维码链接
+	MWebURL   string `xml:"mweb_url"   json:"mweb_url"`   // trade_type 为 MWEB 时返回的支付跳转链接
+}
+
+func (resp *UnifiedOrderResponse) decode(m map[string]string) error {
+	return decodeFields(m, resp)
+}
+
 // 统一下单.
+//
+// Deprecated: 使用 UnifiedOrderTyped, 它返回解析好的 *UnifiedOrderResponse.
 func (clt *Client) UnifiedOrder(req UnifiedOrder) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/pay/unifiedorder", req)
+	return clt.UnifiedOrderContext(context.Background(), req)
+}
+
+// Deprecated: 使用 UnifiedOrderTypedContext.
+func (clt *Client) UnifiedOrderContext(ctx context.Context, req UnifiedOrder) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/pay/unifiedorder"), req)
+}
+
+// 统一下单, 返回解析好的 *UnifiedOrderResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError; 其他错误时 resp 为 nil.
+func (clt *Client) UnifiedOrderTyped(req UnifiedOrder) (resp *UnifiedOrderResponse, err error) {
+	return clt.UnifiedOrderTypedContext(context.Background(), req)
+}
+
+func (clt *Client) UnifiedOrderTypedContext(ctx context.Context, req UnifiedOrder) (resp *UnifiedOrderResponse, err error) {
+	resp = new(UnifiedOrderResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/pay/unifiedorder"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
@@ -1,8 +1,11 @@
 package pay
 
-import "net/url"
+import (
+	"context"
+	"net/url"
+)
 
-// 统一下单
+// 转换短链接
 type ShortURL struct {
 	XMLName  struct{} `xml:"xml" json:"-"`
 	AppId    string   `xml:"appid"   json:"appid"`
@@ -10,11 +13,43 @@ type ShortURL struct {
 	LongURL  string   `xml:"long_url" json:"long_url"`
 	NonceStr string   `xml:"nonce_str" json:"nonce_str"`
 	Sign     string   `xml:"sign" json:"sign"`
+	SignType string   `xml:"sign_type,omitempty" json:"sign_type,omitempty"`
+}
+
+// 转换短链接的返回参数
+type ShortURLResponse struct {
+	CommonResponse
+	ShortURL string `xml:"short_url" json:"short_url"` // 转换后的URL
+}
+
+func (resp *ShortURLResponse) decode(m map[string]string) error {
+	return decodeFields(m, resp)
 }
 
 // 转换短链接.
+//
+// Deprecated: 使用 ShortURLTyped, 它返回解析好的 *ShortURLResponse.
 func (clt *Client) ShortURL(req ShortURL) (resp map[string]string, err error) {
-	return clt.PostXML("https://api.mch.weixin.qq.com/tools/shorturl", req)
+	return clt.ShortURLContext(context.Background(), req)
+}
+
+// Deprecated: 使用 ShortURLTypedContext.
+func (clt *Client) ShortURLContext(ctx context.Context, req ShortURL) (resp map[string]string, err error) {
+	return clt.PostXMLContext(ctx, clt.apiURL("/tools/shorturl"), req)
+}
+
+// 转换短链接, 返回解析好的 *ShortURLResponse.
+//  业务结果为 FAIL 时同时返回 resp 和 *ResultError; 其他错误时 resp 为 nil.
+func (clt *Client) ShortURLTyped(req ShortURL) (resp *ShortURLResponse, err error) {
+	return clt.ShortURLTypedContext(context.Background(), req)
+}
+
+func (clt *Client) ShortURLTypedContext(ctx context.Context, req ShortURL) (resp *ShortURLResponse, err error) {
+	resp = new(ShortURLResponse)
+	if err = clt.postXMLTo(ctx, clt.apiURL("/tools/shorturl"), req, true, resp); err != nil && !isResultError(err) {
+		resp = nil
+	}
+	return
 }
 
 // 生成二维码规则
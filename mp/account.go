@@ -1,11 +1,15 @@
 package mp
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+
+	"github.com/skynology/wechat/util"
 )
 
 const (
@@ -24,8 +28,13 @@ type PermanentQRCode struct {
 }
 
 // 二维码图片的URL, 可以GET此URL下载二维码或者在线显示此二维码.
+//  NOTE: 总是使用 DefaultMPBaseURL, 需要替换域名时使用 Client.QRCodeURL.
 func (qrcode *PermanentQRCode) PicURL() string {
-	return "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=" + url.QueryEscape(qrcode.Ticket)
+	return qrcodeURL(DefaultMPBaseURL, qrcode.Ticket)
+}
+
+func qrcodeURL(mpBaseURL, ticket string) string {
+	return mpBaseURL + "/cgi-bin/showqrcode?ticket=" + url.QueryEscape(ticket)
 }
 
 // 临时二维码
@@ -34,15 +43,19 @@ type TemporaryQRCode struct {
 	ExpiresIn int `json:"expire_seconds"` // 二维码的有效时间，以秒为单位。
 }
 
-// 二维码图片的URL, 可以GET此URL下载二维码或者在线显示此二维码.
+// 二维码图片的URL, 可以GET此URL下载二维码或者在线显示此二维码, 域名由 SetMPBaseURL 设置.
 func (clt *Client) QRCodeURL(ticket string) string {
-	return "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=" + url.QueryEscape(ticket)
+	return qrcodeURL(clt.mpBaseURL, ticket)
 }
 
 // 创建临时二维码
 //  SceneId:       场景值ID，为32位非0整型
 //  ExpireSeconds: 二维码的有效时间，以秒为单位。
 func (clt *Client) CreateTemporaryQRCode(SceneId int, ExpireSeconds int) (qrcode *TemporaryQRCode, err error) {
+	return clt.CreateTemporaryQRCodeContext(context.Background(), SceneId, ExpireSeconds)
+}
+
+func (clt *Client) CreateTemporaryQRCodeContext(ctx context.Context, SceneId int, ExpireSeconds int) (qrcode *TemporaryQRCode, err error) {
 	var request struct {
 		ExpireSeconds int    `json:"expire_seconds"`
 		ActionName    string `json:"action_name"`
@@ -63,8 +76,8 @@ func (clt *Client) CreateTemporaryQRCode(SceneId int, ExpireSeconds int) (qrcode
 		TemporaryQRCode
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/qrcode/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -80,6 +93,10 @@ func (clt *Client) CreateTemporaryQRCode(SceneId int, ExpireSeconds int) (qrcode
 // 创建永久二维码
 //  SceneId: 场景值ID，最大值为100000（目前参数只支持1--100000）
 func (clt *Client) CreatePermanentQRCode(SceneId int) (qrcode *PermanentQRCode, err error) {
+	return clt.CreatePermanentQRCodeContext(context.Background(), SceneId)
+}
+
+func (clt *Client) CreatePermanentQRCodeContext(ctx context.Context, SceneId int) (qrcode *PermanentQRCode, err error) {
 	var request struct {
 		ActionName string `json:"action_name"`
 		ActionInfo struct {
@@ -96,8 +113,8 @@ func (clt *Client) CreatePermanentQRCode(SceneId int) (qrcode *PermanentQRCode,
 		PermanentQRCode
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/qrcode/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -113,6 +130,10 @@ func (clt *Client) CreatePermanentQRCode(SceneId int) (qrcode *PermanentQRCode,
 // 创建永久二维码
 //  SceneString: 场景值ID（字符串形式的ID），字符串类型，长度限制为1到64
 func (clt *Client) CreatePermanentQRCodeWithSceneString(SceneString string) (qrcode *PermanentQRCode, err error) {
+	return clt.CreatePermanentQRCodeWithSceneStringContext(context.Background(), SceneString)
+}
+
+func (clt *Client) CreatePermanentQRCodeWithSceneStringContext(ctx context.Context, SceneString string) (qrcode *PermanentQRCode, err error) {
 	var request struct {
 		ActionName string `json:"action_name"`
 		ActionInfo struct {
@@ -129,8 +150,8 @@ func (clt *Client) CreatePermanentQRCodeWithSceneString(SceneString string) (qrc
 		PermanentQRCode
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/qrcode/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -144,20 +165,33 @@ func (clt *Client) CreatePermanentQRCodeWithSceneString(SceneString string) (qrc
 }
 
 // 通过ticket换取二维码, 写入到 writer.
+//  do 用于发送请求, policy 为 nil 时不重试; 只在收到二维码之前重试, 不会向 writer 写入重复的数据.
 //  NOTE: 调用者保证所有参数有效.
-func qrcodeDownloadToWriter(ticket string, writer io.Writer, httpClient *http.Client) (err error) {
-	qrcodeURL := "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=" + url.QueryEscape(ticket)
-	httpResp, err := httpClient.Get(qrcodeURL)
-	if err != nil {
+func qrcodeDownloadToWriter(ctx context.Context, qrcodeURL string, writer io.Writer,
+	do func(*http.Request) (*http.Response, error), policy *util.RetryPolicy) (err error) {
+
+	var httpResp *http.Response
+	err = policy.Do(ctx, func() (err error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", qrcodeURL, nil)
+		if err != nil {
+			return
+		}
+		if httpResp, err = do(httpReq); err != nil {
+			return
+		}
+		if httpResp.StatusCode != http.StatusOK {
+			httpResp.Body.Close()
+			return &util.HTTPError{StatusCode: httpResp.StatusCode, Status: httpResp.Status}
+		}
 		return
+	})
+	if err != nil {
+		return fmt.Errorf("下载二维码出错: %w", err)
 	}
 	defer httpResp.Body.Close()
 
-	if httpResp.StatusCode == http.StatusOK {
-		_, err = io.Copy(writer, httpResp.Body)
-		return
-	}
-	return errors.New("下载二维码出错, ticket: " + ticket)
+	_, err = io.Copy(writer, httpResp.Body)
+	return
 }
 
 // 通过ticket换取二维码, 写入到 writer.
@@ -169,18 +203,23 @@ func QRCodeDownloadToWriter(ticket string, writer io.Writer, httpClient *http.Cl
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return qrcodeDownloadToWriter(ticket, writer, httpClient)
+	return qrcodeDownloadToWriter(context.Background(), qrcodeURL(DefaultMPBaseURL, ticket), writer, httpClient.Do, nil)
 }
 
 // 通过ticket换取二维码, 写入到 writer.
 func (clt *Client) QRCodeDownloadToWriter(ticket string, writer io.Writer) (err error) {
+	return clt.QRCodeDownloadToWriterContext(context.Background(), ticket, writer)
+}
+
+// 同 QRCodeDownloadToWriter, 请求经过 SetInterceptors 设置的拦截器, 并且按照 SetRetryPolicy 设置的策略重试.
+func (clt *Client) QRCodeDownloadToWriterContext(ctx context.Context, ticket string, writer io.Writer) (err error) {
 	if writer == nil {
 		return errors.New("nil writer")
 	}
 	if clt.httpClient == nil {
 		clt.httpClient = http.DefaultClient
 	}
-	return qrcodeDownloadToWriter(ticket, writer, clt.httpClient)
+	return qrcodeDownloadToWriter(ctx, clt.QRCodeURL(ticket), writer, clt.do, clt.retryPolicy)
 }
 
 // 通过ticket换取二维码, 写入到 filepath 路径的文件.
@@ -195,21 +234,22 @@ func QRCodeDownload(ticket, filepath string, httpClient *http.Client) (err error
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return qrcodeDownloadToWriter(ticket, file, httpClient)
+	return qrcodeDownloadToWriter(context.Background(), qrcodeURL(DefaultMPBaseURL, ticket), file, httpClient.Do, nil)
 }
 
 // 通过ticket换取二维码, 写入到 filepath 路径的文件.
 func (clt *Client) QRCodeDownload(ticket, filepath string) (err error) {
+	return clt.QRCodeDownloadContext(context.Background(), ticket, filepath)
+}
+
+func (clt *Client) QRCodeDownloadContext(ctx context.Context, ticket, filepath string) (err error) {
 	file, err := os.Create(filepath)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
-	if clt.httpClient == nil {
-		clt.httpClient = http.DefaultClient
-	}
-	return qrcodeDownloadToWriter(ticket, file, clt.httpClient)
+	return clt.QRCodeDownloadToWriterContext(ctx, ticket, file)
 }
 
 // 将一条长链接转成短链接.
@@ -217,6 +257,10 @@ func (clt *Client) QRCodeDownload(ticket, filepath string) (err error) {
 //  开发者用于生成二维码的原链接（商品、支付二维码等）太长导致扫码速度和成功率下降，
 //  将原长链接通过此接口转成短链接再生成二维码将大大提升扫码速度和成功率。
 func (clt *Client) ShortURL(LongURL string) (ShortURL string, err error) {
+	return clt.ShortURLContext(context.Background(), LongURL)
+}
+
+func (clt *Client) ShortURLContext(ctx context.Context, LongURL string) (ShortURL string, err error) {
 	var request = struct {
 		Action  string `json:"action"`
 		LongURL string `json:"long_url"`
@@ -230,8 +274,8 @@ func (clt *Client) ShortURL(LongURL string) (ShortURL string, err error) {
 		ShortURL string `json:"short_url"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/shorturl?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/shorturl?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
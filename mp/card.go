@@ -6,6 +6,7 @@
 package mp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -215,14 +216,18 @@ type BoardingPassCheckinParameters struct {
 // 在线值机接口.
 //  领取电影票后通过调用“更新电影票”接口update 电影信息及用户选座信息
 func (clt *Client) BoardingPassCheckin(para *BoardingPassCheckinParameters) (err error) {
+	return clt.BoardingPassCheckinContext(context.Background(), para)
+}
+
+func (clt *Client) BoardingPassCheckinContext(ctx context.Context, para *BoardingPassCheckinParameters) (err error) {
 	if para == nil {
 		return errors.New("nil BoardingPassCheckinParameters")
 	}
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/card/boardingpass/checkin?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/boardingpass/checkin?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -236,6 +241,10 @@ func (clt *Client) BoardingPassCheckin(para *BoardingPassCheckinParameters) (err
 // 创建卡券接口.
 //  Card 需要设置哪些字段请参考微信官方文档.
 func (clt *Client) CardCreate(card *Card) (cardId string, err error) {
+	return clt.CardCreateContext(context.Background(), card)
+}
+
+func (clt *Client) CardCreateContext(ctx context.Context, card *Card) (cardId string, err error) {
 	if card == nil {
 		err = errors.New("nil card")
 		return
@@ -252,8 +261,8 @@ func (clt *Client) CardCreate(card *Card) (cardId string, err error) {
 		CardId string `json:"card_id"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/card/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -268,6 +277,10 @@ func (clt *Client) CardCreate(card *Card) (cardId string, err error) {
 // 查询卡券详情.
 //  返回的 Card 有哪些字段请参考微信官方文档.
 func (clt *Client) CardGet(cardId string) (card *Card, err error) {
+	return clt.CardGetContext(context.Background(), cardId)
+}
+
+func (clt *Client) CardGetContext(ctx context.Context, cardId string) (card *Card, err error) {
 	var request = struct {
 		CardId string `json:"card_id"`
 	}{
@@ -279,8 +292,8 @@ func (clt *Client) CardGet(cardId string) (card *Card, err error) {
 		Card `json:"card"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/card/get?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/get?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -296,6 +309,10 @@ func (clt *Client) CardGet(cardId string) (card *Card, err error) {
 //  支持更新部分通用字段及特殊卡券（会员卡、飞机票、电影票、红包）中特定字段的信息，请参考微信官方文档.。
 //  注：更改卡券的部分字段后会重新提交审核，详情见字段说明。
 func (clt *Client) CardUpdate(cardId string, card *Card) (err error) {
+	return clt.CardUpdateContext(context.Background(), cardId, card)
+}
+
+func (clt *Client) CardUpdateContext(ctx context.Context, cardId string, card *Card) (err error) {
 	if card == nil {
 		return errors.New("nil Card")
 	}
@@ -311,8 +328,8 @@ func (clt *Client) CardUpdate(cardId string, card *Card) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/card/update?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 	if result.ErrCode != ErrCodeOK {
@@ -326,6 +343,10 @@ func (clt *Client) CardUpdate(cardId string, card *Card) (err error) {
 //  删除卡券接口允许商户删除任意一类卡券。删除卡券后，该卡券对应已生成的领取用二维码、添加到卡包JS API 均会失效。
 //  注意：如用户在商家删除卡券前已领取一张或多张该卡券依旧有效。即删除卡券不能删除已被用户领取，保存在微信客户端中的卡券。
 func (clt *Client) CardDelete(cardId string) (err error) {
+	return clt.CardDeleteContext(context.Background(), cardId)
+}
+
+func (clt *Client) CardDeleteContext(ctx context.Context, cardId string) (err error) {
 	var request = struct {
 		CardId string `json:"card_id"`
 	}{
@@ -334,8 +355,8 @@ func (clt *Client) CardDelete(cardId string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/card/delete?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/delete?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -350,6 +371,10 @@ func (clt *Client) CardDelete(cardId string) (err error) {
 //  offset: 查询卡列表的起始偏移量，从0 开始，即offset: 5 是指从从列表里的第六个开始读取。
 //  count : 需要查询的卡片的数量（数量最大50）
 func (clt *Client) CardBatchGet(offset, count int) (cardIdList []string, totalNum int, err error) {
+	return clt.CardBatchGetContext(context.Background(), offset, count)
+}
+
+func (clt *Client) CardBatchGetContext(ctx context.Context, offset, count int) (cardIdList []string, totalNum int, err error) {
 	if offset < 0 {
 		err = fmt.Errorf("invalid offset: %d", offset)
 		return
@@ -373,8 +398,8 @@ func (clt *Client) CardBatchGet(offset, count int) (cardIdList []string, totalNu
 		TotalNum   int      `json:"total_num"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/card/batchget?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/batchget?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -391,6 +416,10 @@ func (clt *Client) CardBatchGet(offset, count int) (cardIdList []string, totalNu
 // cardId:      卡券ID
 // increaseNum: 增加库存数量, 可以为负数
 func (clt *Client) CardModifyStock(cardId string, increaseNum int) (err error) {
+	return clt.CardModifyStockContext(context.Background(), cardId, increaseNum)
+}
+
+func (clt *Client) CardModifyStockContext(ctx context.Context, cardId string, increaseNum int) (err error) {
 	var request struct {
 		CardId             string `json:"card_id"`
 		IncreaseStockValue int    `json:"increase_stock_value,omitempty"`
@@ -408,8 +437,8 @@ func (clt *Client) CardModifyStock(cardId string, increaseNum int) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/card/modifystock?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/modifystock?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -434,14 +463,18 @@ type MeetingTicketUpdateUserParameters struct {
 // 更新电影票.
 //  领取电影票后通过调用“更新电影票”接口update 电影信息及用户选座信息
 func (clt *Client) MeetingTicketUpdateUser(para *MeetingTicketUpdateUserParameters) (err error) {
+	return clt.MeetingTicketUpdateUserContext(context.Background(), para)
+}
+
+func (clt *Client) MeetingTicketUpdateUserContext(ctx context.Context, para *MeetingTicketUpdateUserParameters) (err error) {
 	if para == nil {
 		return errors.New("nil MeetingTicketUpdateUserParameters")
 	}
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/card/meetingticket/updateuser?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/meetingticket/updateuser?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -466,6 +499,10 @@ type CardQRCodeInfo struct {
 // 卡券投放, 创建二维码.
 //  创建卡券后，商户可通过接口生成一张卡券二维码供用户扫码后添加卡券到卡包。
 func (clt *Client) CardQRCodeCreate(qrcodeInfo *CardQRCodeInfo) (ticket string, err error) {
+	return clt.CardQRCodeCreateContext(context.Background(), qrcodeInfo)
+}
+
+func (clt *Client) CardQRCodeCreateContext(ctx context.Context, qrcodeInfo *CardQRCodeInfo) (ticket string, err error) {
 	if qrcodeInfo == nil {
 		err = errors.New("nil CardQRCodeInfo")
 		return
@@ -485,8 +522,8 @@ func (clt *Client) CardQRCodeCreate(qrcodeInfo *CardQRCodeInfo) (ticket string,
 		Ticket string `json:"ticket"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/card/qrcode/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/qrcode/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -507,14 +544,18 @@ type TestWhiteListSetParameters struct {
 //  由于卡券有审核要求，为方便公众号调试，可以设置一些测试帐号，这些帐号可领取未通过审核的卡券，体验整个流程。
 //  注：同时支持“openid”、“username”两种字段设置白名单，总数上限为10 个。
 func (clt *Client) SetCardTestWhiteList(para *TestWhiteListSetParameters) (err error) {
+	return clt.SetCardTestWhiteListContext(context.Background(), para)
+}
+
+func (clt *Client) SetCardTestWhiteListContext(ctx context.Context, para *TestWhiteListSetParameters) (err error) {
 	if para == nil {
 		return errors.New("nil TestWhiteListSetParameters")
 	}
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/card/testwhitelist/set?access_token="
-	if err = clt.PostJSON(incompleteURL, para, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/card/testwhitelist/set?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, para, &result); err != nil {
 		return
 	}
 
@@ -2,79 +2,274 @@ package mp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+const (
+	DefaultAPIBaseURL  = "https://api.weixin.qq.com"
+	DefaultFileBaseURL = "https://file.api.weixin.qq.com"
+	DefaultMPBaseURL   = "https://mp.weixin.qq.com" // 二维码图片, 一次性订阅消息授权页等网页
 )
 
 type Client struct {
-	appId      string
-	appSecret  string
-	httpClient *http.Client
-	tokenInfo  TokenInfo
-	ticketInfo TicketInfo
+	appId       string
+	appSecret   string
+	apiBaseURL  string
+	fileBaseURL string
+	mpBaseURL   string
+	httpClient  *http.Client
+	tokenStore  util.TokenStore
+
+	retryPolicy  *util.RetryPolicy
+	interceptors []util.Interceptor
+
+	refreshGroup util.SingleFlight // 合并并发的 access_token, jsapi_ticket 刷新
+	templates    templateCache     // GetAllPrivateTemplate 获取到的模板内容
 }
 
 func NewClient(appId string, appSecret string) *Client {
 	return &Client{
-		appId:      appId,
-		appSecret:  appSecret,
-		httpClient: http.DefaultClient,
+		appId:       appId,
+		appSecret:   appSecret,
+		apiBaseURL:  DefaultAPIBaseURL,
+		fileBaseURL: DefaultFileBaseURL,
+		mpBaseURL:   DefaultMPBaseURL,
+		httpClient:  http.DefaultClient,
+		tokenStore:  util.NewMemoryTokenStore(),
 	}
 }
 
+// 设置接口的域名, 比如指向本地的测试桩, 就近的接入点或者出口代理.
+//  apiBaseURL:  替换 DefaultAPIBaseURL, 如 "http://127.0.0.1:8080"
+//  fileBaseURL: 替换 DefaultFileBaseURL(多媒体文件上传/下载), 如果为 "" 则和 apiBaseURL 相同
+//  NOTE: 二维码图片等 mp.weixin.qq.com 上的网页用 SetMPBaseURL 设置.
+func (c *Client) SetBaseURL(apiBaseURL, fileBaseURL string) {
+	if fileBaseURL == "" {
+		fileBaseURL = apiBaseURL
+	}
+	c.apiBaseURL = strings.TrimSuffix(apiBaseURL, "/")
+	c.fileBaseURL = strings.TrimSuffix(fileBaseURL, "/")
+}
+
+// 设置 mp.weixin.qq.com 的域名(替换 DefaultMPBaseURL), 用于 QRCodeURL, QRCodeDownload, SubscribeMsgURL 等,
+// 比如指向 wechattest.Server: clt.SetMPBaseURL(srv.URL).
+func (c *Client) SetMPBaseURL(mpBaseURL string) {
+	c.mpBaseURL = strings.TrimSuffix(mpBaseURL, "/")
+}
+
+func (c *Client) SetHttpClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// 设置 access_token, jsapi_ticket 的存储, 默认存储在当前进程的内存中.
+//  多个进程使用同一个公众号时, 应该设置为共享的存储, 否则各个进程会互相刷新导致对方的 access_token 失效.
+func (c *Client) SetTokenStore(store util.TokenStore) {
+	c.tokenStore = store
+}
+
+// 设置重试策略, 默认不重试(只在 access_token 失效时刷新后重试一次).
+//  设置后网络错误, http 5xx, 临时性的错误码(比如 -1 系统繁忙)会按照策略重试, 如:
+//    clt.SetRetryPolicy(&util.DefaultRetryPolicy)
+//  NOTE: 重试可能导致非幂等的接口(比如发送消息)重复执行, 上传多媒体文件的接口不会重试.
+func (c *Client) SetRetryPolicy(policy *util.RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// 设置拦截器, 每一个发往微信服务器的请求都会依次经过这些拦截器, 用于记录日志, 统计指标, 链路追踪等, 如:
+//    metrics := util.NewMetrics("", nil)
+//    clt.SetInterceptors(util.NewLogInterceptor(slog.Default(), false), metrics.Interceptor())
+//  拦截器看到的 URL 中 access_token 已经替换为 "***".
+func (c *Client) SetInterceptors(interceptors ...util.Interceptor) {
+	c.interceptors = interceptors
+}
+
+// 发送请求, 经过 SetInterceptors 设置的拦截器.
+func (c *Client) do(httpReq *http.Request) (*http.Response, error) {
+	return util.DoHTTP(c.httpClient, c.interceptors, httpReq)
+}
+
+// 发送 GET 请求, ctx 用于控制请求的取消和超时.
+func (c *Client) httpGet(ctx context.Context, url string) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(httpReq)
+}
+
+// 发送 POST 请求, ctx 用于控制请求的取消和超时.
+func (c *Client) httpPost(ctx context.Context, url, bodyType string, body io.Reader) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", bodyType)
+	return c.do(httpReq)
+}
+
+const refreshTimeout = 30 * time.Second // 刷新 access_token, jsapi_ticket 的超时时间
+
 type TokenInfo struct {
 	Token     string `json:"access_token"`
 	ExpiresIn int64  `json:"expires_in"`
 }
 
+func (c *Client) tokenKey() string {
+	return "mp:access_token:" + c.appId
+}
+
+func (c *Client) ticketKey() string {
+	return "mp:jsapi_ticket:" + c.appId
+}
+
 func (c *Client) GetTokenInfo() TokenInfo {
-	return c.tokenInfo
+	cred, _ := c.tokenStore.Get(c.tokenKey())
+	return TokenInfo{Token: cred.Value, ExpiresIn: cred.ExpiresAt}
 }
 
 func (c *Client) GetTicketInfo() TicketInfo {
-	return c.ticketInfo
+	cred, _ := c.tokenStore.Get(c.ticketKey())
+	return TicketInfo{Ticket: cred.Value, ExpiresIn: cred.ExpiresAt}
 }
-func (c *Client) SetToken(token TokenInfo) {
-	c.tokenInfo = token
+
+// 把 access_token 写入 TokenStore, ExpiresIn 为过期时间(unixtime); 返回 TokenStore 的错误.
+func (c *Client) SetToken(token TokenInfo) error {
+	return c.tokenStore.Set(c.tokenKey(), util.Credential{Value: token.Token, ExpiresAt: token.ExpiresIn})
 }
-func (c *Client) SetTicket(ticket TicketInfo) {
-	c.ticketInfo = ticket
+
+// 把 jsapi_ticket 写入 TokenStore, ExpiresIn 为过期时间(unixtime); 返回 TokenStore 的错误.
+func (c *Client) SetTicket(ticket TicketInfo) error {
+	return c.tokenStore.Set(c.ticketKey(), util.Credential{Value: ticket.Ticket, ExpiresAt: ticket.ExpiresIn})
 }
+
 func (c *Client) Token() (token string, err error) {
-	if c.isValidToken() {
-		token = c.tokenInfo.Token
+	return c.TokenContext(context.Background())
+}
+
+func (c *Client) TokenContext(ctx context.Context) (token string, err error) {
+	cred, err := c.token(ctx)
+	if err != nil {
 		return
 	}
-	return c.RefreshToken()
+	token = cred.Value
+	return
 }
 func (c *Client) RefreshToken() (token string, err error) {
-	tokenInfo, err := c.getToken()
+	return c.RefreshTokenContext(context.Background())
+}
+
+func (c *Client) RefreshTokenContext(ctx context.Context) (token string, err error) {
+	cred, err := c.tokenStore.Get(c.tokenKey())
 	if err != nil {
 		return
 	}
-	c.tokenInfo = tokenInfo
-	token = c.tokenInfo.Token
+	if cred, err = c.refreshToken(ctx, cred); err != nil {
+		return
+	}
+	token = cred.Value
 	return
 }
 
-func (c *Client) isValidToken() bool {
+func (c *Client) token(ctx context.Context) (cred util.Credential, err error) {
+	if cred, err = c.tokenStore.Get(c.tokenKey()); err != nil {
+		return
+	}
+	if isValidCredential(cred) {
+		return
+	}
+	return c.refreshToken(ctx, cred)
+}
+
+// 从微信服务器获取新的 access_token 替换 stale.
+//  如果存储中的 access_token 已经不是 stale(被其他进程刷新过)并且有效, 则直接使用存储中的.
+//  同一个 Client 同一时刻只有一个刷新请求, 并发的调用者等待并共享它的结果.
+func (c *Client) refreshToken(ctx context.Context, stale util.Credential) (cred util.Credential, err error) {
+	key := c.tokenKey()
+	ch := c.refreshGroup.DoChan(key, func() (interface{}, error) {
+		refreshCtx, cancel := refreshContext(ctx)
+		defer cancel()
+		return c.doRefreshToken(refreshCtx, key, stale)
+	})
+	select {
+	case r := <-ch:
+		cred, _ = r.Val.(util.Credential)
+		err = r.Err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return
+}
+
+func (c *Client) doRefreshToken(ctx context.Context, key string, stale util.Credential) (cred util.Credential, err error) {
+	if cred, err = c.tokenStore.Get(key); err != nil {
+		return
+	}
+	if cred != stale && isValidCredential(cred) {
+		return
+	}
+
+	var tokenInfo TokenInfo
+	err = c.retryPolicy.Do(ctx, func() (err error) {
+		tokenInfo, err = c.getToken(ctx)
+		return
+	})
+	if err != nil {
+		return
+	}
+	return c.swapCredential(key, cred, util.Credential{Value: tokenInfo.Token, ExpiresAt: tokenInfo.ExpiresIn})
+}
+
+// 用 new 替换存储中的 old, 如果其他进程抢先写入了有效的凭证, 则以存储中的为准.
+func (c *Client) swapCredential(key string, old, new util.Credential) (cred util.Credential, err error) {
+	swapped, err := c.tokenStore.CompareAndSwap(key, old, new)
+	if err != nil {
+		return
+	}
+	if !swapped {
+		if cred, err = c.tokenStore.Get(key); err != nil {
+			return
+		}
+		if isValidCredential(cred) {
+			return
+		}
+		if err = c.tokenStore.Set(key, new); err != nil {
+			return
+		}
+	}
+	cred = new
+	return
+}
+
+// 刷新的结果由所有等待者共享, 所以不能因为发起刷新的调用者取消而失败,
+// 但是保留 ctx 里的 value, 并限制最长的刷新时间.
+func refreshContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+}
+
+func isValidCredential(cred util.Credential) bool {
 	timeNowUnix := time.Now().Unix()
 
-	if timeNowUnix+2 >= c.tokenInfo.ExpiresIn || c.tokenInfo.Token == "" {
+	if timeNowUnix+2 >= cred.ExpiresAt || cred.Value == "" {
 		return false
 	}
 
 	return true
 }
-func (c *Client) getToken() (token TokenInfo, err error) {
+func (c *Client) getToken(ctx context.Context) (token TokenInfo, err error) {
 
-	_url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s",
+	_url := c.apiBaseURL + fmt.Sprintf("/cgi-bin/token?grant_type=client_credential&appid=%s&secret=%s",
 		url.QueryEscape(c.appId), url.QueryEscape(c.appSecret))
 	// fmt.Println("get token:", _url)
 	if _url == "" {
@@ -82,14 +277,14 @@ func (c *Client) getToken() (token TokenInfo, err error) {
 		return
 	}
 
-	httpResp, err := c.httpClient.Get(_url)
+	httpResp, err := c.httpGet(ctx, _url)
 	if err != nil {
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("http.Status: %s", httpResp.Status)
+		err = &util.HTTPError{StatusCode: httpResp.StatusCode, Status: httpResp.Status}
 		return
 	}
 
@@ -137,38 +332,82 @@ type TicketInfo struct {
 	ExpiresIn int64  `json:"expires_in"` // 有效时间, seconds
 }
 
-func (c *Client) isValidTicket() bool {
-	timeNowUnix := time.Now().Unix()
-	if timeNowUnix+2 >= c.ticketInfo.ExpiresIn || c.ticketInfo.Ticket == "" {
-		return false
-	}
-	return true
-}
 func (c *Client) Ticket() (ticket string, err error) {
-	if c.isValidTicket() {
-		ticket = c.ticketInfo.Ticket
+	return c.TicketContext(context.Background())
+}
+
+func (c *Client) TicketContext(ctx context.Context) (ticket string, err error) {
+	cred, err := c.tokenStore.Get(c.ticketKey())
+	if err != nil {
 		return
 	}
-	return c.RefreshTicket()
+	if isValidCredential(cred) {
+		ticket = cred.Value
+		return
+	}
+	if cred, err = c.refreshTicket(ctx, cred); err != nil {
+		return
+	}
+	ticket = cred.Value
+	return
 }
 func (c *Client) RefreshTicket() (ticket string, err error) {
-	ticketInfo, err := c.getTicket()
+	return c.RefreshTicketContext(context.Background())
+}
+
+func (c *Client) RefreshTicketContext(ctx context.Context) (ticket string, err error) {
+	cred, err := c.tokenStore.Get(c.ticketKey())
 	if err != nil {
 		return
 	}
-	c.ticketInfo = ticketInfo
-	ticket = c.ticketInfo.Ticket
+	if cred, err = c.refreshTicket(ctx, cred); err != nil {
+		return
+	}
+	ticket = cred.Value
+	return
+}
+
+// 从微信服务器获取新的 jsapi_ticket 替换 stale, 逻辑同 refreshToken.
+func (c *Client) refreshTicket(ctx context.Context, stale util.Credential) (cred util.Credential, err error) {
+	key := c.ticketKey()
+	ch := c.refreshGroup.DoChan(key, func() (interface{}, error) {
+		refreshCtx, cancel := refreshContext(ctx)
+		defer cancel()
+		return c.doRefreshTicket(refreshCtx, key, stale)
+	})
+	select {
+	case r := <-ch:
+		cred, _ = r.Val.(util.Credential)
+		err = r.Err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
 	return
 }
 
+func (c *Client) doRefreshTicket(ctx context.Context, key string, stale util.Credential) (cred util.Credential, err error) {
+	if cred, err = c.tokenStore.Get(key); err != nil {
+		return
+	}
+	if cred != stale && isValidCredential(cred) {
+		return
+	}
+
+	ticketInfo, err := c.getTicket(ctx)
+	if err != nil {
+		return
+	}
+	return c.swapCredential(key, cred, util.Credential{Value: ticketInfo.Ticket, ExpiresAt: ticketInfo.ExpiresIn})
+}
+
 // 从微信服务器获取 jsapi_ticket.
-func (c *Client) getTicket() (ticket TicketInfo, err error) {
+func (c *Client) getTicket(ctx context.Context) (ticket TicketInfo, err error) {
 	var result struct {
 		Error
 		TicketInfo
 	}
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/ticket/getticket?type=jsapi&access_token="
-	if err = c.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := c.apiBaseURL + "/cgi-bin/ticket/getticket?type=jsapi&access_token="
+	if err = c.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -199,7 +438,7 @@ func (c *Client) getTicket() (ticket TicketInfo, err error) {
 	return
 }
 
-// 用 encoding/json 把 request marshal 为 JSON, 放入 http 请求的 body 中,
+// 用 util.EncodeJSON 把 request 编码为 JSON(不转义 HTML 字符), 放入 http 请求的 body 中,
 // POST 到微信服务器, 然后将微信服务器返回的 JSON 用 encoding/json 解析到 response.
 //
 //  NOTE:
@@ -208,51 +447,58 @@ func (c *Client) getTicket() (ticket TicketInfo, err error) {
 //  3. response 要求是 struct 的指针, 并且该 struct 拥有属性:
 //     ErrCode int `json:"errcode"` (可以是直接属性, 也可以是匿名属性里的属性)
 func (c *Client) PostJSON(incompleteURL string, request interface{}, response interface{}) (err error) {
-	b, err := json.Marshal(request)
-	if err != nil {
+	return c.PostJSONContext(context.Background(), incompleteURL, request, response)
+}
+
+// 同 PostJSON, ctx 用于控制请求的取消和超时.
+//  NOTE: 所有高层次的封装方法都有对应的 XxxContext 版本, 原来的方法等价于传入 context.Background().
+func (c *Client) PostJSONContext(ctx context.Context, incompleteURL string, request interface{}, response interface{}) (err error) {
+	buf := textBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer textBufferPool.Put(buf)
+
+	if err = util.EncodeJSON(buf, request); err != nil {
 		return
 	}
 
-	b = bytes.Replace(b, []byte("\\u003c"), []byte("<"), -1)
-	b = bytes.Replace(b, []byte("\\u003e"), []byte(">"), -1)
-	b = bytes.Replace(b, []byte("\\u0026"), []byte("&"), -1)
+	return c.withRetry(ctx, response, func() error {
+		return c.postJSON(ctx, incompleteURL, buf.Bytes(), response)
+	})
+}
 
-	token, err := c.Token()
+func (c *Client) postJSON(ctx context.Context, incompleteURL string, body []byte, response interface{}) (err error) {
+	token, err := c.token(ctx)
 	if err != nil {
 		return
 	}
 
-	//fmt.Println("post weixin json:", string(b))
-
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 	//fmt.Println("wechat call url:", finalURL)
 
-	httpResp, err := c.httpClient.Post(finalURL, "application/json; charset=utf-8", bytes.NewReader(b))
+	httpResp, err := c.httpPost(ctx, finalURL, "application/json; charset=utf-8", bytes.NewReader(body))
 	if err != nil {
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http.Status: %s", httpResp.Status)
+		return &util.HTTPError{StatusCode: httpResp.StatusCode, Status: httpResp.Status}
 	}
 
 	if err = json.NewDecoder(httpResp.Body).Decode(response); err != nil {
 		return
 	}
 
-	ErrCode := reflect.ValueOf(response).Elem().FieldByName("ErrCode").Int()
-
-	switch ErrCode {
+	switch responseErrCode(response) {
 	case ErrCodeOK:
 		return
 	case ErrCodeTimeout, ErrCodeInvalidCredential:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = c.RefreshToken(); err != nil {
+			if token, err = c.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -271,39 +517,48 @@ RETRY:
 //  3. response 要求是 struct 的指针, 并且该 struct 拥有属性:
 //     ErrCode int `json:"errcode"` (可以是直接属性, 也可以是匿名属性里的属性)
 func (c *Client) GetJSON(incompleteURL string, response interface{}) (err error) {
-	token, err := c.Token()
+	return c.GetJSONContext(context.Background(), incompleteURL, response)
+}
+
+// 同 GetJSON, ctx 用于控制请求的取消和超时.
+func (c *Client) GetJSONContext(ctx context.Context, incompleteURL string, response interface{}) (err error) {
+	return c.withRetry(ctx, response, func() error {
+		return c.getJSON(ctx, incompleteURL, response)
+	})
+}
+
+func (c *Client) getJSON(ctx context.Context, incompleteURL string, response interface{}) (err error) {
+	token, err := c.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 
-	httpResp, err := c.httpClient.Get(finalURL)
+	httpResp, err := c.httpGet(ctx, finalURL)
 	if err != nil {
 		return
 	}
 	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http.Status: %s", httpResp.Status)
+		return &util.HTTPError{StatusCode: httpResp.StatusCode, Status: httpResp.Status}
 	}
 
 	if err = json.NewDecoder(httpResp.Body).Decode(response); err != nil {
 		return
 	}
 
-	ErrCode := reflect.ValueOf(response).Elem().FieldByName("ErrCode").Int()
-
-	switch ErrCode {
+	switch responseErrCode(response) {
 	case ErrCodeOK:
 		return
 	case ErrCodeTimeout, ErrCodeInvalidCredential:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = c.RefreshToken(); err != nil {
+			if token, err = c.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -313,3 +568,38 @@ RETRY:
 		return
 	}
 }
+
+// 按照重试策略调用 fn, 微信服务器返回临时性的错误码(比如 -1 系统繁忙)时也会重试;
+// 最终仍然返回错误码时和原来一样返回 nil, 由调用者根据 response 的 ErrCode 判断.
+func (c *Client) withRetry(ctx context.Context, response interface{}, fn func() error) (err error) {
+	if c.retryPolicy == nil {
+		return fn()
+	}
+
+	// 重试前把 response 恢复为调用前的值, 避免上一次返回的字段(比如 errcode)残留
+	v := reflect.ValueOf(response).Elem()
+	orig := reflect.New(v.Type()).Elem()
+	orig.Set(v)
+
+	attempt := 0
+	err = c.retryPolicy.Do(ctx, func() (err error) {
+		if attempt++; attempt > 1 {
+			v.Set(orig)
+		}
+		if err = fn(); err != nil {
+			return
+		}
+		if errCode := responseErrCode(response); errCode != ErrCodeOK {
+			return &Error{ErrCode: errCode}
+		}
+		return
+	})
+	if _, ok := err.(*Error); ok {
+		err = nil
+	}
+	return
+}
+
+func responseErrCode(response interface{}) int {
+	return int(reflect.ValueOf(response).Elem().FieldByName("ErrCode").Int())
+}
@@ -0,0 +1,299 @@
+package mp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/util"
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestTokenConcurrentRefresh(t *testing.T) {
+	var tokenCalls, ticketCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cgi-bin/token":
+			n := atomic.AddInt32(&tokenCalls, 1)
+			time.Sleep(50 * time.Millisecond) // 让并发的调用者都赶上这次刷新
+			fmt.Fprintf(w, `{"access_token":"TOKEN%d","expires_in":7200}`, n)
+		case "/cgi-bin/ticket/getticket":
+			n := atomic.AddInt32(&ticketCalls, 1)
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintf(w, `{"errcode":0,"errmsg":"ok","ticket":"TICKET%d","expires_in":7200}`, n)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	clt := NewClient("appid", "secret")
+	clt.SetBaseURL(server.URL, "")
+
+	const n = 64
+	var wg sync.WaitGroup
+	tokens := make([]string, n)
+	tickets := make([]string, n)
+	errs := make([]error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = clt.Token()
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			tickets[i], errs[n+i] = clt.Ticket()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tokenCalls != 1 {
+		t.Errorf("access_token 应该只刷新 1 次, 实际刷新了 %d 次", tokenCalls)
+	}
+	if ticketCalls != 1 {
+		t.Errorf("jsapi_ticket 应该只刷新 1 次, 实际刷新了 %d 次", ticketCalls)
+	}
+	for i := 0; i < n; i++ {
+		if tokens[i] != "TOKEN1" {
+			t.Errorf("获取了错误的 access_token: %s", tokens[i])
+		}
+		if tickets[i] != "TICKET1" {
+			t.Errorf("获取了错误的 jsapi_ticket: %s", tickets[i])
+		}
+	}
+}
+
+func TestRefreshTokenAfterExpired(t *testing.T) {
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cgi-bin/token":
+			n := atomic.AddInt32(&tokenCalls, 1)
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprintf(w, `{"access_token":"TOKEN%d","expires_in":7200}`, n)
+		case "/cgi-bin/menu/get":
+			if r.URL.Query().Get("access_token") == "EXPIRED" {
+				fmt.Fprint(w, `{"errcode":42001,"errmsg":"access_token expired"}`)
+				return
+			}
+			fmt.Fprint(w, `{"errcode":0,"errmsg":"ok"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	clt := NewClient("appid", "secret")
+	clt.SetBaseURL(server.URL, "")
+	clt.SetToken(TokenInfo{Token: "EXPIRED", ExpiresIn: time.Now().Unix() + 3600})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result Error
+			if err := clt.GetJSON(clt.apiBaseURL+"/cgi-bin/menu/get?access_token=", &result); err != nil {
+				t.Error(err)
+				return
+			}
+			if result.ErrCode != ErrCodeOK {
+				t.Error(&result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if tokenCalls != 1 {
+		t.Errorf("access_token 应该只刷新 1 次, 实际刷新了 %d 次", tokenCalls)
+	}
+}
+
+// 多个进程通过 FileTokenStore 共享 access_token: 过期后只刷新一次, 其他进程直接使用刷新后的.
+func TestFileTokenStoreExpired(t *testing.T) {
+	api := wechattest.NewServer()
+	defer api.Close()
+
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	clt1 := NewClient(api.AppId, api.AppSecret)
+	clt1.SetBaseURL(api.URL, "")
+	clt1.SetTokenStore(util.NewFileTokenStore(path))
+	if err := clt1.SetToken(TokenInfo{Token: "EXPIRED", ExpiresIn: time.Now().Unix() - 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := clt1.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "EXPIRED" {
+		t.Fatal("过期的 access_token 应该刷新")
+	}
+
+	clt2 := NewClient(api.AppId, api.AppSecret)
+	clt2.SetBaseURL(api.URL, "")
+	clt2.SetTokenStore(util.NewFileTokenStore(path))
+	if token2, err := clt2.Token(); err != nil || token2 != token {
+		t.Errorf("token = %q, %v, want %q", token2, err, token)
+	}
+	if tokenCalls, _ := api.TokenCalls(); tokenCalls != 1 {
+		t.Errorf("tokenCalls = %d, want 1", tokenCalls)
+	}
+}
+
+func TestGetJSONContextCanceled(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+	defer close(block)
+
+	clt := NewClient("appid", "secret")
+	clt.SetBaseURL(server.URL, "")
+	clt.SetToken(TokenInfo{Token: "TOKEN", ExpiresIn: time.Now().Unix() + 3600})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var result Error
+	err := clt.GetJSONContext(ctx, clt.apiBaseURL+"/cgi-bin/menu/get?access_token=", &result)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("应该返回 context.DeadlineExceeded, 实际返回: %v", err)
+	}
+}
+
+func TestGetJSONRetrySystemBusy(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cgi-bin/token":
+			fmt.Fprint(w, `{"access_token":"TOKEN","expires_in":7200}`)
+		case "/cgi-bin/tags/get":
+			switch atomic.AddInt32(&calls, 1) {
+			case 1:
+				fmt.Fprint(w, `{"errcode":-1,"errmsg":"system error"}`)
+			case 2:
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				fmt.Fprint(w, `{"tags":[{"id":2,"name":"星标组","count":0}]}`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	clt := NewClient("appid", "secret")
+	clt.SetBaseURL(server.URL, "")
+	clt.SetRetryPolicy(&util.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	tags, err := clt.ListTag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("应该请求 3 次, 实际请求了 %d 次", calls)
+	}
+	if len(tags) != 1 || tags[0].Name != "星标组" {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+
+	// 不设置重试策略时和原来一样, 返回错误码
+	atomic.StoreInt32(&calls, 0)
+	clt.SetRetryPolicy(nil)
+	_, err = clt.ListTag()
+	if !errors.Is(err, ErrSystemBusy) {
+		t.Errorf("want ErrSystemBusy, have %v", err)
+	}
+}
+
+func TestPostJSONNoHTMLEscape(t *testing.T) {
+	srv := wechattest.NewServer()
+	defer srv.Close()
+	srv.SetUser("openid", nil)
+
+	clt := NewClient(srv.AppId, srv.AppSecret)
+	clt.SetBaseURL(srv.URL, "")
+
+	menuURL := "http://example.com/?a=1&b=2"
+	if err := clt.CreateMenu(Menu{Buttons: []Button{{Type: ButtonTypeView, Name: "<官网>", URL: menuURL}}}); err != nil {
+		t.Fatal(err)
+	}
+	content := `<p>Tom & Jerry</p>`
+	if _, err := clt.CreateNews([]Article{{Title: "news", Content: content}}); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"first":{"value":"<订单> & 发货"}}`
+	if _, err := clt.SendTemplateMessage(&TemplateMessage{ToUser: "openid", TemplateId: "TEMPLATE_1", RawJSONData: []byte(data)}); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"/cgi-bin/menu/create":           `"url":"` + menuURL + `"`,
+		"/cgi-bin/media/uploadnews":      `"content":"` + content + `"`,
+		"/cgi-bin/message/template/send": `"data":{"first":{"value":"<订单> & 发货"}}`,
+	} {
+		calls := srv.CallsTo(path)
+		if len(calls) != 1 {
+			t.Fatalf("%s: calls = %+v", path, calls)
+		}
+		if !bytes.Contains(calls[0].Body, []byte(want)) {
+			t.Errorf("%s: body = %s, want contains %s", path, calls[0].Body, want)
+		}
+	}
+}
+
+func TestQRCodeDownloadContext(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cgi-bin/showqrcode" || r.URL.Query().Get("ticket") != "TICKET" {
+			http.NotFound(w, r)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("QRCODE"))
+	}))
+	defer server.Close()
+
+	clt := NewClient("appid", "secret")
+	clt.SetMPBaseURL(server.URL + "/")
+	clt.SetRetryPolicy(&util.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	var paths []string
+	clt.SetInterceptors(func(ctx context.Context, call *util.Call, next func(ctx context.Context) error) error {
+		paths = append(paths, call.Path)
+		return next(ctx)
+	})
+
+	var buf bytes.Buffer
+	if err := clt.QRCodeDownloadToWriterContext(context.Background(), "TICKET", &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "QRCODE" || calls != 2 || len(paths) != 2 || paths[0] != "/cgi-bin/showqrcode" {
+		t.Errorf("body = %q, calls = %d, paths = %v", buf.String(), calls, paths)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := clt.QRCodeDownloadToWriterContext(ctx, "TICKET", &buf); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
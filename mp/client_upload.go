@@ -9,10 +9,10 @@ package mp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -56,6 +56,14 @@ func (clt *Client) UploadFromReader(incompleteURL,
 	part1FieldName, part1FileName string, part1ValueReader io.Reader,
 	part2FieldName string, part2Value []byte,
 	response interface{}) (err error) {
+	return clt.UploadFromReaderContext(context.Background(), incompleteURL, part1FieldName, part1FileName, part1ValueReader, part2FieldName, part2Value, response)
+}
+
+// 同 UploadFromReader, ctx 用于控制请求的取消和超时.
+func (clt *Client) UploadFromReaderContext(ctx context.Context, incompleteURL,
+	part1FieldName, part1FileName string, part1ValueReader io.Reader,
+	part2FieldName string, part2Value []byte,
+	response interface{}) (err error) {
 
 	// 构造 multipart/form-data, 存入一个字节数组里
 
@@ -89,16 +97,16 @@ func (clt *Client) UploadFromReader(incompleteURL,
 
 	bodyBytes := bodyBuf.Bytes()
 
-	token, err := clt.Token()
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := incompleteURL + url.QueryEscape(token)
+	finalURL := incompleteURL + url.QueryEscape(token.Value)
 
-	httpResp, err := clt.httpClient.Post(finalURL, multipartWriter.FormDataContentType(), bytes.NewReader(bodyBytes))
+	httpResp, err := clt.httpPost(ctx, finalURL, multipartWriter.FormDataContentType(), bytes.NewReader(bodyBytes))
 	if err != nil {
 		return
 	}
@@ -131,22 +139,15 @@ RETRY:
 	case ErrCodeOK:
 		return
 	case ErrCodeInvalidCredential, ErrCodeTimeout:
-		ErrMsg := responseStructValue.FieldByName("ErrMsg").String()
-		log.Println("RETRY, err_code:", ErrCode, ", err_msg:", ErrMsg)
-		log.Println("RETRY, current token:", token)
-
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
-			log.Println("RETRY, new token:", token)
-
 			responseStructValue.Set(reflect.New(responseStructValue.Type()).Elem())
 			goto RETRY
 		}
-		log.Println("RETRY fallthrough, current token:", token)
 		fallthrough
 	default:
 		return
@@ -6,6 +6,7 @@
 package mp
 
 import (
+	"context"
 	"errors"
 )
 
@@ -46,6 +47,10 @@ type ArticleSummaryData struct {
 
 // 获取图文群发每日数据.
 func (clt *Client) GetArticleSummary(param *DataCubeParam) (list []ArticleSummaryData, err error) {
+	return clt.GetArticleSummaryContext(context.Background(), param)
+}
+
+func (clt *Client) GetArticleSummaryContext(ctx context.Context, param *DataCubeParam) (list []ArticleSummaryData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -56,8 +61,8 @@ func (clt *Client) GetArticleSummary(param *DataCubeParam) (list []ArticleSummar
 		List []ArticleSummaryData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getarticlesummary?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getarticlesummary?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -83,6 +88,10 @@ type ArticleTotalData struct {
 
 // 获取图文群发总数据.
 func (clt *Client) GetArticleTotal(param *DataCubeParam) (list []ArticleTotalData, err error) {
+	return clt.GetArticleTotalContext(context.Background(), param)
+}
+
+func (clt *Client) GetArticleTotalContext(ctx context.Context, param *DataCubeParam) (list []ArticleTotalData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -93,8 +102,8 @@ func (clt *Client) GetArticleTotal(param *DataCubeParam) (list []ArticleTotalDat
 		List []ArticleTotalData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getarticletotal?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getarticletotal?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -115,6 +124,10 @@ type UserReadData struct {
 
 // 获取图文统计数据.
 func (clt *Client) GetUserRead(param *DataCubeParam) (list []UserReadData, err error) {
+	return clt.GetUserReadContext(context.Background(), param)
+}
+
+func (clt *Client) GetUserReadContext(ctx context.Context, param *DataCubeParam) (list []UserReadData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -125,8 +138,8 @@ func (clt *Client) GetUserRead(param *DataCubeParam) (list []UserReadData, err e
 		List []UserReadData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getuserread?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getuserread?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -146,6 +159,10 @@ type UserReadHourData struct {
 
 // 获取图文统计分时数据.
 func (clt *Client) GetUserReadHour(param *DataCubeParam) (list []UserReadHourData, err error) {
+	return clt.GetUserReadHourContext(context.Background(), param)
+}
+
+func (clt *Client) GetUserReadHourContext(ctx context.Context, param *DataCubeParam) (list []UserReadHourData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -156,8 +173,8 @@ func (clt *Client) GetUserReadHour(param *DataCubeParam) (list []UserReadHourDat
 		List []UserReadHourData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getuserreadhour?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getuserreadhour?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -179,6 +196,10 @@ type UserShareData struct {
 
 // 获取图文分享转发数据.
 func (clt *Client) GetUserShare(param *DataCubeParam) (list []UserShareData, err error) {
+	return clt.GetUserShareContext(context.Background(), param)
+}
+
+func (clt *Client) GetUserShareContext(ctx context.Context, param *DataCubeParam) (list []UserShareData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -189,8 +210,8 @@ func (clt *Client) GetUserShare(param *DataCubeParam) (list []UserShareData, err
 		List []UserShareData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getusershare?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getusershare?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -210,6 +231,10 @@ type UserShareHourData struct {
 
 // 获取图文分享转发分时数据.
 func (clt *Client) GetUserShareHour(param *DataCubeParam) (list []UserShareHourData, err error) {
+	return clt.GetUserShareHourContext(context.Background(), param)
+}
+
+func (clt *Client) GetUserShareHourContext(ctx context.Context, param *DataCubeParam) (list []UserShareHourData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -220,8 +245,8 @@ func (clt *Client) GetUserShareHour(param *DataCubeParam) (list []UserShareHourD
 		List []UserShareHourData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getusersharehour?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getusersharehour?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -244,6 +269,10 @@ type InterfaceSummaryData struct {
 
 // 获取接口分析数据.
 func (clt *Client) GetInterfaceSummary(param *DataCubeParam) (list []InterfaceSummaryData, err error) {
+	return clt.GetInterfaceSummaryContext(context.Background(), param)
+}
+
+func (clt *Client) GetInterfaceSummaryContext(ctx context.Context, param *DataCubeParam) (list []InterfaceSummaryData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -254,8 +283,8 @@ func (clt *Client) GetInterfaceSummary(param *DataCubeParam) (list []InterfaceSu
 		List []InterfaceSummaryData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getinterfacesummary?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getinterfacesummary?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -274,6 +303,10 @@ type InterfaceSummaryHourData struct {
 
 // 获取接口分析分时数据.
 func (clt *Client) GetInterfaceSummaryHour(param *DataCubeParam) (list []InterfaceSummaryHourData, err error) {
+	return clt.GetInterfaceSummaryHourContext(context.Background(), param)
+}
+
+func (clt *Client) GetInterfaceSummaryHourContext(ctx context.Context, param *DataCubeParam) (list []InterfaceSummaryHourData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -284,8 +317,8 @@ func (clt *Client) GetInterfaceSummaryHour(param *DataCubeParam) (list []Interfa
 		List []InterfaceSummaryHourData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getinterfacesummaryhour?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getinterfacesummaryhour?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -314,6 +347,10 @@ type UpstreamMsgData struct {
 
 // 获取消息发送概况数据.
 func (clt *Client) GetUpstreamMsg(param *DataCubeParam) (list []UpstreamMsgData, err error) {
+	return clt.GetUpstreamMsgContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -324,8 +361,8 @@ func (clt *Client) GetUpstreamMsg(param *DataCubeParam) (list []UpstreamMsgData,
 		List []UpstreamMsgData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsg?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsg?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -345,6 +382,10 @@ type UpstreamMsgHourData struct {
 
 // 获取消息分送分时数据.
 func (clt *Client) GetUpstreamMsgHour(param *DataCubeParam) (list []UpstreamMsgHourData, err error) {
+	return clt.GetUpstreamMsgHourContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgHourContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgHourData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -355,8 +396,8 @@ func (clt *Client) GetUpstreamMsgHour(param *DataCubeParam) (list []UpstreamMsgH
 		List []UpstreamMsgHourData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsghour?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsghour?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -375,6 +416,10 @@ type UpstreamMsgWeekData struct {
 
 // 获取消息发送周数据.
 func (clt *Client) GetUpstreamMsgWeek(param *DataCubeParam) (list []UpstreamMsgWeekData, err error) {
+	return clt.GetUpstreamMsgWeekContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgWeekContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgWeekData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -385,8 +430,8 @@ func (clt *Client) GetUpstreamMsgWeek(param *DataCubeParam) (list []UpstreamMsgW
 		List []UpstreamMsgWeekData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsgweek?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsgweek?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -405,6 +450,10 @@ type UpstreamMsgMonthData struct {
 
 // 获取消息发送月数据.
 func (clt *Client) GetUpstreamMsgMonth(param *DataCubeParam) (list []UpstreamMsgMonthData, err error) {
+	return clt.GetUpstreamMsgMonthContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgMonthContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgMonthData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -415,8 +464,8 @@ func (clt *Client) GetUpstreamMsgMonth(param *DataCubeParam) (list []UpstreamMsg
 		List []UpstreamMsgMonthData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsgmonth?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsgmonth?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -437,6 +486,10 @@ type UpstreamMsgDistData struct {
 
 // 获取消息发送分布数据.
 func (clt *Client) GetUpstreamMsgDist(param *DataCubeParam) (list []UpstreamMsgDistData, err error) {
+	return clt.GetUpstreamMsgDistContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgDistContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgDistData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -447,8 +500,8 @@ func (clt *Client) GetUpstreamMsgDist(param *DataCubeParam) (list []UpstreamMsgD
 		List []UpstreamMsgDistData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsgdist?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsgdist?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -467,6 +520,10 @@ type UpstreamMsgDistWeekData struct {
 
 // 获取消息发送分布周数据.
 func (clt *Client) GetUpstreamMsgDistWeek(param *DataCubeParam) (list []UpstreamMsgDistWeekData, err error) {
+	return clt.GetUpstreamMsgDistWeekContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgDistWeekContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgDistWeekData, err error) {
 	if param == nil {
 		err = errors.New("nil Request")
 		return
@@ -477,8 +534,8 @@ func (clt *Client) GetUpstreamMsgDistWeek(param *DataCubeParam) (list []Upstream
 		List []UpstreamMsgDistWeekData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsgdistweek?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsgdistweek?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -497,6 +554,10 @@ type UpstreamMsgDistMonthData struct {
 
 // 获取消息发送分布月数据.
 func (clt *Client) GetUpstreamMsgDistMonth(param *DataCubeParam) (list []UpstreamMsgDistMonthData, err error) {
+	return clt.GetUpstreamMsgDistMonthContext(context.Background(), param)
+}
+
+func (clt *Client) GetUpstreamMsgDistMonthContext(ctx context.Context, param *DataCubeParam) (list []UpstreamMsgDistMonthData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -507,8 +568,8 @@ func (clt *Client) GetUpstreamMsgDistMonth(param *DataCubeParam) (list []Upstrea
 		List []UpstreamMsgDistMonthData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getupstreammsgdistmonth?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getupstreammsgdistmonth?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -539,6 +600,10 @@ type UserSummaryData struct {
 
 // 获取用户增减数据.
 func (clt *Client) GetUserSummary(param *DataCubeParam) (list []UserSummaryData, err error) {
+	return clt.GetUserSummaryContext(context.Background(), param)
+}
+
+func (clt *Client) GetUserSummaryContext(ctx context.Context, param *DataCubeParam) (list []UserSummaryData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -549,8 +614,8 @@ func (clt *Client) GetUserSummary(param *DataCubeParam) (list []UserSummaryData,
 		List []UserSummaryData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getusersummary?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getusersummary?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
@@ -570,6 +635,10 @@ type UserCumulateData struct {
 
 // 获取累计用户数据.
 func (clt *Client) GetUserCumulate(param *DataCubeParam) (list []UserCumulateData, err error) {
+	return clt.GetUserCumulateContext(context.Background(), param)
+}
+
+func (clt *Client) GetUserCumulateContext(ctx context.Context, param *DataCubeParam) (list []UserCumulateData, err error) {
 	if param == nil {
 		err = errors.New("nil DataCubeParam")
 		return
@@ -580,8 +649,8 @@ func (clt *Client) GetUserCumulate(param *DataCubeParam) (list []UserCumulateDat
 		List []UserCumulateData `json:"list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/datacube/getusercumulate?access_token="
-	if err = clt.PostJSON(incompleteURL, param, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/datacube/getusercumulate?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, param, &result); err != nil {
 		return
 	}
 
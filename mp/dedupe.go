@@ -0,0 +1,69 @@
+package mp
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+// 微信服务器在 5 秒内收不到响应会断掉连接并重新发起请求, 总共重试三次, 所以默认记录 1 分钟.
+const DefaultDedupeTTL = time.Minute
+
+// 重复的消息(事件)之前的推送还在处理中, 见 Dedupe.
+var ErrDedupeInProgress = errors.New("mp: duplicate message is being processed")
+
+// 去掉微信服务器重试推送的重复消息(事件)的中间件, 已经处理成功的重复消息直接回复 "success", 不再调用 next.
+//  store 为 nil 时使用 util.NewMemorySeenStore(0), ttl <= 0 时使用 DefaultDedupeTTL.
+//
+//  NOTE:
+//  1. 普通消息用 MsgId 去重, 模板消息, 群发消息等事件用 MsgID 去重, 其他事件用 FromUserName + CreateTime + Event 去重;
+//  2. 之前的推送还在处理时(next 还没有返回), 重试的推送返回 ErrDedupeInProgress(DefaultErrorHandler 回复 500),
+//     之后的重试根据处理结果决定: 成功时直接回复, 失败(next 返回错误)时删除记录, 再次调用 next;
+//  3. store 出错时不去重, 直接调用 next;
+//  4. 可以用于 Router.Use, 也可以直接包装 Handler: mp.NewServer(appId, token, mp.Dedupe(nil, 0)(handler)).
+func Dedupe(store util.SeenStore, ttl time.Duration) Middleware {
+	if store == nil {
+		store = util.NewMemorySeenStore(0)
+	}
+	if ttl <= 0 {
+		ttl = DefaultDedupeTTL
+	}
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, req *Request) (reply interface{}, err error) {
+			key := dedupeKey(req.MixedMessage)
+			state, err := store.Claim(key, ttl)
+			if err != nil {
+				return next.ServeMessage(ctx, req)
+			}
+			switch state {
+			case util.SeenDone:
+				return nil, nil
+			case util.SeenProcessing:
+				return nil, ErrDedupeInProgress
+			}
+
+			if reply, err = next.ServeMessage(ctx, req); err != nil {
+				store.Forget(key)
+				return
+			}
+			store.Done(key, ttl)
+			return
+		})
+	}
+}
+
+// 消息(事件)的唯一标识, 以公众号的原始 ID 为前缀, 多个公众号可以共享同一个 SeenStore
+func dedupeKey(msg *MixedMessage) string {
+	prefix := "mp:" + msg.ToUserName + ":"
+	switch {
+	case msg.MsgId != 0:
+		return prefix + "msgid:" + strconv.FormatInt(msg.MsgId, 10)
+	case msg.MsgID != 0:
+		return prefix + "event:" + msg.Event + ":" + strconv.FormatInt(msg.MsgID, 10)
+	default:
+		return prefix + "event:" + msg.Event + ":" + msg.FromUserName + ":" + strconv.FormatInt(msg.CreateTime, 10)
+	}
+}
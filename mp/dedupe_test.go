@@ -0,0 +1,91 @@
+package mp
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	calls := 0
+	fail := true
+	handler := HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		calls++
+		if fail {
+			fail = false
+			return nil, errors.New("temporary failure")
+		}
+		msg := req.MixedMessage
+		return NewResText(msg.FromUserName, msg.ToUserName, msg.CreateTime, "ok"), nil
+	})
+	srv := NewServer(testAppId, testToken, Dedupe(nil, 0)(handler))
+
+	post := func() *httptest.ResponseRecorder {
+		query := signedQuery(time.Now().Unix(), "nonce")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(testTextXML)))
+		return w
+	}
+
+	// 处理失败时不记录, 微信服务器的重试会再次处理
+	if w := post(); w.Code != 500 {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if w := post(); !strings.Contains(w.Body.String(), "<Content>ok</Content>") {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+	// 重复的推送直接回复 success
+	if w := post(); w.Body.String() != "success" {
+		t.Fatalf("body = %s, want success", w.Body.String())
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestDedupeInProgress(t *testing.T) {
+	started, release := make(chan struct{}), make(chan error)
+	calls := 0
+	handler := HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			close(started)
+			if err := <-release; err != nil {
+				return nil, err
+			}
+		}
+		msg := req.MixedMessage
+		return NewResText(msg.FromUserName, msg.ToUserName, msg.CreateTime, "ok"), nil
+	})
+	srv := NewServer(testAppId, testToken, Dedupe(nil, 0)(handler))
+
+	post := func() *httptest.ResponseRecorder {
+		query := signedQuery(time.Now().Unix(), "nonce")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(testTextXML)))
+		return w
+	}
+
+	// 第一次推送还在处理时, 重试的推送不能回复 success
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- post() }()
+	<-started
+	if w := post(); w.Code != 500 {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+
+	// 第一次处理失败, 之后的重试再次处理
+	release <- errors.New("temporary failure")
+	if w := <-first; w.Code != 500 {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if w := post(); !strings.Contains(w.Body.String(), "<Content>ok</Content>") {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,388 @@
+// Code generated by errcodegen from errcode.txt; DO NOT EDIT.
+
+package mp
+
+const (
+	ErrCodeSystemBusy                  = -1      // 系统繁忙，此时请开发者稍候再试
+	ErrCodeInvalidCredential           = 40001   // 获取 access_token 时 AppSecret 错误，或者 access_token 无效
+	ErrCodeInvalidGrantType            = 40002   // 不合法的凭证类型
+	ErrCodeInvalidOpenId               = 40003   // 不合法的 OpenID
+	ErrCodeInvalidMediaType            = 40004   // 不合法的媒体文件类型
+	ErrCodeInvalidFileType             = 40005   // 不合法的文件类型
+	ErrCodeInvalidFileSize             = 40006   // 不合法的文件大小
+	ErrCodeInvalidMediaId              = 40007   // 不合法的媒体文件 id
+	ErrCodeInvalidMessageType          = 40008   // 不合法的消息类型
+	ErrCodeInvalidImageSize            = 40009   // 不合法的图片文件大小
+	ErrCodeInvalidVoiceSize            = 40010   // 不合法的语音文件大小
+	ErrCodeInvalidVideoSize            = 40011   // 不合法的视频文件大小
+	ErrCodeInvalidThumbSize            = 40012   // 不合法的缩略图文件大小
+	ErrCodeInvalidAppId                = 40013   // 不合法的 AppID
+	ErrCodeInvalidAccessToken          = 40014   // 不合法的 access_token
+	ErrCodeInvalidMenuType             = 40015   // 不合法的菜单类型
+	ErrCodeInvalidButtonCount          = 40016   // 不合法的按钮个数
+	ErrCodeInvalidButtonType           = 40017   // 不合法的按钮类型
+	ErrCodeInvalidButtonNameSize       = 40018   // 不合法的按钮名字长度
+	ErrCodeInvalidButtonKeySize        = 40019   // 不合法的按钮 KEY 长度
+	ErrCodeInvalidButtonURLSize        = 40020   // 不合法的按钮 URL 长度
+	ErrCodeInvalidMenuVersion          = 40021   // 不合法的菜单版本号
+	ErrCodeInvalidSubMenuLevel         = 40022   // 不合法的子菜单级数
+	ErrCodeInvalidSubButtonCount       = 40023   // 不合法的子菜单按钮个数
+	ErrCodeInvalidSubButtonType        = 40024   // 不合法的子菜单按钮类型
+	ErrCodeInvalidSubButtonNameSize    = 40025   // 不合法的子菜单按钮名字长度
+	ErrCodeInvalidSubButtonKeySize     = 40026   // 不合法的子菜单按钮 KEY 长度
+	ErrCodeInvalidSubButtonURLSize     = 40027   // 不合法的子菜单按钮 URL 长度
+	ErrCodeInvalidMenuAPIUser          = 40028   // 不合法的自定义菜单使用用户
+	ErrCodeInvalidOAuthCode            = 40029   // 不合法的 oauth_code
+	ErrCodeInvalidRefreshToken         = 40030   // 不合法的 refresh_token
+	ErrCodeInvalidOpenIdList           = 40031   // 不合法的 openid 列表
+	ErrCodeInvalidOpenIdListSize       = 40032   // 不合法的 openid 列表长度
+	ErrCodeInvalidCharset              = 40033   // 不合法的请求字符，不能包含 \uxxxx 格式的字符
+	ErrCodeInvalidParameter            = 40035   // 不合法的参数
+	ErrCodeInvalidTemplateIdSize       = 40036   // 不合法的 template_id 长度
+	ErrCodeInvalidTemplateId           = 40037   // 不合法的 template_id
+	ErrCodeInvalidRequestFormat        = 40038   // 不合法的请求格式
+	ErrCodeInvalidURLSize              = 40039   // 不合法的 URL 长度
+	ErrCodeInvalidGroupId              = 40050   // 不合法的分组 id
+	ErrCodeInvalidGroupName            = 40051   // 分组名字不合法
+	ErrCodeInvalidMediaIdSize          = 40118   // media_id 大小不合法
+	ErrCodeInvalidUseButtonType        = 40119   // button 类型错误
+	ErrCodeInvalidUseSubButtonType     = 40120   // 子 button 类型错误
+	ErrCodeInvalidUseMediaIdType       = 40121   // 不合法的 media_id 类型
+	ErrCodeInvalidWeixinName           = 40132   // 微信号不合法
+	ErrCodeInvalidImageFormat          = 40137   // 不支持的图片格式
+	ErrCodeInvalidIP                   = 40164   // 调用接口的 IP 地址不在白名单中
+	ErrCodeAccessTokenMissing          = 41001   // 缺少 access_token 参数
+	ErrCodeAppIdMissing                = 41002   // 缺少 appid 参数
+	ErrCodeRefreshTokenMissing         = 41003   // 缺少 refresh_token 参数
+	ErrCodeAppSecretMissing            = 41004   // 缺少 secret 参数
+	ErrCodeMediaDataMissing            = 41005   // 缺少多媒体文件数据
+	ErrCodeMediaIdMissing              = 41006   // 缺少 media_id 参数
+	ErrCodeSubMenuDataMissing          = 41007   // 缺少子菜单数据
+	ErrCodeOAuthCodeMissing            = 41008   // 缺少 oauth code
+	ErrCodeOpenIdMissing               = 41009   // 缺少 openid
+	ErrCodeAccessTokenExpired          = 42001   // access_token 超时
+	ErrCodeRefreshTokenExpired         = 42002   // refresh_token 超时
+	ErrCodeOAuthCodeExpired            = 42003   // oauth_code 超时
+	ErrCodeUserPasswordChanged         = 42007   // 用户修改微信密码，access_token 和 refresh_token 失效，需要重新授权
+	ErrCodeRequireGETMethod            = 43001   // 需要 GET 请求
+	ErrCodeRequirePOSTMethod           = 43002   // 需要 POST 请求
+	ErrCodeRequireHTTPS                = 43003   // 需要 HTTPS 请求
+	ErrCodeRequireSubscribe            = 43004   // 需要接收者关注
+	ErrCodeRequireFriendRelations      = 43005   // 需要好友关系
+	ErrCodeRequireRemoveBlacklist      = 43019   // 需要将接收者从黑名单中移除
+	ErrCodeChangeIndustryTooFrequently = 43100   // 修改模板所属行业太频繁
+	ErrCodeUserRefuseToAcceptMessage   = 43101   // 用户拒绝接受消息
+	ErrCodeEmptyMediaData              = 44001   // 多媒体文件为空
+	ErrCodeEmptyPostData               = 44002   // POST 的数据包为空
+	ErrCodeEmptyNewsData               = 44003   // 图文消息内容为空
+	ErrCodeEmptyContent                = 44004   // 文本消息内容为空
+	ErrCodeMediaSizeOutOfLimit         = 45001   // 多媒体文件大小超过限制
+	ErrCodeContentSizeOutOfLimit       = 45002   // 消息内容超过限制
+	ErrCodeTitleSizeOutOfLimit         = 45003   // 标题字段超过限制
+	ErrCodeDescriptionSizeOutOfLimit   = 45004   // 描述字段超过限制
+	ErrCodeURLSizeOutOfLimit           = 45005   // 链接字段超过限制
+	ErrCodePicURLSizeOutOfLimit        = 45006   // 图片链接字段超过限制
+	ErrCodePlaytimeOutOfLimit          = 45007   // 语音播放时间超过限制
+	ErrCodeArticleSizeOutOfLimit       = 45008   // 图文消息超过限制
+	ErrCodeAPIQuotaExceeded            = 45009   // 接口调用超过限制
+	ErrCodeCreateMenuLimit             = 45010   // 创建菜单个数超过限制
+	ErrCodeAPIMinuteQuotaExceeded      = 45011   // API 调用太频繁，请稍候再试
+	ErrCodeResponseOutOfTime           = 45015   // 回复时间超过限制
+	ErrCodeSystemGroupNotAllowed       = 45016   // 系统分组，不允许修改
+	ErrCodeGroupNameTooLong            = 45017   // 分组名字过长
+	ErrCodeGroupCountLimit             = 45018   // 分组数量超过上限
+	ErrCodeTemplateCountLimit          = 45026   // 模板数量超出限制
+	ErrCodeCustomMessageLimit          = 45047   // 客服接口下行条数超过上限
+	ErrCodeMediaDataNotExist           = 46001   // 不存在媒体数据
+	ErrCodeMenuVersionNotExist         = 46002   // 不存在的菜单版本
+	ErrCodeMenuDataNotExist            = 46003   // 不存在的菜单数据
+	ErrCodeUserNotExist                = 46004   // 不存在的用户
+	ErrCodeDataFormatError             = 47001   // 解析 JSON/XML 内容错误
+	ErrCodeTemplateArgumentInvalid     = 47003   // 模板参数不准确
+	ErrCodeAPIUnauthorized             = 48001   // api 功能未授权，请确认公众号已获得该接口
+	ErrCodeUserBlockMessage            = 48002   // 粉丝拒收消息
+	ErrCodeAPIForbidden                = 48004   // api 接口被封禁
+	ErrCodeMediaReferenced             = 48005   // api 禁止删除被自动回复和自定义菜单引用的素材
+	ErrCodeClearQuotaLimit             = 48006   // api 禁止清零调用次数，因为清零次数达到上限
+	ErrCodeNoPermissionForMessageType  = 48008   // 没有该类型消息的发送权限
+	ErrCodeUserUnauthorized            = 50001   // 用户未授权该 api
+	ErrCodeUserLimited                 = 50002   // 用户受限，可能是违规后接口被封禁
+	ErrCodeUserNotSubscribed           = 50005   // 用户未关注公众号
+	ErrCodeSystemError                 = 61450   // 系统错误
+	ErrCodeInvalidKfParameter          = 61451   // 参数错误
+	ErrCodeInvalidKfAccount            = 61452   // 无效客服账号
+	ErrCodeKfAccountExisted            = 61453   // 客服帐号已存在
+	ErrCodeInvalidKfAccountNameSize    = 61454   // 客服帐号名长度超过限制(仅允许 10 个英文字符，不包括 @ 及 @ 后的公众号的微信号)
+	ErrCodeInvalidKfAccountName        = 61455   // 客服帐号名包含非法字符(仅允许英文+数字)
+	ErrCodeKfAccountCountLimit         = 61456   // 客服帐号个数超过限制(10 个客服账号)
+	ErrCodeInvalidKfHeadImageFileType  = 61457   // 无效头像文件类型
+	ErrCodeInvalidDateFormat           = 61500   // 日期格式错误
+	ErrCodeConditionalMenuNotExist     = 65301   // 不存在此 menuid 对应的个性化菜单
+	ErrCodeNoSuchUser                  = 65302   // 没有相应的用户
+	ErrCodeDefaultMenuNotExist         = 65303   // 没有默认菜单，不能创建个性化菜单
+	ErrCodeEmptyMatchRule              = 65304   // MatchRule 信息为空
+	ErrCodeConditionalMenuCountLimit   = 65305   // 个性化菜单数量受限
+	ErrCodeConditionalMenuUnsupported  = 65306   // 不支持个性化菜单的帐号
+	ErrCodeEmptyConditionalMenu        = 65307   // 个性化菜单信息为空
+	ErrCodeButtonWithoutResponse       = 65308   // 包含没有响应类型的 button
+	ErrCodeConditionalMenuSwitchClosed = 65309   // 个性化菜单开关处于关闭状态
+	ErrCodeInvalidPOSTParameter        = 9001001 // POST 数据参数不合法
+	ErrCodeRemoteServiceUnavailable    = 9001002 // 远端服务不可用
+)
+
+// 可以用 errors.Is(err, ErrXxx) 判断错误码
+var (
+	ErrSystemBusy                  = &Error{ErrCode: ErrCodeSystemBusy, ErrMsg: "system busy, please try again later"}
+	ErrInvalidCredential           = &Error{ErrCode: ErrCodeInvalidCredential, ErrMsg: "invalid credential, access_token is invalid or not latest"}
+	ErrInvalidGrantType            = &Error{ErrCode: ErrCodeInvalidGrantType, ErrMsg: "invalid grant_type"}
+	ErrInvalidOpenId               = &Error{ErrCode: ErrCodeInvalidOpenId, ErrMsg: "invalid openid"}
+	ErrInvalidMediaType            = &Error{ErrCode: ErrCodeInvalidMediaType, ErrMsg: "invalid media type"}
+	ErrInvalidFileType             = &Error{ErrCode: ErrCodeInvalidFileType, ErrMsg: "invalid file type"}
+	ErrInvalidFileSize             = &Error{ErrCode: ErrCodeInvalidFileSize, ErrMsg: "invalid file size"}
+	ErrInvalidMediaId              = &Error{ErrCode: ErrCodeInvalidMediaId, ErrMsg: "invalid media_id"}
+	ErrInvalidMessageType          = &Error{ErrCode: ErrCodeInvalidMessageType, ErrMsg: "invalid message type"}
+	ErrInvalidImageSize            = &Error{ErrCode: ErrCodeInvalidImageSize, ErrMsg: "invalid image size"}
+	ErrInvalidVoiceSize            = &Error{ErrCode: ErrCodeInvalidVoiceSize, ErrMsg: "invalid voice size"}
+	ErrInvalidVideoSize            = &Error{ErrCode: ErrCodeInvalidVideoSize, ErrMsg: "invalid video size"}
+	ErrInvalidThumbSize            = &Error{ErrCode: ErrCodeInvalidThumbSize, ErrMsg: "invalid thumb size"}
+	ErrInvalidAppId                = &Error{ErrCode: ErrCodeInvalidAppId, ErrMsg: "invalid appid"}
+	ErrInvalidAccessToken          = &Error{ErrCode: ErrCodeInvalidAccessToken, ErrMsg: "invalid access_token"}
+	ErrInvalidMenuType             = &Error{ErrCode: ErrCodeInvalidMenuType, ErrMsg: "invalid menu type"}
+	ErrInvalidButtonCount          = &Error{ErrCode: ErrCodeInvalidButtonCount, ErrMsg: "invalid button count"}
+	ErrInvalidButtonType           = &Error{ErrCode: ErrCodeInvalidButtonType, ErrMsg: "invalid button type"}
+	ErrInvalidButtonNameSize       = &Error{ErrCode: ErrCodeInvalidButtonNameSize, ErrMsg: "invalid button name size"}
+	ErrInvalidButtonKeySize        = &Error{ErrCode: ErrCodeInvalidButtonKeySize, ErrMsg: "invalid button key size"}
+	ErrInvalidButtonURLSize        = &Error{ErrCode: ErrCodeInvalidButtonURLSize, ErrMsg: "invalid button url size"}
+	ErrInvalidMenuVersion          = &Error{ErrCode: ErrCodeInvalidMenuVersion, ErrMsg: "invalid menu version"}
+	ErrInvalidSubMenuLevel         = &Error{ErrCode: ErrCodeInvalidSubMenuLevel, ErrMsg: "invalid sub menu level"}
+	ErrInvalidSubButtonCount       = &Error{ErrCode: ErrCodeInvalidSubButtonCount, ErrMsg: "invalid sub button count"}
+	ErrInvalidSubButtonType        = &Error{ErrCode: ErrCodeInvalidSubButtonType, ErrMsg: "invalid sub button type"}
+	ErrInvalidSubButtonNameSize    = &Error{ErrCode: ErrCodeInvalidSubButtonNameSize, ErrMsg: "invalid sub button name size"}
+	ErrInvalidSubButtonKeySize     = &Error{ErrCode: ErrCodeInvalidSubButtonKeySize, ErrMsg: "invalid sub button key size"}
+	ErrInvalidSubButtonURLSize     = &Error{ErrCode: ErrCodeInvalidSubButtonURLSize, ErrMsg: "invalid sub button url size"}
+	ErrInvalidMenuAPIUser          = &Error{ErrCode: ErrCodeInvalidMenuAPIUser, ErrMsg: "invalid menu api user"}
+	ErrInvalidOAuthCode            = &Error{ErrCode: ErrCodeInvalidOAuthCode, ErrMsg: "invalid oauth code"}
+	ErrInvalidRefreshToken         = &Error{ErrCode: ErrCodeInvalidRefreshToken, ErrMsg: "invalid refresh_token"}
+	ErrInvalidOpenIdList           = &Error{ErrCode: ErrCodeInvalidOpenIdList, ErrMsg: "invalid openid list"}
+	ErrInvalidOpenIdListSize       = &Error{ErrCode: ErrCodeInvalidOpenIdListSize, ErrMsg: "invalid openid list size"}
+	ErrInvalidCharset              = &Error{ErrCode: ErrCodeInvalidCharset, ErrMsg: "invalid charset, \\uxxxx is not allowed"}
+	ErrInvalidParameter            = &Error{ErrCode: ErrCodeInvalidParameter, ErrMsg: "invalid parameter"}
+	ErrInvalidTemplateIdSize       = &Error{ErrCode: ErrCodeInvalidTemplateIdSize, ErrMsg: "invalid template_id size"}
+	ErrInvalidTemplateId           = &Error{ErrCode: ErrCodeInvalidTemplateId, ErrMsg: "invalid template_id"}
+	ErrInvalidRequestFormat        = &Error{ErrCode: ErrCodeInvalidRequestFormat, ErrMsg: "invalid request format"}
+	ErrInvalidURLSize              = &Error{ErrCode: ErrCodeInvalidURLSize, ErrMsg: "invalid url size"}
+	ErrInvalidGroupId              = &Error{ErrCode: ErrCodeInvalidGroupId, ErrMsg: "invalid group id"}
+	ErrInvalidGroupName            = &Error{ErrCode: ErrCodeInvalidGroupName, ErrMsg: "invalid group name"}
+	ErrInvalidMediaIdSize          = &Error{ErrCode: ErrCodeInvalidMediaIdSize, ErrMsg: "invalid media_id size"}
+	ErrInvalidUseButtonType        = &Error{ErrCode: ErrCodeInvalidUseButtonType, ErrMsg: "invalid use button type"}
+	ErrInvalidUseSubButtonType     = &Error{ErrCode: ErrCodeInvalidUseSubButtonType, ErrMsg: "invalid use sub button type"}
+	ErrInvalidUseMediaIdType       = &Error{ErrCode: ErrCodeInvalidUseMediaIdType, ErrMsg: "invalid media_id type"}
+	ErrInvalidWeixinName           = &Error{ErrCode: ErrCodeInvalidWeixinName, ErrMsg: "invalid weixin name"}
+	ErrInvalidImageFormat          = &Error{ErrCode: ErrCodeInvalidImageFormat, ErrMsg: "invalid image format"}
+	ErrInvalidIP                   = &Error{ErrCode: ErrCodeInvalidIP, ErrMsg: "invalid ip, not in whitelist"}
+	ErrAccessTokenMissing          = &Error{ErrCode: ErrCodeAccessTokenMissing, ErrMsg: "access_token missing"}
+	ErrAppIdMissing                = &Error{ErrCode: ErrCodeAppIdMissing, ErrMsg: "appid missing"}
+	ErrRefreshTokenMissing         = &Error{ErrCode: ErrCodeRefreshTokenMissing, ErrMsg: "refresh_token missing"}
+	ErrAppSecretMissing            = &Error{ErrCode: ErrCodeAppSecretMissing, ErrMsg: "appsecret missing"}
+	ErrMediaDataMissing            = &Error{ErrCode: ErrCodeMediaDataMissing, ErrMsg: "media data missing"}
+	ErrMediaIdMissing              = &Error{ErrCode: ErrCodeMediaIdMissing, ErrMsg: "media_id missing"}
+	ErrSubMenuDataMissing          = &Error{ErrCode: ErrCodeSubMenuDataMissing, ErrMsg: "sub menu data missing"}
+	ErrOAuthCodeMissing            = &Error{ErrCode: ErrCodeOAuthCodeMissing, ErrMsg: "oauth code missing"}
+	ErrOpenIdMissing               = &Error{ErrCode: ErrCodeOpenIdMissing, ErrMsg: "openid missing"}
+	ErrAccessTokenExpired          = &Error{ErrCode: ErrCodeAccessTokenExpired, ErrMsg: "access_token expired"}
+	ErrRefreshTokenExpired         = &Error{ErrCode: ErrCodeRefreshTokenExpired, ErrMsg: "refresh_token expired"}
+	ErrOAuthCodeExpired            = &Error{ErrCode: ErrCodeOAuthCodeExpired, ErrMsg: "oauth code expired"}
+	ErrUserPasswordChanged         = &Error{ErrCode: ErrCodeUserPasswordChanged, ErrMsg: "user changed password, access_token and refresh_token are invalid"}
+	ErrRequireGETMethod            = &Error{ErrCode: ErrCodeRequireGETMethod, ErrMsg: "require GET method"}
+	ErrRequirePOSTMethod           = &Error{ErrCode: ErrCodeRequirePOSTMethod, ErrMsg: "require POST method"}
+	ErrRequireHTTPS                = &Error{ErrCode: ErrCodeRequireHTTPS, ErrMsg: "require https"}
+	ErrRequireSubscribe            = &Error{ErrCode: ErrCodeRequireSubscribe, ErrMsg: "require subscribe"}
+	ErrRequireFriendRelations      = &Error{ErrCode: ErrCodeRequireFriendRelations, ErrMsg: "require friend relations"}
+	ErrRequireRemoveBlacklist      = &Error{ErrCode: ErrCodeRequireRemoveBlacklist, ErrMsg: "require remove blacklist"}
+	ErrChangeIndustryTooFrequently = &Error{ErrCode: ErrCodeChangeIndustryTooFrequently, ErrMsg: "change industry too frequently"}
+	ErrUserRefuseToAcceptMessage   = &Error{ErrCode: ErrCodeUserRefuseToAcceptMessage, ErrMsg: "user refuse to accept the msg"}
+	ErrEmptyMediaData              = &Error{ErrCode: ErrCodeEmptyMediaData, ErrMsg: "empty media data"}
+	ErrEmptyPostData               = &Error{ErrCode: ErrCodeEmptyPostData, ErrMsg: "empty post data"}
+	ErrEmptyNewsData               = &Error{ErrCode: ErrCodeEmptyNewsData, ErrMsg: "empty news data"}
+	ErrEmptyContent                = &Error{ErrCode: ErrCodeEmptyContent, ErrMsg: "empty content"}
+	ErrMediaSizeOutOfLimit         = &Error{ErrCode: ErrCodeMediaSizeOutOfLimit, ErrMsg: "media size out of limit"}
+	ErrContentSizeOutOfLimit       = &Error{ErrCode: ErrCodeContentSizeOutOfLimit, ErrMsg: "content size out of limit"}
+	ErrTitleSizeOutOfLimit         = &Error{ErrCode: ErrCodeTitleSizeOutOfLimit, ErrMsg: "title size out of limit"}
+	ErrDescriptionSizeOutOfLimit   = &Error{ErrCode: ErrCodeDescriptionSizeOutOfLimit, ErrMsg: "description size out of limit"}
+	ErrURLSizeOutOfLimit           = &Error{ErrCode: ErrCodeURLSizeOutOfLimit, ErrMsg: "url size out of limit"}
+	ErrPicURLSizeOutOfLimit        = &Error{ErrCode: ErrCodePicURLSizeOutOfLimit, ErrMsg: "picurl size out of limit"}
+	ErrPlaytimeOutOfLimit          = &Error{ErrCode: ErrCodePlaytimeOutOfLimit, ErrMsg: "playtime out of limit"}
+	ErrArticleSizeOutOfLimit       = &Error{ErrCode: ErrCodeArticleSizeOutOfLimit, ErrMsg: "article size out of limit"}
+	ErrAPIQuotaExceeded            = &Error{ErrCode: ErrCodeAPIQuotaExceeded, ErrMsg: "reach max api daily quota limit"}
+	ErrCreateMenuLimit             = &Error{ErrCode: ErrCodeCreateMenuLimit, ErrMsg: "create menu limit"}
+	ErrAPIMinuteQuotaExceeded      = &Error{ErrCode: ErrCodeAPIMinuteQuotaExceeded, ErrMsg: "api minute-quota reach limit, try later"}
+	ErrResponseOutOfTime           = &Error{ErrCode: ErrCodeResponseOutOfTime, ErrMsg: "response out of time limit"}
+	ErrSystemGroupNotAllowed       = &Error{ErrCode: ErrCodeSystemGroupNotAllowed, ErrMsg: "system group can not be modified"}
+	ErrGroupNameTooLong            = &Error{ErrCode: ErrCodeGroupNameTooLong, ErrMsg: "group name too long"}
+	ErrGroupCountLimit             = &Error{ErrCode: ErrCodeGroupCountLimit, ErrMsg: "too many group now, no need to add new"}
+	ErrTemplateCountLimit          = &Error{ErrCode: ErrCodeTemplateCountLimit, ErrMsg: "template num exceeds limit"}
+	ErrCustomMessageLimit          = &Error{ErrCode: ErrCodeCustomMessageLimit, ErrMsg: "out of response count limit"}
+	ErrMediaDataNotExist           = &Error{ErrCode: ErrCodeMediaDataNotExist, ErrMsg: "media data no exist"}
+	ErrMenuVersionNotExist         = &Error{ErrCode: ErrCodeMenuVersionNotExist, ErrMsg: "menu version no exist"}
+	ErrMenuDataNotExist            = &Error{ErrCode: ErrCodeMenuDataNotExist, ErrMsg: "menu no exist"}
+	ErrUserNotExist                = &Error{ErrCode: ErrCodeUserNotExist, ErrMsg: "user no exist"}
+	ErrDataFormatError             = &Error{ErrCode: ErrCodeDataFormatError, ErrMsg: "data format error"}
+	ErrTemplateArgumentInvalid     = &Error{ErrCode: ErrCodeTemplateArgumentInvalid, ErrMsg: "argument invalid"}
+	ErrAPIUnauthorized             = &Error{ErrCode: ErrCodeAPIUnauthorized, ErrMsg: "api unauthorized"}
+	ErrUserBlockMessage            = &Error{ErrCode: ErrCodeUserBlockMessage, ErrMsg: "user block message"}
+	ErrAPIForbidden                = &Error{ErrCode: ErrCodeAPIForbidden, ErrMsg: "api forbidden"}
+	ErrMediaReferenced             = &Error{ErrCode: ErrCodeMediaReferenced, ErrMsg: "forbid to delete material used by auto-reply or menu"}
+	ErrClearQuotaLimit             = &Error{ErrCode: ErrCodeClearQuotaLimit, ErrMsg: "forbid to clear quota because of reaching the limit"}
+	ErrNoPermissionForMessageType  = &Error{ErrCode: ErrCodeNoPermissionForMessageType, ErrMsg: "no permission for this msgtype"}
+	ErrUserUnauthorized            = &Error{ErrCode: ErrCodeUserUnauthorized, ErrMsg: "user unauthorized"}
+	ErrUserLimited                 = &Error{ErrCode: ErrCodeUserLimited, ErrMsg: "user limited"}
+	ErrUserNotSubscribed           = &Error{ErrCode: ErrCodeUserNotSubscribed, ErrMsg: "user is not subscribed"}
+	ErrSystemError                 = &Error{ErrCode: ErrCodeSystemError, ErrMsg: "system error"}
+	ErrInvalidKfParameter          = &Error{ErrCode: ErrCodeInvalidKfParameter, ErrMsg: "invalid parameter"}
+	ErrInvalidKfAccount            = &Error{ErrCode: ErrCodeInvalidKfAccount, ErrMsg: "invalid kf_account"}
+	ErrKfAccountExisted            = &Error{ErrCode: ErrCodeKfAccountExisted, ErrMsg: "kf_account exsited"}
+	ErrInvalidKfAccountNameSize    = &Error{ErrCode: ErrCodeInvalidKfAccountNameSize, ErrMsg: "invalid kf_acount length"}
+	ErrInvalidKfAccountName        = &Error{ErrCode: ErrCodeInvalidKfAccountName, ErrMsg: "illegal character in kf_account"}
+	ErrKfAccountCountLimit         = &Error{ErrCode: ErrCodeKfAccountCountLimit, ErrMsg: "kf_account count exceeded"}
+	ErrInvalidKfHeadImageFileType  = &Error{ErrCode: ErrCodeInvalidKfHeadImageFileType, ErrMsg: "invalid file type"}
+	ErrInvalidDateFormat           = &Error{ErrCode: ErrCodeInvalidDateFormat, ErrMsg: "date format error"}
+	ErrConditionalMenuNotExist     = &Error{ErrCode: ErrCodeConditionalMenuNotExist, ErrMsg: "conditional menu not exist"}
+	ErrNoSuchUser                  = &Error{ErrCode: ErrCodeNoSuchUser, ErrMsg: "no such user"}
+	ErrDefaultMenuNotExist         = &Error{ErrCode: ErrCodeDefaultMenuNotExist, ErrMsg: "there is no selfmenu, please create selfmenu first"}
+	ErrEmptyMatchRule              = &Error{ErrCode: ErrCodeEmptyMatchRule, ErrMsg: "match rule empty"}
+	ErrConditionalMenuCountLimit   = &Error{ErrCode: ErrCodeConditionalMenuCountLimit, ErrMsg: "menu count limit"}
+	ErrConditionalMenuUnsupported  = &Error{ErrCode: ErrCodeConditionalMenuUnsupported, ErrMsg: "conditional menu not support"}
+	ErrEmptyConditionalMenu        = &Error{ErrCode: ErrCodeEmptyConditionalMenu, ErrMsg: "conditional menu is empty"}
+	ErrButtonWithoutResponse       = &Error{ErrCode: ErrCodeButtonWithoutResponse, ErrMsg: "exist empty button act"}
+	ErrConditionalMenuSwitchClosed = &Error{ErrCode: ErrCodeConditionalMenuSwitchClosed, ErrMsg: "conditional menu switch is closed"}
+	ErrInvalidPOSTParameter        = &Error{ErrCode: ErrCodeInvalidPOSTParameter, ErrMsg: "invalid post parameter"}
+	ErrRemoteServiceUnavailable    = &Error{ErrCode: ErrCodeRemoteServiceUnavailable, ErrMsg: "remote service unavailable"}
+)
+
+var errCodeInfos = map[int]errCodeInfo{
+	ErrCodeSystemBusy:                  {"系统繁忙，此时请开发者稍候再试", "system busy, please try again later", true, false},
+	ErrCodeInvalidCredential:           {"获取 access_token 时 AppSecret 错误，或者 access_token 无效", "invalid credential, access_token is invalid or not latest", false, true},
+	ErrCodeInvalidGrantType:            {"不合法的凭证类型", "invalid grant_type", false, false},
+	ErrCodeInvalidOpenId:               {"不合法的 OpenID", "invalid openid", false, false},
+	ErrCodeInvalidMediaType:            {"不合法的媒体文件类型", "invalid media type", false, false},
+	ErrCodeInvalidFileType:             {"不合法的文件类型", "invalid file type", false, false},
+	ErrCodeInvalidFileSize:             {"不合法的文件大小", "invalid file size", false, false},
+	ErrCodeInvalidMediaId:              {"不合法的媒体文件 id", "invalid media_id", false, false},
+	ErrCodeInvalidMessageType:          {"不合法的消息类型", "invalid message type", false, false},
+	ErrCodeInvalidImageSize:            {"不合法的图片文件大小", "invalid image size", false, false},
+	ErrCodeInvalidVoiceSize:            {"不合法的语音文件大小", "invalid voice size", false, false},
+	ErrCodeInvalidVideoSize:            {"不合法的视频文件大小", "invalid video size", false, false},
+	ErrCodeInvalidThumbSize:            {"不合法的缩略图文件大小", "invalid thumb size", false, false},
+	ErrCodeInvalidAppId:                {"不合法的 AppID", "invalid appid", false, false},
+	ErrCodeInvalidAccessToken:          {"不合法的 access_token", "invalid access_token", false, true},
+	ErrCodeInvalidMenuType:             {"不合法的菜单类型", "invalid menu type", false, false},
+	ErrCodeInvalidButtonCount:          {"不合法的按钮个数", "invalid button count", false, false},
+	ErrCodeInvalidButtonType:           {"不合法的按钮类型", "invalid button type", false, false},
+	ErrCodeInvalidButtonNameSize:       {"不合法的按钮名字长度", "invalid button name size", false, false},
+	ErrCodeInvalidButtonKeySize:        {"不合法的按钮 KEY 长度", "invalid button key size", false, false},
+	ErrCodeInvalidButtonURLSize:        {"不合法的按钮 URL 长度", "invalid button url size", false, false},
+	ErrCodeInvalidMenuVersion:          {"不合法的菜单版本号", "invalid menu version", false, false},
+	ErrCodeInvalidSubMenuLevel:         {"不合法的子菜单级数", "invalid sub menu level", false, false},
+	ErrCodeInvalidSubButtonCount:       {"不合法的子菜单按钮个数", "invalid sub button count", false, false},
+	ErrCodeInvalidSubButtonType:        {"不合法的子菜单按钮类型", "invalid sub button type", false, false},
+	ErrCodeInvalidSubButtonNameSize:    {"不合法的子菜单按钮名字长度", "invalid sub button name size", false, false},
+	ErrCodeInvalidSubButtonKeySize:     {"不合法的子菜单按钮 KEY 长度", "invalid sub button key size", false, false},
+	ErrCodeInvalidSubButtonURLSize:     {"不合法的子菜单按钮 URL 长度", "invalid sub button url size", false, false},
+	ErrCodeInvalidMenuAPIUser:          {"不合法的自定义菜单使用用户", "invalid menu api user", false, false},
+	ErrCodeInvalidOAuthCode:            {"不合法的 oauth_code", "invalid oauth code", false, false},
+	ErrCodeInvalidRefreshToken:         {"不合法的 refresh_token", "invalid refresh_token", false, false},
+	ErrCodeInvalidOpenIdList:           {"不合法的 openid 列表", "invalid openid list", false, false},
+	ErrCodeInvalidOpenIdListSize:       {"不合法的 openid 列表长度", "invalid openid list size", false, false},
+	ErrCodeInvalidCharset:              {"不合法的请求字符，不能包含 \\uxxxx 格式的字符", "invalid charset, \\uxxxx is not allowed", false, false},
+	ErrCodeInvalidParameter:            {"不合法的参数", "invalid parameter", false, false},
+	ErrCodeInvalidTemplateIdSize:       {"不合法的 template_id 长度", "invalid template_id size", false, false},
+	ErrCodeInvalidTemplateId:           {"不合法的 template_id", "invalid template_id", false, false},
+	ErrCodeInvalidRequestFormat:        {"不合法的请求格式", "invalid request format", false, false},
+	ErrCodeInvalidURLSize:              {"不合法的 URL 长度", "invalid url size", false, false},
+	ErrCodeInvalidGroupId:              {"不合法的分组 id", "invalid group id", false, false},
+	ErrCodeInvalidGroupName:            {"分组名字不合法", "invalid group name", false, false},
+	ErrCodeInvalidMediaIdSize:          {"media_id 大小不合法", "invalid media_id size", false, false},
+	ErrCodeInvalidUseButtonType:        {"button 类型错误", "invalid use button type", false, false},
+	ErrCodeInvalidUseSubButtonType:     {"子 button 类型错误", "invalid use sub button type", false, false},
+	ErrCodeInvalidUseMediaIdType:       {"不合法的 media_id 类型", "invalid media_id type", false, false},
+	ErrCodeInvalidWeixinName:           {"微信号不合法", "invalid weixin name", false, false},
+	ErrCodeInvalidImageFormat:          {"不支持的图片格式", "invalid image format", false, false},
+	ErrCodeInvalidIP:                   {"调用接口的 IP 地址不在白名单中", "invalid ip, not in whitelist", false, false},
+	ErrCodeAccessTokenMissing:          {"缺少 access_token 参数", "access_token missing", false, false},
+	ErrCodeAppIdMissing:                {"缺少 appid 参数", "appid missing", false, false},
+	ErrCodeRefreshTokenMissing:         {"缺少 refresh_token 参数", "refresh_token missing", false, false},
+	ErrCodeAppSecretMissing:            {"缺少 secret 参数", "appsecret missing", false, false},
+	ErrCodeMediaDataMissing:            {"缺少多媒体文件数据", "media data missing", false, false},
+	ErrCodeMediaIdMissing:              {"缺少 media_id 参数", "media_id missing", false, false},
+	ErrCodeSubMenuDataMissing:          {"缺少子菜单数据", "sub menu data missing", false, false},
+	ErrCodeOAuthCodeMissing:            {"缺少 oauth code", "oauth code missing", false, false},
+	ErrCodeOpenIdMissing:               {"缺少 openid", "openid missing", false, false},
+	ErrCodeAccessTokenExpired:          {"access_token 超时", "access_token expired", false, true},
+	ErrCodeRefreshTokenExpired:         {"refresh_token 超时", "refresh_token expired", false, false},
+	ErrCodeOAuthCodeExpired:            {"oauth_code 超时", "oauth code expired", false, false},
+	ErrCodeUserPasswordChanged:         {"用户修改微信密码，access_token 和 refresh_token 失效，需要重新授权", "user changed password, access_token and refresh_token are invalid", false, false},
+	ErrCodeRequireGETMethod:            {"需要 GET 请求", "require GET method", false, false},
+	ErrCodeRequirePOSTMethod:           {"需要 POST 请求", "require POST method", false, false},
+	ErrCodeRequireHTTPS:                {"需要 HTTPS 请求", "require https", false, false},
+	ErrCodeRequireSubscribe:            {"需要接收者关注", "require subscribe", false, false},
+	ErrCodeRequireFriendRelations:      {"需要好友关系", "require friend relations", false, false},
+	ErrCodeRequireRemoveBlacklist:      {"需要将接收者从黑名单中移除", "require remove blacklist", false, false},
+	ErrCodeChangeIndustryTooFrequently: {"修改模板所属行业太频繁", "change industry too frequently", false, false},
+	ErrCodeUserRefuseToAcceptMessage:   {"用户拒绝接受消息", "user refuse to accept the msg", false, false},
+	ErrCodeEmptyMediaData:              {"多媒体文件为空", "empty media data", false, false},
+	ErrCodeEmptyPostData:               {"POST 的数据包为空", "empty post data", false, false},
+	ErrCodeEmptyNewsData:               {"图文消息内容为空", "empty news data", false, false},
+	ErrCodeEmptyContent:                {"文本消息内容为空", "empty content", false, false},
+	ErrCodeMediaSizeOutOfLimit:         {"多媒体文件大小超过限制", "media size out of limit", false, false},
+	ErrCodeContentSizeOutOfLimit:       {"消息内容超过限制", "content size out of limit", false, false},
+	ErrCodeTitleSizeOutOfLimit:         {"标题字段超过限制", "title size out of limit", false, false},
+	ErrCodeDescriptionSizeOutOfLimit:   {"描述字段超过限制", "description size out of limit", false, false},
+	ErrCodeURLSizeOutOfLimit:           {"链接字段超过限制", "url size out of limit", false, false},
+	ErrCodePicURLSizeOutOfLimit:        {"图片链接字段超过限制", "picurl size out of limit", false, false},
+	ErrCodePlaytimeOutOfLimit:          {"语音播放时间超过限制", "playtime out of limit", false, false},
+	ErrCodeArticleSizeOutOfLimit:       {"图文消息超过限制", "article size out of limit", false, false},
+	ErrCodeAPIQuotaExceeded:            {"接口调用超过限制", "reach max api daily quota limit", false, false},
+	ErrCodeCreateMenuLimit:             {"创建菜单个数超过限制", "create menu limit", false, false},
+	ErrCodeAPIMinuteQuotaExceeded:      {"API 调用太频繁，请稍候再试", "api minute-quota reach limit, try later", true, false},
+	ErrCodeResponseOutOfTime:           {"回复时间超过限制", "response out of time limit", false, false},
+	ErrCodeSystemGroupNotAllowed:       {"系统分组，不允许修改", "system group can not be modified", false, false},
+	ErrCodeGroupNameTooLong:            {"分组名字过长", "group name too long", false, false},
+	ErrCodeGroupCountLimit:             {"分组数量超过上限", "too many group now, no need to add new", false, false},
+	ErrCodeTemplateCountLimit:          {"模板数量超出限制", "template num exceeds limit", false, false},
+	ErrCodeCustomMessageLimit:          {"客服接口下行条数超过上限", "out of response count limit", false, false},
+	ErrCodeMediaDataNotExist:           {"不存在媒体数据", "media data no exist", false, false},
+	ErrCodeMenuVersionNotExist:         {"不存在的菜单版本", "menu version no exist", false, false},
+	ErrCodeMenuDataNotExist:            {"不存在的菜单数据", "menu no exist", false, false},
+	ErrCodeUserNotExist:                {"不存在的用户", "user no exist", false, false},
+	ErrCodeDataFormatError:             {"解析 JSON/XML 内容错误", "data format error", false, false},
+	ErrCodeTemplateArgumentInvalid:     {"模板参数不准确", "argument invalid", false, false},
+	ErrCodeAPIUnauthorized:             {"api 功能未授权，请确认公众号已获得该接口", "api unauthorized", false, false},
+	ErrCodeUserBlockMessage:            {"粉丝拒收消息", "user block message", false, false},
+	ErrCodeAPIForbidden:                {"api 接口被封禁", "api forbidden", false, false},
+	ErrCodeMediaReferenced:             {"api 禁止删除被自动回复和自定义菜单引用的素材", "forbid to delete material used by auto-reply or menu", false, false},
+	ErrCodeClearQuotaLimit:             {"api 禁止清零调用次数，因为清零次数达到上限", "forbid to clear quota because of reaching the limit", false, false},
+	ErrCodeNoPermissionForMessageType:  {"没有该类型消息的发送权限", "no permission for this msgtype", false, false},
+	ErrCodeUserUnauthorized:            {"用户未授权该 api", "user unauthorized", false, false},
+	ErrCodeUserLimited:                 {"用户受限，可能是违规后接口被封禁", "user limited", false, false},
+	ErrCodeUserNotSubscribed:           {"用户未关注公众号", "user is not subscribed", false, false},
+	ErrCodeSystemError:                 {"系统错误", "system error", true, false},
+	ErrCodeInvalidKfParameter:          {"参数错误", "invalid parameter", false, false},
+	ErrCodeInvalidKfAccount:            {"无效客服账号", "invalid kf_account", false, false},
+	ErrCodeKfAccountExisted:            {"客服帐号已存在", "kf_account exsited", false, false},
+	ErrCodeInvalidKfAccountNameSize:    {"客服帐号名长度超过限制(仅允许 10 个英文字符，不包括 @ 及 @ 后的公众号的微信号)", "invalid kf_acount length", false, false},
+	ErrCodeInvalidKfAccountName:        {"客服帐号名包含非法字符(仅允许英文+数字)", "illegal character in kf_account", false, false},
+	ErrCodeKfAccountCountLimit:         {"客服帐号个数超过限制(10 个客服账号)", "kf_account count exceeded", false, false},
+	ErrCodeInvalidKfHeadImageFileType:  {"无效头像文件类型", "invalid file type", false, false},
+	ErrCodeInvalidDateFormat:           {"日期格式错误", "date format error", false, false},
+	ErrCodeConditionalMenuNotExist:     {"不存在此 menuid 对应的个性化菜单", "conditional menu not exist", false, false},
+	ErrCodeNoSuchUser:                  {"没有相应的用户", "no such user", false, false},
+	ErrCodeDefaultMenuNotExist:         {"没有默认菜单，不能创建个性化菜单", "there is no selfmenu, please create selfmenu first", false, false},
+	ErrCodeEmptyMatchRule:              {"MatchRule 信息为空", "match rule empty", false, false},
+	ErrCodeConditionalMenuCountLimit:   {"个性化菜单数量受限", "menu count limit", false, false},
+	ErrCodeConditionalMenuUnsupported:  {"不支持个性化菜单的帐号", "conditional menu not support", false, false},
+	ErrCodeEmptyConditionalMenu:        {"个性化菜单信息为空", "conditional menu is empty", false, false},
+	ErrCodeButtonWithoutResponse:       {"包含没有响应类型的 button", "exist empty button act", false, false},
+	ErrCodeConditionalMenuSwitchClosed: {"个性化菜单开关处于关闭状态", "conditional menu switch is closed", false, false},
+	ErrCodeInvalidPOSTParameter:        {"POST 数据参数不合法", "invalid post parameter", false, false},
+	ErrCodeRemoteServiceUnavailable:    {"远端服务不可用", "remote service unavailable", true, false},
+}
@@ -7,10 +7,11 @@ package mp
 
 import "fmt"
 
+//go:generate go run ../internal/errcodegen -pkg mp -type int -in errcode.txt -out errcode.go
+
 const (
-	ErrCodeOK                = 0
-	ErrCodeInvalidCredential = 40001 // access_token 过期（无效）返回这个错误（maybe!!!）
-	ErrCodeTimeout           = 42001 // access_token 过期（无效）返回这个错误
+	ErrCodeOK      = 0
+	ErrCodeTimeout = ErrCodeAccessTokenExpired // access_token 过期（无效）返回这个错误
 )
 
 type Error struct {
@@ -21,3 +22,33 @@ type Error struct {
 func (e *Error) Error() string {
 	return fmt.Sprintf("errcode: %d, errmsg: %s", e.ErrCode, e.ErrMsg)
 }
+
+// 错误码相同即认为是同一个错误, 所以可以用 errors.Is(err, ErrAPIQuotaExceeded) 判断.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.ErrCode == e.ErrCode
+}
+
+// 是否是临时性的错误(系统繁忙, 调用太频繁等), 稍后重试可能成功.
+func (e *Error) Temporary() bool {
+	return errCodeInfos[e.ErrCode].temporary
+}
+
+// 是否可以重试: 临时性的错误, 或者刷新 access_token 后可以重试的错误.
+func (e *Error) Retryable() bool {
+	info := errCodeInfos[e.ErrCode]
+	return info.temporary || info.refreshToken
+}
+
+// 错误码的中文说明和英文说明, 如果错误码不在目录(errcode.txt)中, ok == false.
+func ErrCodeDescription(errCode int) (zh, en string, ok bool) {
+	info, ok := errCodeInfos[errCode]
+	return info.zh, info.en, ok
+}
+
+type errCodeInfo struct {
+	zh           string
+	en           string
+	temporary    bool // 临时性错误
+	refreshToken bool // 刷新 access_token 后可以重试
+}
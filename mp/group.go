@@ -1,6 +1,7 @@
 package mp
 
 import (
+	"context"
 	"errors"
 )
 
@@ -16,6 +17,10 @@ type Group struct {
 // 创建分组.
 //  name: 分组名字（30个字符以内）.
 func (clt *Client) CreateGroup(name string) (group *Group, err error) {
+	return clt.CreateGroupContext(context.Background(), name)
+}
+
+func (clt *Client) CreateGroupContext(ctx context.Context, name string) (group *Group, err error) {
 	if name == "" {
 		err = errors.New(`name == ""`)
 		return
@@ -33,8 +38,8 @@ func (clt *Client) CreateGroup(name string) (group *Group, err error) {
 		Group `json:"group"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/groups/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/groups/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -49,6 +54,10 @@ func (clt *Client) CreateGroup(name string) (group *Group, err error) {
 
 // 查询所有分组.
 func (clt *Client) ListGroup() (groups []Group, err error) {
+	return clt.ListGroupContext(context.Background())
+}
+
+func (clt *Client) ListGroupContext(ctx context.Context) (groups []Group, err error) {
 	var result = struct {
 		Error
 		Groups []Group `json:"groups"`
@@ -56,8 +65,8 @@ func (clt *Client) ListGroup() (groups []Group, err error) {
 		Groups: make([]Group, 0, 16),
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/groups/get?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/groups/get?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -72,6 +81,10 @@ func (clt *Client) ListGroup() (groups []Group, err error) {
 // 修改分组名.
 //  name: 分组名字（30个字符以内）.
 func (clt *Client) GroupRename(groupId int64, newName string) (err error) {
+	return clt.GroupRenameContext(context.Background(), groupId, newName)
+}
+
+func (clt *Client) GroupRenameContext(ctx context.Context, groupId int64, newName string) (err error) {
 	if newName == "" {
 		return errors.New(`newName == ""`)
 	}
@@ -87,8 +100,8 @@ func (clt *Client) GroupRename(groupId int64, newName string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/groups/update?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/groups/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -101,6 +114,10 @@ func (clt *Client) GroupRename(groupId int64, newName string) (err error) {
 
 // 查询用户所在分组.
 func (clt *Client) UserInWhichGroup(openId string) (groupId int64, err error) {
+	return clt.UserInWhichGroupContext(context.Background(), openId)
+}
+
+func (clt *Client) UserInWhichGroupContext(ctx context.Context, openId string) (groupId int64, err error) {
 	var request = struct {
 		OpenId string `json:"openid"`
 	}{
@@ -112,8 +129,8 @@ func (clt *Client) UserInWhichGroup(openId string) (groupId int64, err error) {
 		GroupId int64 `json:"groupid"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/groups/getid?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/groups/getid?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -127,6 +144,10 @@ func (clt *Client) UserInWhichGroup(openId string) (groupId int64, err error) {
 
 // 移动用户分组.
 func (clt *Client) UserMoveToGroup(openId string, toGroupId int64) (err error) {
+	return clt.UserMoveToGroupContext(context.Background(), openId, toGroupId)
+}
+
+func (clt *Client) UserMoveToGroupContext(ctx context.Context, openId string, toGroupId int64) (err error) {
 	var request = struct {
 		OpenId    string `json:"openid"`
 		ToGroupId int64  `json:"to_groupid"`
@@ -137,8 +158,8 @@ func (clt *Client) UserMoveToGroup(openId string, toGroupId int64) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/groups/members/update?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/groups/members/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -151,6 +172,10 @@ func (clt *Client) UserMoveToGroup(openId string, toGroupId int64) (err error) {
 
 // 批量移动用户分组.
 func (clt *Client) UserBatchMoveToGroup(openIdList []string, toGroupId int64) (err error) {
+	return clt.UserBatchMoveToGroupContext(context.Background(), openIdList, toGroupId)
+}
+
+func (clt *Client) UserBatchMoveToGroupContext(ctx context.Context, openIdList []string, toGroupId int64) (err error) {
 	var request = struct {
 		OpenIdList []string `json:"openid_list"`
 		ToGroupId  int64    `json:"to_groupid"`
@@ -161,8 +186,8 @@ func (clt *Client) UserBatchMoveToGroup(openIdList []string, toGroupId int64) (e
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/groups/members/batchupdate?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/groups/members/batchupdate?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -1,6 +1,7 @@
 package mp
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -62,13 +63,17 @@ func (info *KfInfo) HeadImageSize() (size int, err error) {
 
 // 获取客服基本信息.
 func (clt *Client) KfList() (KfList []KfInfo, err error) {
+	return clt.KfListContext(context.Background())
+}
+
+func (clt *Client) KfListContext(ctx context.Context) (KfList []KfInfo, err error) {
 	var result struct {
 		Error
 		KfList []KfInfo `json:"kf_list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/customservice/getkflist?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/customservice/getkflist?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -91,13 +96,17 @@ type OnlineKfInfo struct {
 
 // 获取在线客服接待信息.
 func (clt *Client) OnlineKfList() (KfList []OnlineKfInfo, err error) {
+	return clt.OnlineKfListContext(context.Background())
+}
+
+func (clt *Client) OnlineKfListContext(ctx context.Context) (KfList []OnlineKfInfo, err error) {
 	var result struct {
 		Error
 		KfList []OnlineKfInfo `json:"kf_online_list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/customservice/getonlinekflist?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/customservice/getonlinekflist?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -115,6 +124,10 @@ func (clt *Client) OnlineKfList() (KfList []OnlineKfInfo, err error) {
 //  password:   客服账号登录密码
 //  isPwdPlain: 标识 password 是否为明文格式, true 表示是明文密码, false 表示是密文密码.
 func (clt *Client) AddKfAccount(account, nickname, password string, isPwdPlain bool) (err error) {
+	return clt.AddKfAccountContext(context.Background(), account, nickname, password, isPwdPlain)
+}
+
+func (clt *Client) AddKfAccountContext(ctx context.Context, account, nickname, password string, isPwdPlain bool) (err error) {
 	if isPwdPlain {
 		md5Sum := md5.Sum([]byte(password))
 		password = hex.EncodeToString(md5Sum[:])
@@ -132,8 +145,8 @@ func (clt *Client) AddKfAccount(account, nickname, password string, isPwdPlain b
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfaccount/add?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/customservice/kfaccount/add?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -150,6 +163,10 @@ func (clt *Client) AddKfAccount(account, nickname, password string, isPwdPlain b
 //  password:   客服账号登录密码
 //  isPwdPlain: 标识 password 是否为明文格式, true 表示是明文密码, false 表示是密文密码.
 func (clt *Client) SetKfAccount(account, nickname, password string, isPwdPlain bool) (err error) {
+	return clt.SetKfAccountContext(context.Background(), account, nickname, password, isPwdPlain)
+}
+
+func (clt *Client) SetKfAccountContext(ctx context.Context, account, nickname, password string, isPwdPlain bool) (err error) {
 	if isPwdPlain {
 		md5Sum := md5.Sum([]byte(password))
 		password = hex.EncodeToString(md5Sum[:])
@@ -167,8 +184,8 @@ func (clt *Client) SetKfAccount(account, nickname, password string, isPwdPlain b
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfaccount/update?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/customservice/kfaccount/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -182,6 +199,10 @@ func (clt *Client) SetKfAccount(account, nickname, password string, isPwdPlain b
 // 上传客服头像.
 //  开发者可调用本接口来上传图片作为客服人员的头像，头像图片文件必须是jpg格式，推荐使用640*640大小的图片以达到最佳效果。
 func (clt *Client) UploadKfHeadImage(kfAccount, imagePath string) (err error) {
+	return clt.UploadKfHeadImageContext(context.Background(), kfAccount, imagePath)
+}
+
+func (clt *Client) UploadKfHeadImageContext(ctx context.Context, kfAccount, imagePath string) (err error) {
 	if kfAccount == "" {
 		return errors.New("empty kfAccount")
 	}
@@ -191,13 +212,17 @@ func (clt *Client) UploadKfHeadImage(kfAccount, imagePath string) (err error) {
 	}
 	defer file.Close()
 
-	return clt.uploadKfHeadImageFromReader(kfAccount, filepath.Base(imagePath), file)
+	return clt.uploadKfHeadImageFromReader(ctx, kfAccount, filepath.Base(imagePath), file)
 }
 
 // 上传客服头像.
 //  开发者可调用本接口来上传图片作为客服人员的头像，头像图片文件必须是jpg格式，推荐使用640*640大小的图片以达到最佳效果。
 //  注意参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadKfHeadImageFromReader(kfAccount, filename string, reader io.Reader) (err error) {
+	return clt.UploadKfHeadImageFromReaderContext(context.Background(), kfAccount, filename, reader)
+}
+
+func (clt *Client) UploadKfHeadImageFromReaderContext(ctx context.Context, kfAccount, filename string, reader io.Reader) (err error) {
 	if kfAccount == "" {
 		return errors.New("empty kfAccount")
 	}
@@ -208,17 +233,17 @@ func (clt *Client) UploadKfHeadImageFromReader(kfAccount, filename string, reade
 		return errors.New("nil reader")
 	}
 
-	return clt.uploadKfHeadImageFromReader(kfAccount, filename, reader)
+	return clt.uploadKfHeadImageFromReader(ctx, kfAccount, filename, reader)
 }
 
 // 上传客服头像.
 //  注意参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
-func (clt *Client) uploadKfHeadImageFromReader(kfAccount, filename string, reader io.Reader) (err error) {
+func (clt *Client) uploadKfHeadImageFromReader(ctx context.Context, kfAccount, filename string, reader io.Reader) (err error) {
 	var result Error
 
-	incompleteURL := "http://api.weixin.qq.com/customservice/kfaccount/uploadheadimg?kf_account=" +
+	incompleteURL := clt.apiBaseURL + "/customservice/kfaccount/uploadheadimg?kf_account=" +
 		url.QueryEscape(kfAccount) + "&access_token="
-	if err = clt.UploadFromReader(incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
+	if err = clt.UploadFromReaderContext(ctx, incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
 		return
 	}
 
@@ -231,11 +256,15 @@ func (clt *Client) uploadKfHeadImageFromReader(kfAccount, filename string, reade
 
 // 删除客服账号
 func (clt *Client) DeleteKfAccount(kfAccount string) (err error) {
+	return clt.DeleteKfAccountContext(context.Background(), kfAccount)
+}
+
+func (clt *Client) DeleteKfAccountContext(ctx context.Context, kfAccount string) (err error) {
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfaccount/del?kf_account=" +
+	incompleteURL := clt.apiBaseURL + "/customservice/kfaccount/del?kf_account=" +
 		kfAccount + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
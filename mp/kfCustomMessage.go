@@ -0,0 +1,313 @@
+package mp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	CustomMsgTypeText    = "text"
+	CustomMsgTypeImage   = "image"
+	CustomMsgTypeVoice   = "voice"
+	CustomMsgTypeVideo   = "video"
+	CustomMsgTypeMusic   = "music"
+	CustomMsgTypeNews    = "news"    // 图文消息(点击跳转到外链)
+	CustomMsgTypeMPNews  = "mpnews"  // 图文消息(点击跳转到图文消息页面)
+	CustomMsgTypeWxCard  = "wxcard"  // 卡券
+	CustomMsgTypeMsgMenu = "msgmenu" // 菜单消息
+)
+
+const CustomNewsArticleCountLimit = 1 // 客服图文消息(news)里文章的个数限制
+
+const (
+	customTypingCommandTyping = "Typing"
+	customTypingCommandCancel = "CancelTyping"
+)
+
+type CustomService struct {
+	KfAccount string `json:"kf_account"`
+}
+
+type CommonCustomMessageHeader struct {
+	ToUser        string         `json:"touser"`
+	MsgType       string         `json:"msgtype"`
+	CustomService *CustomService `json:"customservice,omitempty"` // 以某个客服账号来发消息, 可以为 nil
+}
+
+// 以客服账号 kfAccount 发送消息, kfAccount 为 "" 时取消.
+//  kfAccount 的格式为: 账号前缀@公众号微信号
+func (hdr *CommonCustomMessageHeader) SetKfAccount(kfAccount string) {
+	if kfAccount == "" {
+		hdr.CustomService = nil
+		return
+	}
+	hdr.CustomService = &CustomService{KfAccount: kfAccount}
+}
+
+// 文本消息
+type CustomText struct {
+	CommonCustomMessageHeader
+	Text struct {
+		Content string `json:"content"`
+	} `json:"text"`
+}
+
+// 新建文本消息
+//  NOTE: content 支持换行符
+func NewCustomText(toUser, content string) *CustomText {
+	var msg CustomText
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeText
+	msg.Text.Content = content
+	return &msg
+}
+
+// 图片消息
+type CustomImage struct {
+	CommonCustomMessageHeader
+	Image struct {
+		MediaId string `json:"media_id"`
+	} `json:"image"`
+}
+
+// 新建图片消息
+//  mediaId 通过上传多媒体文件得到
+func NewCustomImage(toUser, mediaId string) *CustomImage {
+	var msg CustomImage
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeImage
+	msg.Image.MediaId = mediaId
+	return &msg
+}
+
+// 语音消息
+type CustomVoice struct {
+	CommonCustomMessageHeader
+	Voice struct {
+		MediaId string `json:"media_id"`
+	} `json:"voice"`
+}
+
+// 新建语音消息
+//  mediaId 通过上传多媒体文件得到
+func NewCustomVoice(toUser, mediaId string) *CustomVoice {
+	var msg CustomVoice
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeVoice
+	msg.Voice.MediaId = mediaId
+	return &msg
+}
+
+// 视频消息
+type CustomVideo struct {
+	CommonCustomMessageHeader
+	Video struct {
+		MediaId      string `json:"media_id"`
+		ThumbMediaId string `json:"thumb_media_id,omitempty"`
+		Title        string `json:"title,omitempty"`
+		Description  string `json:"description,omitempty"`
+	} `json:"video"`
+}
+
+// 新建视频消息
+//  mediaId, thumbMediaId 通过上传多媒体文件得到
+//  thumbMediaId, title, description 可以为 ""
+func NewCustomVideo(toUser, mediaId, thumbMediaId, title, description string) *CustomVideo {
+	var msg CustomVideo
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeVideo
+	msg.Video.MediaId = mediaId
+	msg.Video.ThumbMediaId = thumbMediaId
+	msg.Video.Title = title
+	msg.Video.Description = description
+	return &msg
+}
+
+// 音乐消息
+type CustomMusic struct {
+	CommonCustomMessageHeader
+	Music struct {
+		Title        string `json:"title,omitempty"`
+		Description  string `json:"description,omitempty"`
+		MusicURL     string `json:"musicurl"`
+		HQMusicURL   string `json:"hqmusicurl"`
+		ThumbMediaId string `json:"thumb_media_id"`
+	} `json:"music"`
+}
+
+// 新建音乐消息
+//  thumbMediaId 通过上传多媒体文件得到
+//  title, description 可以为 ""
+func NewCustomMusic(toUser, thumbMediaId, musicURL, HQMusicURL, title, description string) *CustomMusic {
+	var msg CustomMusic
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeMusic
+	msg.Music.Title = title
+	msg.Music.Description = description
+	msg.Music.MusicURL = musicURL
+	msg.Music.HQMusicURL = HQMusicURL
+	msg.Music.ThumbMediaId = thumbMediaId
+	return &msg
+}
+
+// 图文消息里的文章
+type CustomArticle struct {
+	Title       string `json:"title,omitempty"`       // 图文消息标题
+	Description string `json:"description,omitempty"` // 图文消息描述
+	URL         string `json:"url,omitempty"`         // 点击后跳转的链接
+	PicURL      string `json:"picurl,omitempty"`      // 图片链接, 支持JPG, PNG格式, 较好的效果为大图640*320, 小图80*80
+}
+
+// 图文消息(点击跳转到外链)
+type CustomNews struct {
+	CommonCustomMessageHeader
+	News struct {
+		Articles []CustomArticle `json:"articles"`
+	} `json:"news"`
+}
+
+// 新建图文消息
+//  NOTE: articles 的长度不能超过 CustomNewsArticleCountLimit
+func NewCustomNews(toUser string, articles []CustomArticle) *CustomNews {
+	var msg CustomNews
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeNews
+	msg.News.Articles = articles
+	return &msg
+}
+
+// 检查 CustomNews 是否有效，有效返回 nil，否则返回错误信息
+func (msg *CustomNews) CheckValid() (err error) {
+	n := len(msg.News.Articles)
+	if n <= 0 {
+		err = errors.New("图文消息里没有文章")
+		return
+	}
+	if n > CustomNewsArticleCountLimit {
+		err = fmt.Errorf("客服图文消息的文章个数不能超过 %d, 现在为 %d", CustomNewsArticleCountLimit, n)
+		return
+	}
+	return
+}
+
+// 图文消息(点击跳转到图文消息页面)
+type CustomMPNews struct {
+	CommonCustomMessageHeader
+	MPNews struct {
+		MediaId string `json:"media_id"`
+	} `json:"mpnews"`
+}
+
+// 新建图文消息
+//  NOTE: mediaId 为图文素材的 media_id, 通过 Client.AddNews 得到
+func NewCustomMPNews(toUser, mediaId string) *CustomMPNews {
+	var msg CustomMPNews
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeMPNews
+	msg.MPNews.MediaId = mediaId
+	return &msg
+}
+
+// 卡券消息
+type CustomWxCard struct {
+	CommonCustomMessageHeader
+	WxCard struct {
+		CardId string `json:"card_id"`
+	} `json:"wxcard"`
+}
+
+// 新建卡券消息
+//  NOTE: 只支持非自定义 Code 码和导入 Code 模式的卡券
+func NewCustomWxCard(toUser, cardId string) *CustomWxCard {
+	var msg CustomWxCard
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeWxCard
+	msg.WxCard.CardId = cardId
+	return &msg
+}
+
+// 菜单消息里的菜单项, 用户点击后会给公众号发送一条文本消息, 内容为 Content, 同时带上 bizmsgmenuid=Id
+type CustomMenuItem struct {
+	Id      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// 菜单消息
+type CustomMsgMenu struct {
+	CommonCustomMessageHeader
+	MsgMenu struct {
+		HeadContent string           `json:"head_content,omitempty"`
+		List        []CustomMenuItem `json:"list"`
+		TailContent string           `json:"tail_content,omitempty"`
+	} `json:"msgmenu"`
+}
+
+// 新建菜单消息
+//  headContent, tailContent 可以为 ""
+func NewCustomMsgMenu(toUser, headContent string, list []CustomMenuItem, tailContent string) *CustomMsgMenu {
+	var msg CustomMsgMenu
+	msg.ToUser = toUser
+	msg.MsgType = CustomMsgTypeMsgMenu
+	msg.MsgMenu.HeadContent = headContent
+	msg.MsgMenu.List = list
+	msg.MsgMenu.TailContent = tailContent
+	return &msg
+}
+
+// 检查 CustomMsgMenu 是否有效，有效返回 nil，否则返回错误信息
+func (msg *CustomMsgMenu) CheckValid() (err error) {
+	if len(msg.MsgMenu.List) <= 0 {
+		err = errors.New("菜单消息里没有菜单项")
+		return
+	}
+	for i := range msg.MsgMenu.List {
+		if msg.MsgMenu.List[i].Id == "" {
+			err = fmt.Errorf("菜单消息的第 %d 个菜单项没有 id", i+1)
+			return
+		}
+	}
+	return
+}
+
+// 对用户 openid 下发"正在输入"状态, 持续 15 秒或者到下一条客服消息发出为止.
+//  NOTE: 只能在用户发消息给公众号之后的 48 小时内调用, 适合 Handler 处理时间较长(比如异步回复)时使用.
+func (clt *Client) Typing(openid string) (err error) {
+	return clt.TypingContext(context.Background(), openid)
+}
+
+func (clt *Client) TypingContext(ctx context.Context, openid string) (err error) {
+	return clt.customTyping(ctx, openid, customTypingCommandTyping)
+}
+
+// 取消对用户 openid 的"正在输入"状态.
+func (clt *Client) CancelTyping(openid string) (err error) {
+	return clt.CancelTypingContext(context.Background(), openid)
+}
+
+func (clt *Client) CancelTypingContext(ctx context.Context, openid string) (err error) {
+	return clt.customTyping(ctx, openid, customTypingCommandCancel)
+}
+
+func (clt *Client) customTyping(ctx context.Context, openid, command string) (err error) {
+	request := struct {
+		ToUser  string `json:"touser"`
+		Command string `json:"command"`
+	}{
+		ToUser:  openid,
+		Command: command,
+	}
+
+	var result Error
+
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/message/custom/typing?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
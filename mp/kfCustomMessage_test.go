@@ -0,0 +1,57 @@
+package mp
+
+import (
+	"testing"
+
+	"github.com/skynology/wechat/util"
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestCustomMessage(t *testing.T) {
+	msg := NewCustomText("openid", "a<b")
+	msg.SetKfAccount("test1@test")
+	data, err := util.MarshalJSON(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"touser":"openid","msgtype":"text","customservice":{"kf_account":"test1@test"},"text":{"content":"a<b"}}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	api := wechattest.NewServer()
+	defer api.Close()
+	api.SetUser("openid", nil)
+	clt := NewClient(api.AppId, api.AppSecret)
+	clt.SetBaseURL(api.URL, "")
+
+	// 文章个数超过限制的在本地就返回错误
+	news := NewCustomNews("openid", []CustomArticle{{Title: "1"}, {Title: "2"}})
+	if err = clt.SendCustomMessage(news); err == nil {
+		t.Error("expected error for too many articles")
+	}
+	if n := len(api.CallsTo("/cgi-bin/message/custom/send")); n != 0 {
+		t.Errorf("custom send calls = %d, want 0", n)
+	}
+	news.News.Articles = news.News.Articles[:1]
+	if err = clt.SendCustomMessage(news); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = clt.Typing("openid"); err != nil {
+		t.Fatal(err)
+	}
+	if err = clt.CancelTyping("openid"); err != nil {
+		t.Fatal(err)
+	}
+	var req struct {
+		Command string `json:"command"`
+	}
+	calls := api.CallsTo("/cgi-bin/message/custom/typing")
+	if len(calls) != 2 {
+		t.Fatalf("typing calls = %d, want 2", len(calls))
+	}
+	if err = calls[1].Decode(&req); err != nil || req.Command != "CancelTyping" {
+		t.Errorf("command = %q, err = %v", req.Command, err)
+	}
+}
@@ -1,12 +1,27 @@
 package mp
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
+// 发送客服消息, msg 一般为 NewCustomText, NewCustomNews 等的返回值.
+//  NOTE: msg 实现了 CheckValid() error (比如 *CustomNews) 时先检查 msg 是否有效.
 func (clt *Client) SendCustomMessage(msg interface{}) (err error) {
+	return clt.SendCustomMessageContext(context.Background(), msg)
+}
+
+func (clt *Client) SendCustomMessageContext(ctx context.Context, msg interface{}) (err error) {
+	if v, ok := msg.(interface{ CheckValid() error }); ok {
+		if err = v.CheckValid(); err != nil {
+			return
+		}
+	}
+
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/message/custom/send?access_token="
-	if err = clt.PostJSON(incompleteURL, msg, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/message/custom/send?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
 		return
 	}
 
@@ -51,6 +66,10 @@ type GetRecordRequest struct {
 
 // 获取客服聊天记录
 func (clt *Client) GetRecord(request *GetRecordRequest) (recordList []Record, err error) {
+	return clt.GetRecordContext(context.Background(), request)
+}
+
+func (clt *Client) GetRecordContext(ctx context.Context, request *GetRecordRequest) (recordList []Record, err error) {
 	if request == nil {
 		err = errors.New("nil request")
 		return
@@ -67,8 +86,8 @@ func (clt *Client) GetRecord(request *GetRecordRequest) (recordList []Record, er
 		result.RecordList = make([]Record, 0, size)
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/customservice/msgrecord/getrecord?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/customservice/msgrecord/getrecord?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -132,7 +151,11 @@ func (iter *RecordIterator) NextPage() (records []Record, err error) {
 
 // 获取聊天记录遍历器.
 func (clt *Client) RecordIterator(request *GetRecordRequest) (iter *RecordIterator, err error) {
-	records, err := clt.GetRecord(request)
+	return clt.RecordIteratorContext(context.Background(), request)
+}
+
+func (clt *Client) RecordIteratorContext(ctx context.Context, request *GetRecordRequest) (iter *RecordIterator, err error) {
+	records, err := clt.GetRecordContext(ctx, request)
 	if err != nil {
 		return
 	}
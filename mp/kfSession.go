@@ -1,10 +1,16 @@
 package mp
 
+import "context"
+
 // 添加客服会话.
 //  account:    完整客服账号，格式为：账号前缀@公众号微信号，账号前缀最多10个字符，必须是英文或者数字字符。
 //  openid:     客户openid
 //  text:       附加信息，文本会展示在客服人员的多客服客户端
 func (clt *Client) CreateKfSession(account, openid, text string) (err error) {
+	return clt.CreateKfSessionContext(context.Background(), account, openid, text)
+}
+
+func (clt *Client) CreateKfSessionContext(ctx context.Context, account, openid, text string) (err error) {
 	request := struct {
 		Account string `json:"kf_account"`
 		OpenId  string `json:"openid"`
@@ -17,8 +23,8 @@ func (clt *Client) CreateKfSession(account, openid, text string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/customservice/kfsession/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -34,6 +40,10 @@ func (clt *Client) CreateKfSession(account, openid, text string) (err error) {
 //  openid:     客户openid
 //  text:       附加信息，文本会展示在客服人员的多客服客户端
 func (clt *Client) CloseKfSession(account, openid, text string) (err error) {
+	return clt.CloseKfSessionContext(context.Background(), account, openid, text)
+}
+
+func (clt *Client) CloseKfSessionContext(ctx context.Context, account, openid, text string) (err error) {
 	request := struct {
 		Account string `json:"kf_account"`
 		OpenId  string `json:"openid"`
@@ -46,8 +56,8 @@ func (clt *Client) CloseKfSession(account, openid, text string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/close?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/customservice/kfsession/close?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -68,14 +78,18 @@ type KFSessionInfo struct {
 // 获取客户的会话状态
 //  openid:     客户openid
 func (clt *Client) GetKfSession(openid string) (kfSessionInfo KFSessionInfo, err error) {
+	return clt.GetKfSessionContext(context.Background(), openid)
+}
+
+func (clt *Client) GetKfSessionContext(ctx context.Context, openid string) (kfSessionInfo KFSessionInfo, err error) {
 	var result struct {
 		Error
 		KFSessionInfo
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/getsession?openid=" + openid
+	incompleteURL := clt.apiBaseURL + "/customservice/kfsession/getsession?openid=" + openid
 	incompleteURL += "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -90,14 +104,18 @@ func (clt *Client) GetKfSession(openid string) (kfSessionInfo KFSessionInfo, err
 // 获取客服的会话列表
 //  openid:     客户openid
 func (clt *Client) KfSessionList(kfaccount string) (kfSessionList []KFSessionInfo, err error) {
+	return clt.KfSessionListContext(context.Background(), kfaccount)
+}
+
+func (clt *Client) KfSessionListContext(ctx context.Context, kfaccount string) (kfSessionList []KFSessionInfo, err error) {
 	var result struct {
 		Error
 		SessionList []KFSessionInfo `json:"sessionlist"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/getsessionlist?kf_account=" + kfaccount
+	incompleteURL := clt.apiBaseURL + "/customservice/kfsession/getsessionlist?kf_account=" + kfaccount
 	incompleteURL += "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -116,14 +134,18 @@ type KFSessionWait struct {
 
 // 获取未接入会话列表
 func (clt *Client) KfSessionWaitList() (kfSessionWaitList KFSessionWait, err error) {
+	return clt.KfSessionWaitListContext(context.Background())
+}
+
+func (clt *Client) KfSessionWaitListContext(ctx context.Context) (kfSessionWaitList KFSessionWait, err error) {
 	var result struct {
 		Error
 		Count       int             `json:"count"`        //未接入会话数量
 		SessionList []KFSessionInfo `json:"waitcaselist"` // 未接入会话列表，最多返回100条数据
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/customservice/kfsession/getwaitcase?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/customservice/kfsession/getwaitcase?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -1,5 +1,7 @@
 package mp
 
+import "context"
+
 // 发送对象类型
 type MassMessageToType string
 
@@ -108,13 +110,17 @@ func NewMassNews(groupId int64, mediaId string) *MassNews {
 }
 
 func (clt *Client) SendMassMassage(filterType MassMessageToType, msg interface{}) (msgid int64, err error) {
+	return clt.SendMassMassageContext(context.Background(), filterType, msg)
+}
+
+func (clt *Client) SendMassMassageContext(ctx context.Context, filterType MassMessageToType, msg interface{}) (msgid int64, err error) {
 	var result struct {
 		Error
 		Type  string `json:"type"`
 		MsgId int64  `json:"msg_id"`
 	}
 
-	urlPrefix := "https://api.weixin.qq.com/cgi-bin/message/mass/"
+	urlPrefix := clt.apiBaseURL + "/cgi-bin/message/mass/"
 	incompleteURL := urlPrefix + "sendall?access_token="
 	if filterType == MassMessageToPreview {
 		incompleteURL = urlPrefix + "preview?access_token="
@@ -122,7 +128,7 @@ func (clt *Client) SendMassMassage(filterType MassMessageToType, msg interface{}
 		incompleteURL = urlPrefix + "send?access_token="
 	}
 
-	if err = clt.PostJSON(incompleteURL, msg, &result); err != nil {
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
 		return
 	}
 
@@ -135,6 +141,10 @@ func (clt *Client) SendMassMassage(filterType MassMessageToType, msg interface{}
 }
 
 func (clt *Client) DeleteMassMassage(msgId int64) (err error) {
+	return clt.DeleteMassMassageContext(context.Background(), msgId)
+}
+
+func (clt *Client) DeleteMassMassageContext(ctx context.Context, msgId int64) (err error) {
 	var result struct {
 		Error
 	}
@@ -144,8 +154,8 @@ func (clt *Client) DeleteMassMassage(msgId int64) (err error) {
 		MsgId: msgId,
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/message/mass/delete?access_token="
-	if err = clt.PostJSON(incompleteURL, msg, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/message/mass/delete?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
 		return
 	}
 	if result.ErrCode != ErrCodeOK {
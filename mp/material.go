@@ -1,19 +1,25 @@
 package mp
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+
+	"github.com/skynology/wechat/util"
 )
 
 type News []Article
 
 // 删除永久素材.
 func (clt *Client) DeleteMaterial(mediaId string) (err error) {
+	return clt.DeleteMaterialContext(context.Background(), mediaId)
+}
+
+func (clt *Client) DeleteMaterialContext(ctx context.Context, mediaId string) (err error) {
 	var request = struct {
 		MediaId string `json:"media_id"`
 	}{
@@ -22,8 +28,8 @@ func (clt *Client) DeleteMaterial(mediaId string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/del_material?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/del_material?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -44,13 +50,17 @@ type MaterialCountInfo struct {
 
 // 获取素材总数.
 func (clt *Client) GetMaterialCount() (info *MaterialCountInfo, err error) {
+	return clt.GetMaterialCountContext(context.Background())
+}
+
+func (clt *Client) GetMaterialCountContext(ctx context.Context) (info *MaterialCountInfo, err error) {
 	var result struct {
 		Error
 		MaterialCountInfo
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/get_materialcount?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/get_materialcount?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -78,6 +88,10 @@ type MaterialInfo struct {
 //  ItemCount:    本次调用获取的素材的数量
 //  Items:        本次调用获取的素材
 func (clt *Client) BatchGetMaterial(materialType string, offset, count int) (TotalCount, ItemCount int, Items []MaterialInfo, err error) {
+	return clt.BatchGetMaterialContext(context.Background(), materialType, offset, count)
+}
+
+func (clt *Client) BatchGetMaterialContext(ctx context.Context, materialType string, offset, count int) (TotalCount, ItemCount int, Items []MaterialInfo, err error) {
 	var request = struct {
 		MaterialType string `json:"type"`
 		Offset       int    `json:"offset"`
@@ -95,8 +109,8 @@ func (clt *Client) BatchGetMaterial(materialType string, offset, count int) (Tot
 		Items      []MaterialInfo `json:"item"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/batchget_material?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/batchget_material?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -112,6 +126,10 @@ func (clt *Client) BatchGetMaterial(materialType string, offset, count int) (Tot
 
 // 新增永久图文素材.
 func (clt *Client) AddMeterialNews(news News) (mediaId string, err error) {
+	return clt.AddMeterialNewsContext(context.Background(), news)
+}
+
+func (clt *Client) AddMeterialNewsContext(ctx context.Context, news News) (mediaId string, err error) {
 	if len(news) == 0 {
 		err = errors.New("图文素材是空的")
 		return
@@ -132,8 +150,8 @@ func (clt *Client) AddMeterialNews(news News) (mediaId string, err error) {
 		MediaId string `json:"media_id"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/add_news?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/add_news?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -148,6 +166,10 @@ func (clt *Client) AddMeterialNews(news News) (mediaId string, err error) {
 // 修改永久图文素材.
 //  再次fuck微信開發組, 這個api是猜的!
 func (clt *Client) UpdateMeterialNews(mediaId string, index int, news News) (err error) {
+	return clt.UpdateMeterialNewsContext(context.Background(), mediaId, index, news)
+}
+
+func (clt *Client) UpdateMeterialNewsContext(ctx context.Context, mediaId string, index int, news News) (err error) {
 	var request = struct {
 		MediaId  string `json:"media_id"`
 		Index    int    `json:"index"`
@@ -160,8 +182,8 @@ func (clt *Client) UpdateMeterialNews(mediaId string, index int, news News) (err
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/update_news?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/update_news?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -174,6 +196,10 @@ func (clt *Client) UpdateMeterialNews(mediaId string, index int, news News) (err
 
 // 获取永久图文素材.
 func (clt *Client) GetMeterialNews(mediaId string) (news News, err error) {
+	return clt.GetMeterialNewsContext(context.Background(), mediaId)
+}
+
+func (clt *Client) GetMeterialNewsContext(ctx context.Context, mediaId string) (news News, err error) {
 	var request = struct {
 		MediaId string `json:"media_id"`
 	}{
@@ -185,8 +211,8 @@ func (clt *Client) GetMeterialNews(mediaId string) (news News, err error) {
 		Articles []Article `json:"news_item"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/get_material?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/get_material?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -207,6 +233,10 @@ type Video struct {
 
 // 获取永久图文素材.
 func (clt *Client) GetMeterialVideo(mediaId string) (video Video, err error) {
+	return clt.GetMeterialVideoContext(context.Background(), mediaId)
+}
+
+func (clt *Client) GetMeterialVideoContext(ctx context.Context, mediaId string) (video Video, err error) {
 	var request = struct {
 		MediaId string `json:"media_id"`
 	}{
@@ -218,8 +248,8 @@ func (clt *Client) GetMeterialVideo(mediaId string) (video Video, err error) {
 		Video
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/get_material?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/get_material?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -241,33 +271,49 @@ const (
 
 // 上传多媒体图片
 func (clt *Client) UploadImage(filepath string) (mediaId string, err error) {
-	return clt.uploadMaterial(MaterialTypeImage, filepath)
+	return clt.UploadImageContext(context.Background(), filepath)
+}
+
+func (clt *Client) UploadImageContext(ctx context.Context, filepath string) (mediaId string, err error) {
+	return clt.uploadMaterial(ctx, MaterialTypeImage, filepath)
 }
 
 // 上传多媒体缩略图
 func (clt *Client) UploadThumb(filepath string) (mediaId string, err error) {
-	return clt.uploadMaterial(MaterialTypeThumb, filepath)
+	return clt.UploadThumbContext(context.Background(), filepath)
+}
+
+func (clt *Client) UploadThumbContext(ctx context.Context, filepath string) (mediaId string, err error) {
+	return clt.uploadMaterial(ctx, MaterialTypeThumb, filepath)
 }
 
 // 上传多媒体语音
 func (clt *Client) UploadVoice(filepath string) (mediaId string, err error) {
-	return clt.uploadMaterial(MaterialTypeVoice, filepath)
+	return clt.UploadVoiceContext(context.Background(), filepath)
+}
+
+func (clt *Client) UploadVoiceContext(ctx context.Context, filepath string) (mediaId string, err error) {
+	return clt.uploadMaterial(ctx, MaterialTypeVoice, filepath)
 }
 
 // 上传多媒体
-func (clt *Client) uploadMaterial(materialType, _filepath string) (mediaId string, err error) {
+func (clt *Client) uploadMaterial(ctx context.Context, materialType, _filepath string) (mediaId string, err error) {
 	file, err := os.Open(_filepath)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
-	return clt.uploadMaterialFromReader(materialType, filepath.Base(_filepath), file)
+	return clt.uploadMaterialFromReader(ctx, materialType, filepath.Base(_filepath), file)
 }
 
 // 上传多媒体图片
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadMeterialImageFromReader(filename string, reader io.Reader) (mediaId string, err error) {
+	return clt.UploadMeterialImageFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadMeterialImageFromReaderContext(ctx context.Context, filename string, reader io.Reader) (mediaId string, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -276,12 +322,16 @@ func (clt *Client) UploadMeterialImageFromReader(filename string, reader io.Read
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadMaterialFromReader(MaterialTypeImage, filename, reader)
+	return clt.uploadMaterialFromReader(ctx, MaterialTypeImage, filename, reader)
 }
 
 // 上传多媒体缩略图
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadMeteriralThumbFromReader(filename string, reader io.Reader) (mediaId string, err error) {
+	return clt.UploadMeteriralThumbFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadMeteriralThumbFromReaderContext(ctx context.Context, filename string, reader io.Reader) (mediaId string, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -290,12 +340,16 @@ func (clt *Client) UploadMeteriralThumbFromReader(filename string, reader io.Rea
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadMaterialFromReader(MaterialTypeThumb, filename, reader)
+	return clt.uploadMaterialFromReader(ctx, MaterialTypeThumb, filename, reader)
 }
 
 // 上传多媒体语音
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadMeterialVoiceFromReader(filename string, reader io.Reader) (mediaId string, err error) {
+	return clt.UploadMeterialVoiceFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadMeterialVoiceFromReaderContext(ctx context.Context, filename string, reader io.Reader) (mediaId string, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -304,12 +358,16 @@ func (clt *Client) UploadMeterialVoiceFromReader(filename string, reader io.Read
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadMaterialFromReader(MaterialTypeVoice, filename, reader)
+	return clt.uploadMaterialFromReader(ctx, MaterialTypeVoice, filename, reader)
 }
 
 // 上传多媒体缩视频
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadMeterialVideoFromReader(filename string, reader io.Reader, title, introduction string) (mediaId string, err error) {
+	return clt.UploadMeterialVideoFromReaderContext(context.Background(), filename, reader, title, introduction)
+}
+
+func (clt *Client) UploadMeterialVideoFromReaderContext(ctx context.Context, filename string, reader io.Reader, title, introduction string) (mediaId string, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -318,17 +376,17 @@ func (clt *Client) UploadMeterialVideoFromReader(filename string, reader io.Read
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadVideoFromReader(filename, reader, title, introduction)
+	return clt.uploadVideoFromReader(ctx, filename, reader, title, introduction)
 }
-func (clt *Client) uploadMaterialFromReader(materialType, filename string, reader io.Reader) (mediaId string, err error) {
+func (clt *Client) uploadMaterialFromReader(ctx context.Context, materialType, filename string, reader io.Reader) (mediaId string, err error) {
 	var result struct {
 		Error
 		MediaId string `json:"media_id"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/add_material?type=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/add_material?type=" +
 		url.QueryEscape(materialType) + "&access_token="
-	if err = clt.UploadFromReader(incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
+	if err = clt.UploadFromReaderContext(ctx, incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
 		return
 	}
 
@@ -339,7 +397,7 @@ func (clt *Client) uploadMaterialFromReader(materialType, filename string, reade
 	mediaId = result.MediaId
 	return
 }
-func (clt *Client) uploadVideoFromReader(filename string, reader io.Reader,
+func (clt *Client) uploadVideoFromReader(ctx context.Context, filename string, reader io.Reader,
 	title, introduction string) (mediaId string, err error) {
 
 	var desc = struct {
@@ -350,7 +408,7 @@ func (clt *Client) uploadVideoFromReader(filename string, reader io.Reader,
 		Introduction: introduction,
 	}
 
-	descBytes, err := json.Marshal(&desc)
+	descBytes, err := util.MarshalJSON(&desc)
 	if err != nil {
 		return
 	}
@@ -360,8 +418,8 @@ func (clt *Client) uploadVideoFromReader(filename string, reader io.Reader,
 		MediaId string `json:"media_id"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/material/add_material?type=video&access_token="
-	if err = clt.UploadFromReader(incompleteURL, "media", filename, reader, "description", descBytes, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/material/add_material?type=video&access_token="
+	if err = clt.UploadFromReaderContext(ctx, incompleteURL, "media", filename, reader, "description", descBytes, &result); err != nil {
 		return
 	}
 
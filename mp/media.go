@@ -1,6 +1,7 @@
 package mp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -50,11 +51,15 @@ func (article *Article) SetShowCoverPic(b bool) {
 
 // 获取临时素材下载地址
 func (clt *Client) GetMediaDownloadURL(mediaId string) string {
-	token, err := clt.Token()
+	return clt.GetMediaDownloadURLContext(context.Background(), mediaId)
+}
+
+func (clt *Client) GetMediaDownloadURLContext(ctx context.Context, mediaId string) string {
+	token, err := clt.TokenContext(ctx)
 	if err != nil {
 		return ""
 	}
-	finalURL := "http://file.api.weixin.qq.com/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
+	finalURL := clt.fileBaseURL + "/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
 		"&access_token=" + url.QueryEscape(token)
 
 	return finalURL
@@ -62,36 +67,44 @@ func (clt *Client) GetMediaDownloadURL(mediaId string) string {
 
 // 下载多媒体到文件.
 func (clt *Client) DownloadMedia(mediaId, filepath string) (err error) {
+	return clt.DownloadMediaContext(context.Background(), mediaId, filepath)
+}
+
+func (clt *Client) DownloadMediaContext(ctx context.Context, mediaId, filepath string) (err error) {
 	file, err := os.Create(filepath)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
-	return clt.downloadMediaToWriter(mediaId, file)
+	return clt.downloadMediaToWriter(ctx, mediaId, file)
 }
 
 // 下载多媒体到 io.Writer.
 func (clt *Client) DownloadMediaToWriter(mediaId string, writer io.Writer) error {
+	return clt.DownloadMediaToWriterContext(context.Background(), mediaId, writer)
+}
+
+func (clt *Client) DownloadMediaToWriterContext(ctx context.Context, mediaId string, writer io.Writer) error {
 	if writer == nil {
 		return errors.New("nil writer")
 	}
-	return clt.downloadMediaToWriter(mediaId, writer)
+	return clt.downloadMediaToWriter(ctx, mediaId, writer)
 }
 
 // 下载多媒体到 io.Writer.
-func (clt *Client) downloadMediaToWriter(mediaId string, writer io.Writer) (err error) {
-	token, err := clt.Token()
+func (clt *Client) downloadMediaToWriter(ctx context.Context, mediaId string, writer io.Writer) (err error) {
+	token, err := clt.token(ctx)
 	if err != nil {
 		return
 	}
 
 	hasRetried := false
 RETRY:
-	finalURL := "http://file.api.weixin.qq.com/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
-		"&access_token=" + url.QueryEscape(token)
+	finalURL := clt.fileBaseURL + "/cgi-bin/media/get?media_id=" + url.QueryEscape(mediaId) +
+		"&access_token=" + url.QueryEscape(token.Value)
 
-	httpResp, err := clt.httpClient.Get(finalURL)
+	httpResp, err := clt.httpGet(ctx, finalURL)
 	if err != nil {
 		return
 	}
@@ -121,7 +134,7 @@ RETRY:
 		if !hasRetried {
 			hasRetried = true
 
-			if token, err = clt.RefreshToken(); err != nil {
+			if token, err = clt.refreshToken(ctx, token); err != nil {
 				return
 			}
 			goto RETRY
@@ -136,6 +149,10 @@ RETRY:
 // 根据上传的缩略图媒体创建图文消息素材.
 //  articles 的长度不能大于 NewsArticleCountLimit.
 func (clt *Client) CreateNews(articles []Article) (info *MediaInfo, err error) {
+	return clt.CreateNewsContext(context.Background(), articles)
+}
+
+func (clt *Client) CreateNewsContext(ctx context.Context, articles []Article) (info *MediaInfo, err error) {
 	if len(articles) == 0 {
 		err = errors.New("图文消息是空的")
 		return
@@ -156,8 +173,8 @@ func (clt *Client) CreateNews(articles []Article) (info *MediaInfo, err error) {
 		MediaInfo
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/media/uploadnews?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/media/uploadnews?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -172,6 +189,10 @@ func (clt *Client) CreateNews(articles []Article) (info *MediaInfo, err error) {
 // 根据上传的视频文件 media_id 创建视频媒体, 群发视频消息应该用这个函数得到的 media_id.
 //  NOTE: title, description 可以为空.
 func (clt *Client) CreateVideo(mediaId, title, description string) (info *MediaInfo, err error) {
+	return clt.CreateVideoContext(context.Background(), mediaId, title, description)
+}
+
+func (clt *Client) CreateVideoContext(ctx context.Context, mediaId, title, description string) (info *MediaInfo, err error) {
 	var request = struct {
 		MediaId     string `json:"media_id"`
 		Title       string `json:"title,omitempty"`
@@ -187,8 +208,8 @@ func (clt *Client) CreateVideo(mediaId, title, description string) (info *MediaI
 		MediaInfo
 	}
 
-	incompleteURL := "https://file.api.weixin.qq.com/cgi-bin/media/uploadvideo?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.fileBaseURL + "/cgi-bin/media/uploadvideo?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -203,6 +224,10 @@ func (clt *Client) CreateVideo(mediaId, title, description string) (info *MediaI
 // 上传多媒体图片
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadImageFromReader(filename string, reader io.Reader) (info *MediaInfo, err error) {
+	return clt.UploadImageFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadImageFromReaderContext(ctx context.Context, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -211,12 +236,16 @@ func (clt *Client) UploadImageFromReader(filename string, reader io.Reader) (inf
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadMediaFromReader(MediaTypeImage, filename, reader)
+	return clt.uploadMediaFromReader(ctx, MediaTypeImage, filename, reader)
 }
 
 // 上传多媒体语音
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadVoiceFromReader(filename string, reader io.Reader) (info *MediaInfo, err error) {
+	return clt.UploadVoiceFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadVoiceFromReaderContext(ctx context.Context, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -225,12 +254,16 @@ func (clt *Client) UploadVoiceFromReader(filename string, reader io.Reader) (inf
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadMediaFromReader(MediaTypeVoice, filename, reader)
+	return clt.uploadMediaFromReader(ctx, MediaTypeVoice, filename, reader)
 }
 
 // 上传多媒体视频
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadVideoFromReader(filename string, reader io.Reader) (info *MediaInfo, err error) {
+	return clt.UploadVideoFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadVideoFromReaderContext(ctx context.Context, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -239,17 +272,17 @@ func (clt *Client) UploadVideoFromReader(filename string, reader io.Reader) (inf
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadMediaFromReader(MediaTypeVideo, filename, reader)
+	return clt.uploadMediaFromReader(ctx, MediaTypeVideo, filename, reader)
 }
-func (clt *Client) uploadMediaFromReader(mediaType, filename string, reader io.Reader) (info *MediaInfo, err error) {
+func (clt *Client) uploadMediaFromReader(ctx context.Context, mediaType, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	var result struct {
 		Error
 		MediaInfo
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/media/upload?type=" +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/media/upload?type=" +
 		url.QueryEscape(mediaType) + "&access_token="
-	if err = clt.UploadFromReader(incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
+	if err = clt.UploadFromReaderContext(ctx, incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
 		return
 	}
 
@@ -264,6 +297,10 @@ func (clt *Client) uploadMediaFromReader(mediaType, filename string, reader io.R
 // 上传多媒体缩略图
 //  NOTE: 参数 filename 不是文件路径, 是指定 multipart/form-data 里面文件名称
 func (clt *Client) UploadThumbFromReader(filename string, reader io.Reader) (info *MediaInfo, err error) {
+	return clt.UploadThumbFromReaderContext(context.Background(), filename, reader)
+}
+
+func (clt *Client) UploadThumbFromReaderContext(ctx context.Context, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	if filename == "" {
 		err = errors.New("empty filename")
 		return
@@ -272,10 +309,10 @@ func (clt *Client) UploadThumbFromReader(filename string, reader io.Reader) (inf
 		err = errors.New("nil reader")
 		return
 	}
-	return clt.uploadThumbFromReader(filename, reader)
+	return clt.uploadThumbFromReader(ctx, filename, reader)
 }
 
-func (clt *Client) uploadThumbFromReader(filename string, reader io.Reader) (info *MediaInfo, err error) {
+func (clt *Client) uploadThumbFromReader(ctx context.Context, filename string, reader io.Reader) (info *MediaInfo, err error) {
 	var result struct {
 		Error
 		MediaType string `json:"type"`
@@ -283,8 +320,8 @@ func (clt *Client) uploadThumbFromReader(filename string, reader io.Reader) (inf
 		CreatedAt int64  `json:"created_at"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/media/upload?type=thumb&access_token="
-	if err = clt.UploadFromReader(incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/media/upload?type=thumb&access_token="
+	if err = clt.UploadFromReaderContext(ctx, incompleteURL, "media", filename, reader, "", nil, &result); err != nil {
 		return
 	}
 
@@ -5,6 +5,8 @@
 
 package mp
 
+import "context"
+
 const (
 	MenuButtonCountLimit    = 3 // 一级菜单最多包含 3 个按钮
 	SubMenuButtonCountLimit = 5 // 二级菜单最多包含 5 个按钮
@@ -139,10 +141,14 @@ func (btn *Button) SetAsLocationSelectButton(name, key string) {
 
 // 创建自定义菜单.
 func (clt *Client) CreateMenu(menu Menu) (err error) {
+	return clt.CreateMenuContext(context.Background(), menu)
+}
+
+func (clt *Client) CreateMenuContext(ctx context.Context, menu Menu) (err error) {
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/menu/create?access_token="
-	if err = clt.PostJSON(incompleteURL, menu, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/menu/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, menu, &result); err != nil {
 		return
 	}
 
@@ -155,10 +161,14 @@ func (clt *Client) CreateMenu(menu Menu) (err error) {
 
 // 删除自定义菜单
 func (clt *Client) DeleteMenu() (err error) {
+	return clt.DeleteMenuContext(context.Background())
+}
+
+func (clt *Client) DeleteMenuContext(ctx context.Context) (err error) {
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/menu/delete?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/menu/delete?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -171,13 +181,17 @@ func (clt *Client) DeleteMenu() (err error) {
 
 // 获取自定义菜单
 func (clt *Client) GetMenu() (menu Menu, err error) {
+	return clt.GetMenuContext(context.Background())
+}
+
+func (clt *Client) GetMenuContext(ctx context.Context) (menu Menu, err error) {
 	var result struct {
 		Error
 		Menu Menu `json:"menu"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/menu/get?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/menu/get?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -1,6 +1,7 @@
 package mp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -83,6 +84,10 @@ func (info *OauthUserInfo) HeadImageSize() (size int, err error) {
 //  1. Client 需要指定 OAuth2Config, OAuth2Token
 //  2. lang 可能的取值是 zh_CN, zh_TW, en, 如果留空 "" 则默认为 zh_CN.
 func (clt *Client) OauthUserInfo(openId string, accessToken string, lang string) (info *UserInfo, err error) {
+	return clt.OauthUserInfoContext(context.Background(), openId, accessToken, lang)
+}
+
+func (clt *Client) OauthUserInfoContext(ctx context.Context, openId string, accessToken string, lang string) (info *UserInfo, err error) {
 	if lang == "" {
 		lang = Language_zh_CN
 	}
@@ -91,11 +96,11 @@ func (clt *Client) OauthUserInfo(openId string, accessToken string, lang string)
 		return
 	}
 
-	_url := "https://api.weixin.qq.com/sns/userinfo" +
+	_url := clt.apiBaseURL + "/sns/userinfo" +
 		"?access_token=" + url.QueryEscape(accessToken) +
 		"&openid=" + url.QueryEscape(openId) +
 		"&lang=" + url.QueryEscape(lang)
-	httpResp, err := clt.httpClient.Get(_url)
+	httpResp, err := clt.httpGet(ctx, _url)
 	if err != nil {
 		return
 	}
@@ -169,25 +174,33 @@ func (token *OAuth2Token) accessTokenExpired() bool {
 //  2. 如果指定了 OAuth2Token, 则会更新这个 OAuth2Token, 同时返回的也是指定的 OAuth2Token;
 //     否则会重新分配一个 OAuth2Token.
 func (clt *Client) GetOauthToken(code string) (token *OAuth2Token, err error) {
+	return clt.GetOauthTokenContext(context.Background(), code)
+}
+
+func (clt *Client) GetOauthTokenContext(ctx context.Context, code string) (token *OAuth2Token, err error) {
 	token = new(OAuth2Token)
 
-	_url := "https://api.weixin.qq.com/sns/oauth2/access_token" +
+	_url := clt.apiBaseURL + "/sns/oauth2/access_token" +
 		"?appid=" + url.QueryEscape(clt.appId) +
 		"&secret=" + url.QueryEscape(clt.appSecret) +
 		"&code=" + url.QueryEscape(code) +
 		"&grant_type=authorization_code"
-	token, err = clt.updateOauthToken(_url)
+	token, err = clt.updateOauthToken(ctx, _url)
 	return
 }
 
 // 刷新access_token（如果需要）.
 //  NOTE: Client 需要指定 OAuth2Config, OAuth2Token
 func (clt *Client) OauthTokenRefresh(refreshToken string) (token *OAuth2Token, err error) {
+	return clt.OauthTokenRefreshContext(context.Background(), refreshToken)
+}
+
+func (clt *Client) OauthTokenRefreshContext(ctx context.Context, refreshToken string) (token *OAuth2Token, err error) {
 
-	_url := "https://api.weixin.qq.com/sns/oauth2/refresh_token" +
+	_url := clt.apiBaseURL + "/sns/oauth2/refresh_token" +
 		"?appid=" + url.QueryEscape(clt.appId) +
 		"&grant_type=refresh_token&refresh_token=" + url.QueryEscape(refreshToken)
-	token, err = clt.updateOauthToken(_url)
+	token, err = clt.updateOauthToken(ctx, _url)
 	return
 }
 
@@ -196,10 +209,14 @@ func (clt *Client) OauthTokenRefresh(refreshToken string) (token *OAuth2Token, e
 //  1. Client 需要指定 OAuth2Token
 //  2. 先判断 err 然后再判断 valid
 func (clt *Client) CheckOauthAccessTokenValid(accessToken string, openId string) (valid bool, err error) {
+	return clt.CheckOauthAccessTokenValidContext(context.Background(), accessToken, openId)
+}
+
+func (clt *Client) CheckOauthAccessTokenValidContext(ctx context.Context, accessToken string, openId string) (valid bool, err error) {
 
-	_url := "https://api.weixin.qq.com/sns/auth?access_token=" + url.QueryEscape(accessToken) +
+	_url := clt.apiBaseURL + "/sns/auth?access_token=" + url.QueryEscape(accessToken) +
 		"&openid=" + url.QueryEscape(openId)
-	httpResp, err := clt.httpClient.Get(_url)
+	httpResp, err := clt.httpGet(ctx, _url)
 	if err != nil {
 		return
 	}
@@ -229,10 +246,10 @@ func (clt *Client) CheckOauthAccessTokenValid(accessToken string, openId string)
 }
 
 // 从服务器获取新的 token 更新 tk
-func (clt *Client) updateOauthToken(url string) (tk *OAuth2Token, err error) {
+func (clt *Client) updateOauthToken(ctx context.Context, url string) (tk *OAuth2Token, err error) {
 	tk = new(OAuth2Token)
 
-	httpResp, err := clt.httpClient.Get(url)
+	httpResp, err := clt.httpGet(ctx, url)
 	if err != nil {
 		return
 	}
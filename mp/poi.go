@@ -6,6 +6,7 @@
 package mp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -35,6 +36,10 @@ type PoiAddParameters struct {
 
 // 创建门店.
 func (clt *Client) PoiAdd(para *PoiAddParameters) (err error) {
+	return clt.PoiAddContext(context.Background(), para)
+}
+
+func (clt *Client) PoiAddContext(ctx context.Context, para *PoiAddParameters) (err error) {
 	if para == nil {
 		return errors.New("nil PoiAddParameters")
 	}
@@ -47,8 +52,8 @@ func (clt *Client) PoiAdd(para *PoiAddParameters) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/poi/addpoi?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/poi/addpoi?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -74,6 +79,10 @@ type PoiBrief struct {
 //  begin: 开始位置，0 即为从第一条开始查询
 //  limit: 返回数据条数，最大允许50，默认为20
 func (clt *Client) PoiList(begin, limit int) (list []PoiBrief, totalCount int, err error) {
+	return clt.PoiListContext(context.Background(), begin, limit)
+}
+
+func (clt *Client) PoiListContext(ctx context.Context, begin, limit int) (list []PoiBrief, totalCount int, err error) {
 	if begin < 0 {
 		err = fmt.Errorf("invalid begin: %d", begin)
 		return
@@ -97,8 +106,8 @@ func (clt *Client) PoiList(begin, limit int) (list []PoiBrief, totalCount int, e
 		TotalCount int        `json:"total_count"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/poi/getpoilist?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/poi/getpoilist?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -140,6 +149,10 @@ type Poi struct {
 
 // 查询门店信息.
 func (clt *Client) PoiGet(poiId string) (poi *Poi, err error) {
+	return clt.PoiGetContext(context.Background(), poiId)
+}
+
+func (clt *Client) PoiGetContext(ctx context.Context, poiId string) (poi *Poi, err error) {
 	var request = struct {
 		PoiId string `json:"poi_id"`
 	}{
@@ -151,8 +164,8 @@ func (clt *Client) PoiGet(poiId string) (poi *Poi, err error) {
 		Poi `json:"business"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/poi/getpoi?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/poi/getpoi?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -185,6 +198,10 @@ type PoiUpdateParameters struct {
 //  商户可以通过该接口，修改门店的服务信息，包括：图片列表、营业时间、推荐、特色服务、简
 //  介、人均价格、电话7 个字段。目前基础字段包括（名称、坐标、地址等不可修改）
 func (clt *Client) PoiUpdate(para *PoiUpdateParameters) (err error) {
+	return clt.PoiUpdateContext(context.Background(), para)
+}
+
+func (clt *Client) PoiUpdateContext(ctx context.Context, para *PoiUpdateParameters) (err error) {
 	if para == nil {
 		return errors.New("nil PoiUpdateParameters")
 	}
@@ -197,8 +214,8 @@ func (clt *Client) PoiUpdate(para *PoiUpdateParameters) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/poi/updatepoi?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/poi/updatepoi?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -211,6 +228,10 @@ func (clt *Client) PoiUpdate(para *PoiUpdateParameters) (err error) {
 
 // 删除门店.
 func (clt *Client) PoiDelete(poiId string) (err error) {
+	return clt.PoiDeleteContext(context.Background(), poiId)
+}
+
+func (clt *Client) PoiDeleteContext(ctx context.Context, poiId string) (err error) {
 	var request = struct {
 		PoiId string `json:"poi_id"`
 	}{
@@ -219,8 +240,8 @@ func (clt *Client) PoiDelete(poiId string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/poi/delpoi?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/poi/delpoi?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -0,0 +1,275 @@
+package mp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+const qrScenePrefix = "qrscene_" // 未关注用户扫描带参数二维码关注时 EventKey 的前缀
+
+// 匹配事件的 EventKey, 比如 CLICK 事件的菜单 key, 扫描带参数二维码事件的场景值.
+type KeyMatcher func(key string) bool
+
+// 完全匹配 key.
+func Key(key string) KeyMatcher {
+	return func(s string) bool { return s == key }
+}
+
+// 匹配以 prefix 开头的 key.
+func KeyPrefix(prefix string) KeyMatcher {
+	return func(s string) bool { return strings.HasPrefix(s, prefix) }
+}
+
+// 匹配满足正则表达式 re 的 key.
+func KeyRegexp(re *regexp.Regexp) KeyMatcher {
+	return re.MatchString
+}
+
+// 在 Handler 前后插入逻辑, 比如日志, 鉴权, 恢复 panic.
+type Middleware func(next Handler) Handler
+
+// 消息(事件)路由, 实现了 Handler, 可以直接作为 Server 的 Handler:
+//
+//    router := mp.NewRouter()
+//    router.OnText(func(ctx context.Context, req *mp.Request, text *mp.ReqText) (interface{}, error) {
+//        return mp.NewResText(text.FromUserName, text.ToUserName, text.CreateTime, text.Content), nil
+//    })
+//    router.OnClick(mp.KeyPrefix("V1001_"), handleClick)
+//    router.OnSubscribeByScan(mp.KeyRegexp(regexp.MustCompile(`^\d+$`)), handleScene)
+//    srv := mp.NewServer(appId, token, router)
+//
+//  NOTE:
+//  1. 事件先按照 Event 和 EventKey 匹配(按注册的顺序, 先注册的优先), 然后按照 Event 匹配,
+//     普通消息按照 MsgType 匹配, 都没有匹配上时调用 Fallback;
+//  2. 扫描带参数二维码关注的 subscribe 事件, EventKey 去掉 "qrscene_" 前缀之后再匹配,
+//     所以同一个 KeyMatcher 可以同时用于 OnSubscribeByScan 和 OnScan;
+//  3. Router 不是并发安全的, 所有的注册应该在 Server 开始服务之前完成.
+type Router struct {
+	messageHandlers map[string]Handler    // MsgType -> Handler
+	eventHandlers   map[string]Handler    // Event -> Handler
+	keyRoutes       map[string][]keyRoute // Event -> EventKey 匹配的 Handler 列表
+	fallback        Handler
+	middlewares     []Middleware
+}
+
+type keyRoute struct {
+	match   KeyMatcher
+	handler Handler
+}
+
+func NewRouter() *Router {
+	return &Router{
+		messageHandlers: make(map[string]Handler),
+		eventHandlers:   make(map[string]Handler),
+		keyRoutes:       make(map[string][]keyRoute),
+	}
+}
+
+// 处理 MsgType 为 msgType 的消息, 比如 MsgTypeText.
+func (r *Router) HandleMessage(msgType string, handler Handler) {
+	r.messageHandlers[msgType] = handler
+}
+
+// 处理 Event 为 eventType 的事件, 比如 EventTypeClick.
+func (r *Router) HandleEvent(eventType string, handler Handler) {
+	r.eventHandlers[eventType] = handler
+}
+
+// 处理 Event 为 eventType 并且 EventKey 满足 match 的事件.
+func (r *Router) HandleEventKey(eventType string, match KeyMatcher, handler Handler) {
+	r.keyRoutes[eventType] = append(r.keyRoutes[eventType], keyRoute{match: match, handler: handler})
+}
+
+// 设置没有匹配上任何 Handler 时调用的 Handler, 默认不回复消息.
+func (r *Router) SetFallback(handler Handler) {
+	r.fallback = handler
+}
+
+// 添加中间件, 先添加的在外层.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+func (r *Router) ServeMessage(ctx context.Context, req *Request) (reply interface{}, err error) {
+	var handler Handler = HandlerFunc(r.route)
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	return handler.ServeMessage(ctx, req)
+}
+
+func (r *Router) route(ctx context.Context, req *Request) (reply interface{}, err error) {
+	if handler := r.match(req.MixedMessage); handler != nil {
+		return handler.ServeMessage(ctx, req)
+	}
+	if r.fallback != nil {
+		return r.fallback.ServeMessage(ctx, req)
+	}
+	return
+}
+
+func (r *Router) match(msg *MixedMessage) Handler {
+	if msg.MsgType != MsgTypeEvent {
+		return r.messageHandlers[msg.MsgType]
+	}
+
+	if key, ok := eventKey(msg); ok {
+		for _, route := range r.keyRoutes[msg.Event] {
+			if route.match == nil || route.match(key) {
+				return route.handler
+			}
+		}
+	}
+	if handler := r.eventHandlers[msg.Event]; handler != nil {
+		return handler
+	}
+	return r.messageHandlers[MsgTypeEvent]
+}
+
+// 用于匹配的 EventKey; 普通关注的 subscribe 事件没有 EventKey, 返回 false.
+func eventKey(msg *MixedMessage) (key string, ok bool) {
+	if msg.Event == EventTypeSubscribe {
+		if !strings.HasPrefix(msg.EventKey, qrScenePrefix) {
+			return
+		}
+		return msg.EventKey[len(qrScenePrefix):], true
+	}
+	return msg.EventKey, true
+}
+
+// 下面是按照消息(事件)类型注册的便捷方法, Handler 收到的是已经转换好的消息(事件)结构.
+
+func (r *Router) OnText(fn func(ctx context.Context, req *Request, msg *ReqText) (interface{}, error)) {
+	r.HandleMessage(MsgTypeText, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetText(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnImage(fn func(ctx context.Context, req *Request, msg *ReqImage) (interface{}, error)) {
+	r.HandleMessage(MsgTypeImage, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetImage(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnVoice(fn func(ctx context.Context, req *Request, msg *ReqVoice) (interface{}, error)) {
+	r.HandleMessage(MsgTypeVoice, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetVoice(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnVideo(fn func(ctx context.Context, req *Request, msg *ReqVideo) (interface{}, error)) {
+	r.HandleMessage(MsgTypeVideo, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetVideo(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnLocation(fn func(ctx context.Context, req *Request, msg *ReqLocation) (interface{}, error)) {
+	r.HandleMessage(MsgTypeLocation, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetLocation(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnLink(fn func(ctx context.Context, req *Request, msg *ReqLink) (interface{}, error)) {
+	r.HandleMessage(MsgTypeLink, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetLink(req.MixedMessage))
+	}))
+}
+
+// 普通关注事件; 扫描带参数二维码关注的事件没有匹配上 OnSubscribeByScan 时也由 fn 处理.
+func (r *Router) OnSubscribe(fn func(ctx context.Context, req *Request, event *SubscribeEvent) (interface{}, error)) {
+	r.HandleEvent(EventTypeSubscribe, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetSubscribeEvent(req.MixedMessage))
+	}))
+}
+
+// 扫描带参数二维码关注的事件, match 匹配去掉 "qrscene_" 前缀后的场景值, 为 nil 时匹配所有场景.
+func (r *Router) OnSubscribeByScan(match KeyMatcher, fn func(ctx context.Context, req *Request, event *SubscribeByScanEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeSubscribe, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetSubscribeByScanEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnUnsubscribe(fn func(ctx context.Context, req *Request, event *UnsubscribeEvent) (interface{}, error)) {
+	r.HandleEvent(EventTypeUnsubscribe, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetUnsubscribeEvent(req.MixedMessage))
+	}))
+}
+
+// 已关注用户扫描带参数二维码的事件, match 匹配场景值, 为 nil 时匹配所有场景.
+func (r *Router) OnScan(match KeyMatcher, fn func(ctx context.Context, req *Request, event *ScanEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeScan, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetScanEvent(req.MixedMessage))
+	}))
+}
+
+// 上报地理位置事件
+func (r *Router) OnLocationEvent(fn func(ctx context.Context, req *Request, event *LocationEvent) (interface{}, error)) {
+	r.HandleEvent(EventTypeLocation, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetLocationEvent(req.MixedMessage))
+	}))
+}
+
+// 点击菜单拉取消息事件, match 匹配菜单的 key, 为 nil 时匹配所有的 key.
+func (r *Router) OnClick(match KeyMatcher, fn func(ctx context.Context, req *Request, event *ClickEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeClick, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetClickEvent(req.MixedMessage))
+	}))
+}
+
+// 点击菜单跳转链接事件, match 匹配链接, 为 nil 时匹配所有的链接.
+func (r *Router) OnView(match KeyMatcher, fn func(ctx context.Context, req *Request, event *ViewEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeView, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetViewEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnScanCodePush(match KeyMatcher, fn func(ctx context.Context, req *Request, event *ScanCodePushEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeScanCodePush, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetScanCodePushEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnScanCodeWaitMsg(match KeyMatcher, fn func(ctx context.Context, req *Request, event *ScanCodeWaitMsgEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeScanCodeWaitMsg, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetScanCodeWaitMsgEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnPicSysPhoto(match KeyMatcher, fn func(ctx context.Context, req *Request, event *PicSysPhotoEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypePicSysPhoto, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetPicSysPhotoEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnPicPhotoOrAlbum(match KeyMatcher, fn func(ctx context.Context, req *Request, event *PicPhotoOrAlbumEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypePicPhotoOrAlbum, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetPicPhotoOrAlbumEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnPicWeixin(match KeyMatcher, fn func(ctx context.Context, req *Request, event *PicWeixinEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypePicWeixin, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetPicWeixinEvent(req.MixedMessage))
+	}))
+}
+
+func (r *Router) OnLocationSelect(match KeyMatcher, fn func(ctx context.Context, req *Request, event *LocationSelectEvent) (interface{}, error)) {
+	r.HandleEventKey(EventTypeLocationSelect, match, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetLocationSelectEvent(req.MixedMessage))
+	}))
+}
+
+// 门店审核结果事件
+func (r *Router) OnPoiCheckNotify(fn func(ctx context.Context, req *Request, event *PoiCheckNotifyEvent) (interface{}, error)) {
+	r.HandleEvent(EventTypePoiCheckNotify, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetPoiCheckNotifyEvent(req.MixedMessage))
+	}))
+}
+
+// 模板消息发送任务完成事件
+func (r *Router) OnTemplateSendJobFinish(fn func(ctx context.Context, req *Request, event *TemplateSendJobFinishEvent) (interface{}, error)) {
+	r.HandleEvent(EventTypeTemplateSendJobFinish, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		return fn(ctx, req, GetTemplateSendJobFinishEvent(req.MixedMessage))
+	}))
+}
@@ -0,0 +1,84 @@
+package mp
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	var got []string
+	record := func(name string) { got = append(got, name) }
+
+	router := NewRouter()
+	router.OnText(func(ctx context.Context, req *Request, msg *ReqText) (interface{}, error) {
+		record("text:" + msg.Content)
+		return nil, nil
+	})
+	router.OnClick(Key("V1001_TODAY"), func(ctx context.Context, req *Request, event *ClickEvent) (interface{}, error) {
+		record("click:" + event.EventKey)
+		return nil, nil
+	})
+	router.OnClick(KeyPrefix("V1001_"), func(ctx context.Context, req *Request, event *ClickEvent) (interface{}, error) {
+		record("click-prefix:" + event.EventKey)
+		return nil, nil
+	})
+	router.OnSubscribeByScan(KeyRegexp(regexp.MustCompile(`^\d+$`)), func(ctx context.Context, req *Request, event *SubscribeByScanEvent) (interface{}, error) {
+		scene, err := event.Scene()
+		record("scan-subscribe:" + scene)
+		return nil, err
+	})
+	router.OnSubscribe(func(ctx context.Context, req *Request, event *SubscribeEvent) (interface{}, error) {
+		record("subscribe")
+		return nil, nil
+	})
+	router.OnTemplateSendJobFinish(func(ctx context.Context, req *Request, event *TemplateSendJobFinishEvent) (interface{}, error) {
+		record("template:" + event.Status)
+		return nil, nil
+	})
+	router.SetFallback(HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		record("fallback:" + req.MixedMessage.MsgType)
+		return nil, nil
+	}))
+	router.Use(func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+			record("mw")
+			return next.ServeMessage(ctx, req)
+		})
+	})
+
+	messages := []*MixedMessage{
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeText}, Content: "hi"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeClick, EventKey: "V1001_TODAY"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeClick, EventKey: "V1001_GOOD"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeSubscribe, EventKey: "qrscene_123"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeSubscribe, EventKey: "qrscene_abc"},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeSubscribe},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent}, Event: EventTypeTemplateSendJobFinish, Status: TemplateSendStatusSuccess},
+		{CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeImage}},
+	}
+	for _, msg := range messages {
+		if _, err := router.ServeMessage(context.Background(), &Request{MixedMessage: msg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"mw", "text:hi",
+		"mw", "click:V1001_TODAY",
+		"mw", "click-prefix:V1001_GOOD",
+		"mw", "scan-subscribe:123",
+		"mw", "subscribe",
+		"mw", "subscribe",
+		"mw", "template:success",
+		"mw", "fallback:image",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
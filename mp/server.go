@@ -0,0 +1,336 @@
+package mp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+const (
+	DefaultTimestampTolerance = 5 * time.Minute // 默认允许的 timestamp 和本地时间的误差
+
+	maxRequestBodySize = 1 << 20 // 消息(事件)的 http body 最大 1MB
+)
+
+var (
+	ErrInvalidSignature = errors.New("mp: invalid signature")
+	ErrInvalidTimestamp = errors.New("mp: invalid timestamp")
+	ErrAESKeyNotSet     = errors.New("mp: message is encrypted but AES key is not set")
+)
+
+// 微信服务器推送过来的一个消息(事件).
+type Request struct {
+	HttpRequest *http.Request
+
+	Timestamp    int64
+	Nonce        string
+	EncryptType  string // 明文模式为 "", 兼容模式和安全模式为 "aes"
+	MsgSignature string // 兼容模式和安全模式下的 msg_signature
+
+	RawMsgXML    []byte        // 消息(事件)的 XML, 安全模式下是解密后的 XML
+	MixedMessage *MixedMessage // RawMsgXML 解析后的消息
+}
+
+// 处理微信服务器推送过来的消息(事件).
+//  reply 为被动回复的消息(比如 NewResText 的返回值), Server 会按照请求的模式(明文或者密文)写回;
+//  reply 为 nil 表示不需要回复, Server 回复 "success";
+//  err 不为 nil 时 Server 不回复消息, 由 ErrorHandler 处理, 默认返回 500, 微信服务器会重试.
+type Handler interface {
+	ServeMessage(ctx context.Context, req *Request) (reply interface{}, err error)
+}
+
+type HandlerFunc func(ctx context.Context, req *Request) (reply interface{}, err error)
+
+func (fn HandlerFunc) ServeMessage(ctx context.Context, req *Request) (reply interface{}, err error) {
+	return fn(ctx, req)
+}
+
+// 处理 Server 的错误, 比如签名错误, 消息格式错误, Handler 返回的错误.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// 默认的 ErrorHandler, 请求本身有问题(签名错误, 格式错误等)返回 400, 其他错误返回 500.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	var badReq *badRequestError
+	if errors.As(err, &badReq) {
+		status = http.StatusBadRequest
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+type badRequestError struct {
+	err error
+}
+
+func (e *badRequestError) Error() string { return e.err.Error() }
+func (e *badRequestError) Unwrap() error { return e.err }
+
+func badRequest(err error) error {
+	return &badRequestError{err: err}
+}
+
+// 接收微信服务器推送的消息(事件)的 http.Handler, 用法:
+//
+//    srv := mp.NewServer(appId, token, mp.HandlerFunc(serveMessage))
+//    if err := srv.SetAESKey(encodedAESKey); err != nil { // 兼容模式和安全模式需要设置 EncodingAESKey
+//        ...
+//    }
+//    http.Handle("/wechat/callback", srv)
+//
+//  NOTE:
+//  1. GET 请求用于验证服务器地址的有效性, 签名正确时原样返回 echostr;
+//  2. POST 请求根据 URL 里的 encrypt_type 区分模式: 没有 encrypt_type 为明文模式, 校验 signature;
+//     encrypt_type=aes 为兼容模式或者安全模式, 校验 msg_signature, 解密 Encrypt 字段, 被动回复的消息也加密;
+//  3. 同时校验 timestamp 和本地时间的误差, 默认为 DefaultTimestampTolerance.
+type Server struct {
+	appId string
+	token string
+
+	aesKey    [32]byte
+	hasAESKey bool
+
+	handler            Handler
+	errorHandler       ErrorHandler
+	timestampTolerance time.Duration
+
+	asyncClient       *Client // 不为 nil 时启用异步回复, 参考 SetAsyncReply
+	asyncBudget       time.Duration
+	asyncErrorHandler func(req *Request, err error)
+}
+
+func NewServer(appId, token string, handler Handler) *Server {
+	return &Server{
+		appId:              appId,
+		token:              token,
+		handler:            handler,
+		errorHandler:       DefaultErrorHandler,
+		timestampTolerance: DefaultTimestampTolerance,
+	}
+}
+
+// 设置 EncodingAESKey(43 个字符), 兼容模式和安全模式需要.
+func (srv *Server) SetAESKey(encodedAESKey string) (err error) {
+	key, err := util.AESKeyDecode(encodedAESKey)
+	if err != nil {
+		return
+	}
+	if len(key) != 32 {
+		err = fmt.Errorf("the length of AES key must be equal to 32, now is %d", len(key))
+		return
+	}
+	copy(srv.aesKey[:], key)
+	srv.hasAESKey = true
+	return
+}
+
+// 设置处理错误的 ErrorHandler, 默认为 DefaultErrorHandler.
+func (srv *Server) SetErrorHandler(handler ErrorHandler) {
+	if handler == nil {
+		handler = DefaultErrorHandler
+	}
+	srv.errorHandler = handler
+}
+
+// 设置允许的 timestamp 和本地时间的误差, 默认为 DefaultTimestampTolerance; <= 0 表示不校验.
+func (srv *Server) SetTimestampTolerance(d time.Duration) {
+	srv.timestampTolerance = d
+}
+
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		srv.serveEcho(w, r)
+	case http.MethodPost:
+		srv.serveMessage(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// 验证服务器地址的有效性
+func (srv *Server) serveEcho(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if _, err := srv.checkSignature(query.Get("signature"), query.Get("timestamp"), query.Get("nonce")); err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+	io.WriteString(w, query.Get("echostr"))
+}
+
+func (srv *Server) serveMessage(w http.ResponseWriter, r *http.Request) {
+	req, err := srv.parseRequest(r)
+	if err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+	if srv.asyncClient != nil {
+		srv.serveMessageAsync(w, r, req)
+		return
+	}
+
+	reply, err := srv.handler.ServeMessage(r.Context(), req)
+	if err != nil {
+		srv.errorHandler(w, r, err)
+		return
+	}
+	if err = srv.writeReply(w, req, reply); err != nil {
+		srv.errorHandler(w, r, err)
+	}
+}
+
+// 校验签名和时间戳, 返回解析后的 timestamp
+func (srv *Server) checkSignature(signature, timestampStr, nonce string) (timestamp int64, err error) {
+	if timestamp, err = srv.checkTimestamp(timestampStr); err != nil {
+		return
+	}
+	if !signatureEqual(signature, util.Sign(srv.token, timestampStr, nonce)) {
+		err = badRequest(ErrInvalidSignature)
+	}
+	return
+}
+
+func (srv *Server) checkTimestamp(timestampStr string) (timestamp int64, err error) {
+	timestamp, err = strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		err = badRequest(ErrInvalidTimestamp)
+		return
+	}
+	if srv.timestampTolerance > 0 {
+		d := time.Since(time.Unix(timestamp, 0))
+		if d > srv.timestampTolerance || d < -srv.timestampTolerance {
+			err = badRequest(ErrInvalidTimestamp)
+		}
+	}
+	return
+}
+
+// 常量时间比较签名, 避免计时攻击
+func signatureEqual(have, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(have), []byte(want)) == 1
+}
+
+// 安全模式下 http body 的格式
+type requestHttpBody struct {
+	XMLName      struct{} `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	EncryptedMsg string   `xml:"Encrypt"`
+}
+
+func (srv *Server) parseRequest(r *http.Request) (req *Request, err error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+	if err != nil {
+		return
+	}
+	if len(body) > maxRequestBodySize {
+		err = badRequest(errors.New("mp: request body too large"))
+		return
+	}
+
+	query := r.URL.Query()
+	req = &Request{
+		HttpRequest: r,
+		Nonce:       query.Get("nonce"),
+		EncryptType: query.Get("encrypt_type"),
+	}
+
+	switch req.EncryptType {
+	case "":
+		if req.Timestamp, err = srv.checkSignature(query.Get("signature"), query.Get("timestamp"), req.Nonce); err != nil {
+			return
+		}
+		req.RawMsgXML = body
+
+	case "aes":
+		if !srv.hasAESKey {
+			err = ErrAESKeyNotSet
+			return
+		}
+		timestampStr := query.Get("timestamp")
+		if req.Timestamp, err = srv.checkTimestamp(timestampStr); err != nil {
+			return
+		}
+
+		var httpBody requestHttpBody
+		if err = xml.Unmarshal(body, &httpBody); err != nil {
+			err = badRequest(err)
+			return
+		}
+		req.MsgSignature = query.Get("msg_signature")
+		if !signatureEqual(req.MsgSignature, util.MsgSign(srv.token, timestampStr, req.Nonce, httpBody.EncryptedMsg)) {
+			err = badRequest(ErrInvalidSignature)
+			return
+		}
+
+		var encryptedMsg []byte
+		if encryptedMsg, err = base64.StdEncoding.DecodeString(httpBody.EncryptedMsg); err != nil {
+			err = badRequest(err)
+			return
+		}
+		if _, req.RawMsgXML, err = util.AESDecryptMsg(encryptedMsg, srv.appId, srv.aesKey); err != nil {
+			err = badRequest(err)
+			return
+		}
+
+	default:
+		err = badRequest(fmt.Errorf("mp: unknown encrypt_type: %s", req.EncryptType))
+		return
+	}
+
+	var msg MixedMessage
+	if err = xml.Unmarshal(req.RawMsgXML, &msg); err != nil {
+		err = badRequest(err)
+		return
+	}
+	req.MixedMessage = &msg
+	return
+}
+
+// 写回被动回复的消息
+func (srv *Server) writeReply(w http.ResponseWriter, req *Request, reply interface{}) (err error) {
+	if reply == nil {
+		io.WriteString(w, "success")
+		return
+	}
+
+	var body []byte
+	if req.EncryptType == "" {
+		if body, err = xml.Marshal(reply); err != nil {
+			return
+		}
+	} else {
+		random := make([]byte, 16)
+		if _, err = rand.Read(random); err != nil {
+			return
+		}
+		param := &RequestParam{
+			AppId:     srv.appId,
+			Random:    random,
+			Timestamp: time.Now().Unix(),
+			Token:     srv.token,
+			AESKey:    srv.aesKey,
+			Nonce:     req.Nonce,
+		}
+		var httpBody ResponseHttpBody
+		if httpBody, err = GetAESResponse(param, reply); err != nil {
+			return
+		}
+		if body, err = xml.Marshal(&httpBody); err != nil {
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	_, err = w.Write(body)
+	return
+}
@@ -0,0 +1,125 @@
+package mp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// 微信服务器等待被动回复的时间是 5 秒, 默认留出 1 秒的余量.
+const DefaultAsyncReplyBudget = 4 * time.Second
+
+// 设置异步回复: Handler 在 budget 内没有返回时, 先回复微信服务器 "success",
+// 等 Handler 返回后再通过 clt.SendCustomMessage 把被动回复的消息作为客服消息发送给用户.
+//  clt 为 nil 时关闭异步回复(默认); budget <= 0 时使用 DefaultAsyncReplyBudget.
+//
+//  NOTE:
+//  1. 支持转换为客服消息的被动回复消息有 *ResText, *ResImage, *ResVoice, *ResVideo, *ResMusic, *ResNews;
+//  2. 超时之后 Handler 继续执行, 它收到的 ctx 不会因为 http 请求结束而取消;
+//  3. 超时之后 Handler 返回的错误和发送客服消息的错误交给 SetAsyncErrorHandler 设置的函数处理;
+//  4. 客服消息只能发送给 48 小时内和公众号有过互动的用户, 一般的被动回复场景都满足;
+//  5. 客服图文消息只能有 CustomNewsArticleCountLimit 篇文章, 多图文的 *ResNews 只发送前面的文章.
+func (srv *Server) SetAsyncReply(clt *Client, budget time.Duration) {
+	if budget <= 0 {
+		budget = DefaultAsyncReplyBudget
+	}
+	srv.asyncClient = clt
+	srv.asyncBudget = budget
+}
+
+// 设置处理异步回复错误的函数, 默认忽略这些错误.
+func (srv *Server) SetAsyncErrorHandler(handler func(req *Request, err error)) {
+	srv.asyncErrorHandler = handler
+}
+
+type handlerResult struct {
+	reply interface{}
+	err   error
+}
+
+// 在 asyncBudget 内等待 Handler 返回, 超时则先回复 "success", 之后通过客服消息发送
+func (srv *Server) serveMessageAsync(w http.ResponseWriter, r *http.Request, req *Request) {
+	ctx := context.WithoutCancel(r.Context())
+	done := make(chan handlerResult, 1)
+	go func() {
+		var res handlerResult
+		defer func() {
+			if v := recover(); v != nil {
+				res = handlerResult{err: fmt.Errorf("mp: handler panic: %v", v)}
+			}
+			done <- res
+		}()
+		res.reply, res.err = srv.handler.ServeMessage(ctx, req)
+	}()
+
+	timer := time.NewTimer(srv.asyncBudget)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			srv.errorHandler(w, r, res.err)
+			return
+		}
+		if err := srv.writeReply(w, req, res.reply); err != nil {
+			srv.errorHandler(w, r, err)
+		}
+	case <-timer.C:
+		io.WriteString(w, "success")
+		go srv.sendLateReply(ctx, req, done)
+	}
+}
+
+func (srv *Server) sendLateReply(ctx context.Context, req *Request, done <-chan handlerResult) {
+	res := <-done
+	err := res.err
+	if err == nil && res.reply != nil {
+		var msg interface{}
+		if msg, err = customMessageFromReply(req.MixedMessage.FromUserName, res.reply); err == nil {
+			err = srv.asyncClient.SendCustomMessageContext(ctx, msg)
+		}
+	}
+	if err != nil && srv.asyncErrorHandler != nil {
+		srv.asyncErrorHandler(req, err)
+	}
+}
+
+// 把被动回复的消息转换为客服消息
+func customMessageFromReply(toUser string, reply interface{}) (msg interface{}, err error) {
+	switch v := reply.(type) {
+	case *ResText:
+		msg = NewCustomText(toUser, v.Content)
+	case *ResImage:
+		msg = NewCustomImage(toUser, v.Image.MediaId)
+	case *ResVoice:
+		msg = NewCustomVoice(toUser, v.Voice.MediaId)
+	case *ResVideo:
+		msg = NewCustomVideo(toUser, v.Video.MediaId, "", v.Video.Title, v.Video.Description)
+	case *ResMusic:
+		msg = NewCustomMusic(toUser, v.Music.ThumbMediaId, v.Music.MusicURL, v.Music.HQMusicURL, v.Music.Title, v.Music.Description)
+	case *ResNews:
+		if len(v.Articles) == 0 {
+			err = fmt.Errorf("mp: can not convert %T without articles to custom message", reply)
+			return
+		}
+		// 客服图文消息只能有 CustomNewsArticleCountLimit 篇文章, 多余的丢弃
+		articles := v.Articles
+		if len(articles) > CustomNewsArticleCountLimit {
+			articles = articles[:CustomNewsArticleCountLimit]
+		}
+		customArticles := make([]CustomArticle, 0, len(articles))
+		for _, article := range articles {
+			customArticles = append(customArticles, CustomArticle{
+				Title:       article.Title,
+				Description: article.Digest,
+				URL:         article.ContentSourceURL,
+			})
+		}
+		msg = NewCustomNews(toUser, customArticles)
+	default:
+		err = fmt.Errorf("mp: can not convert %T to custom message", reply)
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package mp
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestServerAsyncReply(t *testing.T) {
+	api := wechattest.NewServer()
+	defer api.Close()
+	api.SetUser("openid", nil)
+	clt := NewClient(api.AppId, api.AppSecret)
+	clt.SetBaseURL(api.URL, "")
+
+	handler := HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		msg := req.MixedMessage
+		if msg.Content == "slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return NewResText(msg.FromUserName, msg.ToUserName, msg.CreateTime, "re: "+msg.Content), nil
+	})
+	srv := NewServer(testAppId, testToken, handler)
+	srv.SetAsyncReply(clt, 20*time.Millisecond)
+	errs := make(chan error, 1)
+	srv.SetAsyncErrorHandler(func(req *Request, err error) { errs <- err })
+
+	post := func(content string) *httptest.ResponseRecorder {
+		query := signedQuery(time.Now().Unix(), "nonce")
+		body := strings.Replace(testTextXML, "hello", content, 1)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(body)))
+		return w
+	}
+
+	// 在 budget 内返回的照常被动回复
+	if w := post("fast"); !strings.Contains(w.Body.String(), "<Content>re: fast</Content>") {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	// 超时先回复 success, 之后通过客服消息发送
+	if w := post("slow"); w.Body.String() != "success" {
+		t.Fatalf("body = %s, want success", w.Body.String())
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	var calls []wechattest.Call
+	for time.Now().Before(deadline) {
+		if calls = api.CallsTo("/cgi-bin/message/custom/send"); len(calls) > 0 {
+			break
+		}
+		select {
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if len(calls) != 1 {
+		t.Fatalf("custom message calls = %d, want 1", len(calls))
+	}
+	var msg struct {
+		ToUser  string `json:"touser"`
+		MsgType string `json:"msgtype"`
+		Text    struct {
+			Content string `json:"content"`
+		} `json:"text"`
+	}
+	if err := calls[0].Decode(&msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.ToUser != "openid" || msg.MsgType != MsgTypeText || msg.Text.Content != "re: slow" {
+		t.Errorf("custom message = %+v", msg)
+	}
+}
+
+func TestCustomMessageFromReplyNews(t *testing.T) {
+	reply := NewResNews("openid", "gh_id", time.Now().Unix(), []Article{
+		{Title: "first", Digest: "digest1", ContentSourceURL: "http://example.com/1"},
+		{Title: "second", Digest: "digest2", ContentSourceURL: "http://example.com/2"},
+	})
+	msg, err := customMessageFromReply("openid", reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	news, ok := msg.(*CustomNews)
+	if !ok {
+		t.Fatalf("msg = %T, want *CustomNews", msg)
+	}
+	if err = news.CheckValid(); err != nil {
+		t.Fatal(err)
+	}
+	if len(news.News.Articles) != 1 || news.News.Articles[0].Title != "first" {
+		t.Errorf("articles = %+v", news.News.Articles)
+	}
+
+	if _, err = customMessageFromReply("openid", NewResNews("openid", "gh_id", time.Now().Unix(), nil)); err == nil {
+		t.Error("news without articles should fail")
+	}
+}
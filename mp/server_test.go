@@ -0,0 +1,147 @@
+package mp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/util"
+)
+
+const (
+	testAppId  = "wx_test_appid"
+	testToken  = "test_token"
+	testAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+const testTextXML = `<xml><ToUserName><![CDATA[gh_123]]></ToUserName><FromUserName><![CDATA[openid]]></FromUserName>` +
+	`<CreateTime>1348831860</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hello]]></Content><MsgId>1234567890123456</MsgId></xml>`
+
+func newTestServer(t *testing.T) *Server {
+	srv := NewServer(testAppId, testToken, HandlerFunc(func(ctx context.Context, req *Request) (interface{}, error) {
+		msg := req.MixedMessage
+		if msg.MsgType != MsgTypeText {
+			return nil, nil
+		}
+		return NewResText(msg.FromUserName, msg.ToUserName, msg.CreateTime, "re: "+msg.Content), nil
+	}))
+	if err := srv.SetAESKey(testAESKey); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+// 构造明文模式的请求 URL
+func signedQuery(timestamp int64, nonce string) url.Values {
+	timestampStr := strconv.FormatInt(timestamp, 10)
+	return url.Values{
+		"timestamp": {timestampStr},
+		"nonce":     {nonce},
+		"signature": {util.Sign(testToken, timestampStr, nonce)},
+	}
+}
+
+func TestServerEcho(t *testing.T) {
+	srv := newTestServer(t)
+
+	query := signedQuery(time.Now().Unix(), "nonce")
+	query.Set("echostr", "echo123")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", "/?"+query.Encode(), nil))
+	if w.Code != http.StatusOK || w.Body.String() != "echo123" {
+		t.Errorf("echo = %d %q", w.Code, w.Body.String())
+	}
+
+	query.Set("signature", "bad")
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("GET", "/?"+query.Encode(), nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestServerPlaintext(t *testing.T) {
+	srv := newTestServer(t)
+
+	query := signedQuery(time.Now().Unix(), "nonce")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(testTextXML)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var reply ResText
+	if err := xml.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Content != "re: hello" || reply.ToUserName != "openid" {
+		t.Errorf("reply = %+v", reply)
+	}
+
+	// 过期的 timestamp
+	query = signedQuery(time.Now().Add(-time.Hour).Unix(), "nonce")
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(testTextXML)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestServerAES(t *testing.T) {
+	srv := newTestServer(t)
+	aesKey, _ := util.AESKeyDecode(testAESKey)
+	var key [32]byte
+	copy(key[:], aesKey)
+
+	encrypted := base64.StdEncoding.EncodeToString(util.AESEncryptMsg([]byte("0123456789abcdef"), []byte(testTextXML), testAppId, key))
+	body := `<xml><ToUserName><![CDATA[gh_123]]></ToUserName><Encrypt><![CDATA[` + encrypted + `]]></Encrypt></xml>`
+
+	now := time.Now().Unix()
+	timestamp := strconv.FormatInt(now, 10)
+	query := signedQuery(now, "nonce")
+	query.Set("encrypt_type", "aes")
+	query.Set("msg_signature", util.MsgSign(testToken, timestamp, "nonce", encrypted))
+
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+
+	var resp ResponseHttpBody
+	if err := xml.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.MsgSignature != util.MsgSign(testToken, strconv.FormatInt(resp.TimeStamp, 10), resp.Nonce, resp.EncryptedMsg) {
+		t.Error("invalid reply msg_signature")
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(resp.EncryptedMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rawXML, err := util.AESDecryptMsg(cipherText, testAppId, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reply ResText
+	if err = xml.Unmarshal(rawXML, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Content != "re: hello" {
+		t.Errorf("reply = %+v", reply)
+	}
+
+	// msg_signature 错误
+	query.Set("msg_signature", "bad")
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest("POST", "/?"+query.Encode(), strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
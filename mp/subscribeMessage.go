@@ -0,0 +1,177 @@
+package mp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/skynology/go-crypto"
+)
+
+const (
+	SubscribeMsgActionConfirm = "confirm" // 用户同意授权
+	SubscribeMsgActionCancel  = "cancel"  // 用户取消授权
+)
+
+const (
+	SubscribeMsgSceneMax       = 10000 // scene 的取值范围为 0-10000
+	SubscribeMsgTitleLimit     = 15    // 消息标题最多 15 个字符
+	SubscribeMsgContentLimit   = 200   // 消息正文最多 200 个字符
+	subscribeMsgContentDataKey = "content"
+)
+
+var ErrSubscribeMsgReservedMismatch = errors.New("mp: subscribemsg reserved mismatch")
+
+// 构造一次性订阅消息的授权地址, 用户同意或者取消授权后跳转到 redirectURL.
+//  appId:       公众号的唯一标识
+//  scene:       订阅场景值, 开发者可以填 0-10000 的整形值, 用来标识订阅场景值
+//  templateId:  订阅消息模板ID, 登录公众平台后台, 在接口权限列表处可查看订阅模板ID
+//  redirectURL: 授权后重定向的回调地址, 回调地址会带上 openid, template_id, action, scene, reserved 参数
+//  reserved:    用于保持请求和回调的状态, 授权后原样带回给第三方, 同 OAuthCodeURL 的 state,
+//               回调时用 ParseSubscribeMsgResult 校验
+//  NOTE: 总是使用 DefaultMPBaseURL, 需要替换域名时使用 Client.SubscribeMsgURL.
+func SubscribeMsgURL(appId string, scene int, templateId, redirectURL string) (authUrl string, reserved string) {
+	return subscribeMsgURL(DefaultMPBaseURL, appId, scene, templateId, redirectURL)
+}
+
+// 同 SubscribeMsgURL, appId 为 clt 的 appId, 域名由 SetMPBaseURL 设置.
+func (clt *Client) SubscribeMsgURL(scene int, templateId, redirectURL string) (authUrl string, reserved string) {
+	return subscribeMsgURL(clt.mpBaseURL, clt.appId, scene, templateId, redirectURL)
+}
+
+func subscribeMsgURL(mpBaseURL, appId string, scene int, templateId, redirectURL string) (authUrl string, reserved string) {
+	reserved = crypto.GetRandomKey()
+	authUrl = mpBaseURL + "/mp/subscribemsg?action=get_confirm" +
+		"&appid=" + url.QueryEscape(appId) +
+		"&scene=" + strconv.Itoa(scene) +
+		"&template_id=" + url.QueryEscape(templateId) +
+		"&redirect_url=" + url.QueryEscape(redirectURL) +
+		"&reserved=" + url.QueryEscape(reserved) +
+		"#wechat_redirect"
+
+	return
+}
+
+// 一次性订阅消息授权后的回调参数
+type SubscribeMsgResult struct {
+	OpenId     string // 用户唯一标识, 只在用户确认授权时才会带上
+	TemplateId string // 订阅消息模板ID
+	Action     string // 用户点击动作, SubscribeMsgActionConfirm 或者 SubscribeMsgActionCancel
+	Scene      int    // 订阅场景值
+	Reserved   string // 请求带入原样返回
+}
+
+// 用户是否同意授权
+func (result *SubscribeMsgResult) Confirmed() bool {
+	return result.Action == SubscribeMsgActionConfirm
+}
+
+// 解析一次性订阅消息授权后回调地址的参数, 比如 ParseSubscribeMsgResult(r.URL.Query(), reserved).
+//  reserved 为 SubscribeMsgURL 返回的 reserved, 不一致时返回 ErrSubscribeMsgReservedMismatch.
+func ParseSubscribeMsgResult(query url.Values, reserved string) (result *SubscribeMsgResult, err error) {
+	if subtle.ConstantTimeCompare([]byte(query.Get("reserved")), []byte(reserved)) != 1 {
+		err = ErrSubscribeMsgReservedMismatch
+		return
+	}
+
+	result = &SubscribeMsgResult{
+		OpenId:     query.Get("openid"),
+		TemplateId: query.Get("template_id"),
+		Action:     query.Get("action"),
+		Reserved:   query.Get("reserved"),
+	}
+	if result.Scene, err = strconv.Atoi(query.Get("scene")); err != nil {
+		err = fmt.Errorf("invalid scene: %q", query.Get("scene"))
+		result = nil
+		return
+	}
+	switch result.Action {
+	case SubscribeMsgActionConfirm:
+		if result.OpenId == "" {
+			err = errors.New("openid is empty")
+			result = nil
+			return
+		}
+	case SubscribeMsgActionCancel:
+	default:
+		err = fmt.Errorf("invalid action: %q", result.Action)
+		result = nil
+		return
+	}
+	return
+}
+
+// 一次性订阅消息
+type SubscribeMessage struct {
+	ToUser     string       `json:"touser"`        // 必须, 填接收消息的用户openid
+	TemplateId string       `json:"template_id"`   // 必须, 订阅消息模板ID
+	URL        string       `json:"url,omitempty"` // 可选, 点击消息跳转的链接, 需要有ICP备案
+	Scene      int          `json:"scene"`         // 必须, 订阅场景值
+	Title      string       `json:"title"`         // 必须, 消息标题, 15字以内
+	Data       TemplateData `json:"data"`          // 必须, 消息正文, 只有 "content" 一个参数, 200字以内
+}
+
+// 新建一次性订阅消息, color 为正文的颜色, 可以为 "".
+func NewSubscribeMessage(toUser, templateId string, scene int, title, content, color string) *SubscribeMessage {
+	return &SubscribeMessage{
+		ToUser:     toUser,
+		TemplateId: templateId,
+		Scene:      scene,
+		Title:      title,
+		Data:       TemplateData{}.Set(subscribeMsgContentDataKey, content, color),
+	}
+}
+
+// 检查 SubscribeMessage 是否有效，有效返回 nil，否则返回错误信息
+func (msg *SubscribeMessage) CheckValid() (err error) {
+	if msg.Scene < 0 || msg.Scene > SubscribeMsgSceneMax {
+		err = fmt.Errorf("订阅场景值必须在 0-%d 之间, 现在为 %d", SubscribeMsgSceneMax, msg.Scene)
+		return
+	}
+	if n := utf8.RuneCountInString(msg.Title); n > SubscribeMsgTitleLimit {
+		err = fmt.Errorf("消息标题不能超过 %d 个字, 现在为 %d", SubscribeMsgTitleLimit, n)
+		return
+	}
+	content, ok := msg.Data[subscribeMsgContentDataKey]
+	if !ok {
+		err = errors.New("消息正文 content 没有设置")
+		return
+	}
+	if n := utf8.RuneCountInString(content.Value); n > SubscribeMsgContentLimit {
+		err = fmt.Errorf("消息正文不能超过 %d 个字, 现在为 %d", SubscribeMsgContentLimit, n)
+		return
+	}
+	return
+}
+
+// 发送一次性订阅消息, 每次授权只能给用户发送一条.
+func (clt *Client) SendSubscribeMessage(msg *SubscribeMessage) (err error) {
+	return clt.SendSubscribeMessageContext(context.Background(), msg)
+}
+
+func (clt *Client) SendSubscribeMessageContext(ctx context.Context, msg *SubscribeMessage) (err error) {
+	if msg == nil {
+		err = errors.New("nil SubscribeMessage")
+		return
+	}
+	if err = msg.CheckValid(); err != nil {
+		return
+	}
+
+	var result Error
+
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/message/template/subscribe?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != ErrCodeOK {
+		err = &result
+		return
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package mp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestSubscribeMessage(t *testing.T) {
+	authURL, reserved := SubscribeMsgURL("wx_test_appid", 1000, "TEMPLATE_1", "https://example.com/cb?a=1")
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("action") != "get_confirm" || query.Get("scene") != "1000" || query.Get("reserved") != reserved ||
+		query.Get("redirect_url") != "https://example.com/cb?a=1" || !strings.HasSuffix(authURL, "#wechat_redirect") {
+		t.Errorf("authURL = %s", authURL)
+	}
+
+	callback := url.Values{
+		"openid":      {"openid"},
+		"template_id": {"TEMPLATE_1"},
+		"action":      {"confirm"},
+		"scene":       {"1000"},
+		"reserved":    {reserved},
+	}
+	result, err := ParseSubscribeMsgResult(callback, reserved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Confirmed() || result.OpenId != "openid" || result.Scene != 1000 {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err = ParseSubscribeMsgResult(callback, "other"); err != ErrSubscribeMsgReservedMismatch {
+		t.Errorf("err = %v, want ErrSubscribeMsgReservedMismatch", err)
+	}
+
+	api := wechattest.NewServer()
+	defer api.Close()
+	api.SetUser("openid", nil)
+	clt := NewClient(api.AppId, api.AppSecret)
+	clt.SetBaseURL(api.URL, "")
+	clt.SetMPBaseURL(api.URL)
+	if authURL, _ := clt.SubscribeMsgURL(1000, "TEMPLATE_1", "https://example.com/cb"); !strings.HasPrefix(authURL, api.URL+"/mp/subscribemsg?") ||
+		!strings.Contains(authURL, "appid="+api.AppId) {
+		t.Errorf("authURL = %s", authURL)
+	}
+
+	msg := NewSubscribeMessage(result.OpenId, result.TemplateId, result.Scene, "这个标题超过了十五个字的限制所以发送失败", "正文", "")
+	if err = clt.SendSubscribeMessage(msg); err == nil {
+		t.Error("expected error for too long title")
+	}
+	msg.Title = "订阅成功"
+	if err = clt.SendSubscribeMessage(msg); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(api.CallsTo("/cgi-bin/message/template/subscribe")); n != 1 {
+		t.Errorf("subscribe calls = %d, want 1", n)
+	}
+}
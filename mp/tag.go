@@ -1,6 +1,9 @@
 package mp
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 const TagCountLimit = 100 // 一个公众账号，最多支持创建100个标签
 
@@ -14,6 +17,10 @@ type Tag struct {
 // 创建标签.
 //  name: 标签名字（30个字符以内）.
 func (clt *Client) CreateTag(name string) (tag *Tag, err error) {
+	return clt.CreateTagContext(context.Background(), name)
+}
+
+func (clt *Client) CreateTagContext(ctx context.Context, name string) (tag *Tag, err error) {
 	if name == "" {
 		err = errors.New(`name == ""`)
 		return
@@ -31,8 +38,8 @@ func (clt *Client) CreateTag(name string) (tag *Tag, err error) {
 		Tag `json:"tag"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/create?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/create?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -47,6 +54,10 @@ func (clt *Client) CreateTag(name string) (tag *Tag, err error) {
 
 // 查询所有标签.
 func (clt *Client) ListTag() (tags []Tag, err error) {
+	return clt.ListTagContext(context.Background())
+}
+
+func (clt *Client) ListTagContext(ctx context.Context) (tags []Tag, err error) {
 	var result = struct {
 		Error
 		Tags []Tag `json:"tags"`
@@ -54,8 +65,8 @@ func (clt *Client) ListTag() (tags []Tag, err error) {
 		Tags: make([]Tag, 0),
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/get?access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/get?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -70,6 +81,10 @@ func (clt *Client) ListTag() (tags []Tag, err error) {
 // 修改标签名.
 //  name: 标签名字（30个字符以内）.
 func (clt *Client) TagRename(tagId int64, newName string) (err error) {
+	return clt.TagRenameContext(context.Background(), tagId, newName)
+}
+
+func (clt *Client) TagRenameContext(ctx context.Context, tagId int64, newName string) (err error) {
 	if newName == "" {
 		return errors.New(`newName == ""`)
 	}
@@ -85,8 +100,8 @@ func (clt *Client) TagRename(tagId int64, newName string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/update?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/update?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -100,6 +115,10 @@ func (clt *Client) TagRename(tagId int64, newName string) (err error) {
 // 删除标签.
 //  name: 标签名字（30个字符以内）.
 func (clt *Client) TagDelete(tagId int64) (err error) {
+	return clt.TagDeleteContext(context.Background(), tagId)
+}
+
+func (clt *Client) TagDeleteContext(ctx context.Context, tagId int64) (err error) {
 
 	var request struct {
 		TagId int64 `json:"tagid"`
@@ -108,8 +127,8 @@ func (clt *Client) TagDelete(tagId int64) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/delete?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/delete?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -134,13 +153,17 @@ type TagUserListResult struct {
 
 // 获取关注者列表, 每次最多能获取 10000 个用户, 如果 beginOpenId == "" 则表示从头获取
 func (clt *Client) TagUserList(tagId int64, beginOpenId string) (data *TagUserListResult, err error) {
+	return clt.TagUserListContext(context.Background(), tagId, beginOpenId)
+}
+
+func (clt *Client) TagUserListContext(ctx context.Context, tagId int64, beginOpenId string) (data *TagUserListResult, err error) {
 	var result struct {
 		Error
 		TagUserListResult
 	}
 
 	var incompleteURL string
-	incompleteURL = "https://api.weixin.qq.com/cgi-bin/user/tag/get?access_token="
+	incompleteURL = clt.apiBaseURL + "/cgi-bin/user/tag/get?access_token="
 
 	var request struct {
 		TagId      int64  `json:"tagid"`
@@ -149,7 +172,7 @@ func (clt *Client) TagUserList(tagId int64, beginOpenId string) (data *TagUserLi
 	request.TagId = tagId
 	request.NextOpenId = beginOpenId
 
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -163,6 +186,10 @@ func (clt *Client) TagUserList(tagId int64, beginOpenId string) (data *TagUserLi
 
 // 批量移动用户标签.
 func (clt *Client) UserBatchMoveToTag(openIdList []string, toTagId int64) (err error) {
+	return clt.UserBatchMoveToTagContext(context.Background(), openIdList, toTagId)
+}
+
+func (clt *Client) UserBatchMoveToTagContext(ctx context.Context, openIdList []string, toTagId int64) (err error) {
 	var request = struct {
 		OpenIdList []string `json:"openid_list"`
 		ToTagId    int64    `json:"tagid"`
@@ -173,8 +200,8 @@ func (clt *Client) UserBatchMoveToTag(openIdList []string, toTagId int64) (err e
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/members/batchtagging?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/members/batchtagging?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -187,6 +214,10 @@ func (clt *Client) UserBatchMoveToTag(openIdList []string, toTagId int64) (err e
 
 // 批量删除用户标签.
 func (clt *Client) UserBatchRemoveFromTag(openIdList []string, toTagId int64) (err error) {
+	return clt.UserBatchRemoveFromTagContext(context.Background(), openIdList, toTagId)
+}
+
+func (clt *Client) UserBatchRemoveFromTagContext(ctx context.Context, openIdList []string, toTagId int64) (err error) {
 	var request = struct {
 		OpenIdList []string `json:"openid_list"`
 		ToTagId    int64    `json:"tagid"`
@@ -197,8 +228,8 @@ func (clt *Client) UserBatchRemoveFromTag(openIdList []string, toTagId int64) (e
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/members/batchuntagging?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/members/batchuntagging?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -211,8 +242,12 @@ func (clt *Client) UserBatchRemoveFromTag(openIdList []string, toTagId int64) (e
 
 // 获取用户标签列表
 func (clt *Client) UserTagIdList(openid string) (tagIds []int64, err error) {
+	return clt.UserTagIdListContext(context.Background(), openid)
+}
+
+func (clt *Client) UserTagIdListContext(ctx context.Context, openid string) (tagIds []int64, err error) {
 	var request = struct {
-		OpenId string `json:openid"`
+		OpenId string `json:"openid"`
 	}{
 		OpenId: openid,
 	}
@@ -222,8 +257,8 @@ func (clt *Client) UserTagIdList(openid string) (tagIds []int64, err error) {
 		TagIds []int64 `json:"tagid_list"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/tags/getidlist?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/tags/getidlist?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -1,23 +1,135 @@
 package mp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/skynology/wechat/util"
 )
 
+// 模板消息里的一个字段
+type TemplateField struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"` // 可选, 字体颜色, 比如 "#173177"
+}
+
+// 模板消息的数据, key 为模板内容里的参数名, 比如 "{{first.DATA}}" 的 "first".
+//
+//    data := mp.TemplateData{}.
+//        Set("first", "您好, 您已购买成功.", "").
+//        Set("remark", "欢迎再次购买!", "#173177")
+type TemplateData map[string]TemplateField
+
+// 设置参数 name 的值和颜色(可以为 ""), 返回 data 本身, 方便链式调用.
+func (data TemplateData) Set(name, value, color string) TemplateData {
+	data[name] = TemplateField{Value: value, Color: color}
+	return data
+}
+
 type TemplateMessage struct {
 	ToUser     string `json:"touser"`             // 必须, 接受者OpenID
 	TemplateId string `json:"template_id"`        // 必须, 模版ID
 	URL        string `json:"url,omitempty"`      // 可选, 用户点击后跳转的URL，该URL必须处于开发者在公众平台网站中设置的域中
 	TopColor   string `json:"topcolor,omitempty"` // 可选, 整个消息的颜色, 可以不设置
 
-	// 必须, JSON 格式的 []byte, 满足特定的模板需求
+	// 必须, JSON 格式的 []byte, 满足特定的模板需求, 一般通过 SetData 设置
 	RawJSONData json.RawMessage `json:"data"`
 }
 
+// 用 data 设置 msg.RawJSONData.
+func (msg *TemplateMessage) SetData(data TemplateData) (err error) {
+	rawJSONData, err := util.MarshalJSON(data)
+	if err != nil {
+		return
+	}
+	msg.RawJSONData = rawJSONData
+	return
+}
+
+// 模板内容里的参数, 比如 "{{first.DATA}}"
+var templatePlaceholderRegexp = regexp.MustCompile(`\{\{\s*(\w+)\.DATA\s*\}\}`)
+
+// 解析模板内容 content, 按照出现的顺序返回所有的参数名(去重), 比如
+//  "{{first.DATA}}\n商品名称: {{keyword1.DATA}}" 返回 ["first", "keyword1"].
+func ParseTemplatePlaceholders(content string) (names []string) {
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholderRegexp.FindAllStringSubmatch(content, -1) {
+		if name := match[1]; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// 模板消息没有设置模板内容里的所有参数, 可以用 errors.Is(err, ErrTemplateDataIncomplete) 判断.
+var ErrTemplateDataIncomplete = errors.New("mp: template data incomplete")
+
+// 检查 data 是否设置了模板内容 content 里的所有参数, 没有设置的参数作为错误信息返回.
+//  NOTE: Client 获取过账号后台的模板(GetAllPrivateTemplate)后, SendTemplateMessage 发送前会自动检查.
+func CheckTemplateData(content string, data TemplateData) (err error) {
+	var missing []string
+	for _, name := range ParseTemplatePlaceholders(content) {
+		if _, ok := data[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("%w: 模板消息缺少参数: %s", ErrTemplateDataIncomplete, strings.Join(missing, ", "))
+	}
+	return
+}
+
+// GetAllPrivateTemplate 获取到的模板内容, 用于发送模板消息前检查参数. 零值可以直接使用.
+type templateCache struct {
+	mu       sync.RWMutex
+	contents map[string]string // template_id -> content
+}
+
+func (cache *templateCache) reset(templates []TemplateInfo) {
+	contents := make(map[string]string, len(templates))
+	for _, info := range templates {
+		contents[info.TemplateId] = info.Content
+	}
+	cache.mu.Lock()
+	cache.contents = contents
+	cache.mu.Unlock()
+}
+
+func (cache *templateCache) remove(templateId string) {
+	cache.mu.Lock()
+	delete(cache.contents, templateId)
+	cache.mu.Unlock()
+}
+
+// 检查 msg 是否设置了模板的所有参数, 模板内容未知时不检查.
+//  data 不能解析时原样返回解析的错误.
+func (cache *templateCache) check(msg *TemplateMessage) error {
+	cache.mu.RLock()
+	content, ok := cache.contents[msg.TemplateId]
+	cache.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	var data TemplateData
+	if err := json.Unmarshal(msg.RawJSONData, &data); err != nil {
+		return err
+	}
+	return CheckTemplateData(content, data)
+}
+
 // 设置所属行业.
 //  目前 industryId 的个数只能为 2.
 func (clt *Client) SetIndustry(industryId ...int64) (err error) {
+	return clt.SetIndustryContext(context.Background(), industryId...)
+}
+
+func (clt *Client) SetIndustryContext(ctx context.Context, industryId ...int64) (err error) {
 	if len(industryId) < 2 {
 		return errors.New("industryId 的个数不能小于 2")
 	}
@@ -32,8 +144,8 @@ func (clt *Client) SetIndustry(industryId ...int64) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/template/api_set_industry?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/template/api_set_industry?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -44,9 +156,45 @@ func (clt *Client) SetIndustry(industryId ...int64) (err error) {
 	return
 }
 
+// 行业信息
+type Industry struct {
+	FirstClass  string `json:"first_class"`  // 主行业
+	SecondClass string `json:"second_class"` // 副行业
+}
+
+// 获取设置的行业信息.
+func (clt *Client) GetIndustry() (primary, secondary Industry, err error) {
+	return clt.GetIndustryContext(context.Background())
+}
+
+func (clt *Client) GetIndustryContext(ctx context.Context) (primary, secondary Industry, err error) {
+	var result struct {
+		Error
+		PrimaryIndustry   Industry `json:"primary_industry"`
+		SecondaryIndustry Industry `json:"secondary_industry"`
+	}
+
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/template/get_industry?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	primary = result.PrimaryIndustry
+	secondary = result.SecondaryIndustry
+	return
+}
+
 // 从行业模板库选择模板添加到账号后台, 并返回模板id.
 //  templateIdShort: 模板库中模板的编号，有“TM**”和“OPENTMTM**”等形式.
 func (clt *Client) AddTemplate(templateIdShort string) (templateId string, err error) {
+	return clt.AddTemplateContext(context.Background(), templateIdShort)
+}
+
+func (clt *Client) AddTemplateContext(ctx context.Context, templateIdShort string) (templateId string, err error) {
 	var request = struct {
 		TemplateIdShort string `json:"template_id_short"`
 	}{
@@ -58,8 +206,8 @@ func (clt *Client) AddTemplate(templateIdShort string) (templateId string, err e
 		TemplateId string `json:"template_id"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/template/api_add_template?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/template/api_add_template?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -71,20 +219,29 @@ func (clt *Client) AddTemplate(templateIdShort string) (templateId string, err e
 	return
 }
 
-// 发送模板消息
+// 发送模板消息.
+//  NOTE: 如果之前调用过 GetAllPrivateTemplate, 发送前检查 msg 是否设置了模板的所有参数,
+//  缺少参数时不发送, 返回 ErrTemplateDataIncomplete.
 func (clt *Client) SendTemplateMessage(msg *TemplateMessage) (msgid int64, err error) {
+	return clt.SendTemplateMessageContext(context.Background(), msg)
+}
+
+func (clt *Client) SendTemplateMessageContext(ctx context.Context, msg *TemplateMessage) (msgid int64, err error) {
 	if msg == nil {
 		err = errors.New("nil TemplateMessage")
 		return
 	}
+	if err = clt.templates.check(msg); err != nil {
+		return
+	}
 
 	var result struct {
 		Error
 		MsgId int64 `json:"msgid"`
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/message/template/send?access_token="
-	if err = clt.PostJSON(incompleteURL, msg, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/message/template/send?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, msg, &result); err != nil {
 		return
 	}
 
@@ -95,3 +252,75 @@ func (clt *Client) SendTemplateMessage(msg *TemplateMessage) (msgid int64, err e
 	msgid = result.MsgId
 	return
 }
+
+// 账号后台的模板
+type TemplateInfo struct {
+	TemplateId      string `json:"template_id"`      // 模板ID
+	Title           string `json:"title"`            // 模板标题
+	PrimaryIndustry string `json:"primary_industry"` // 模板所属行业的一级行业
+	DeputyIndustry  string `json:"deputy_industry"`  // 模板所属行业的二级行业
+	Content         string `json:"content"`          // 模板内容, 参数的格式为 "{{first.DATA}}"
+	Example         string `json:"example"`          // 模板示例
+}
+
+// 模板内容里的所有参数名, 参考 ParseTemplatePlaceholders.
+func (info *TemplateInfo) Placeholders() []string {
+	return ParseTemplatePlaceholders(info.Content)
+}
+
+// 检查 data 是否设置了模板的所有参数, 参考 CheckTemplateData.
+func (info *TemplateInfo) CheckData(data TemplateData) error {
+	return CheckTemplateData(info.Content, data)
+}
+
+// 获取账号后台的所有模板, 同时记录模板内容, 之后 SendTemplateMessage 发送前据此检查参数.
+func (clt *Client) GetAllPrivateTemplate() (templates []TemplateInfo, err error) {
+	return clt.GetAllPrivateTemplateContext(context.Background())
+}
+
+func (clt *Client) GetAllPrivateTemplateContext(ctx context.Context) (templates []TemplateInfo, err error) {
+	var result struct {
+		Error
+		TemplateList []TemplateInfo `json:"template_list"`
+	}
+
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/template/get_all_private_template?access_token="
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != ErrCodeOK {
+		err = &result.Error
+		return
+	}
+	templates = result.TemplateList
+	clt.templates.reset(templates)
+	return
+}
+
+// 删除账号后台的模板.
+func (clt *Client) DeleteTemplate(templateId string) (err error) {
+	return clt.DeleteTemplateContext(context.Background(), templateId)
+}
+
+func (clt *Client) DeleteTemplateContext(ctx context.Context, templateId string) (err error) {
+	var request = struct {
+		TemplateId string `json:"template_id"`
+	}{
+		TemplateId: templateId,
+	}
+
+	var result Error
+
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/template/del_private_template?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
+		return
+	}
+
+	if result.ErrCode != ErrCodeOK {
+		err = &result
+		return
+	}
+	clt.templates.remove(templateId)
+	return
+}
@@ -0,0 +1,273 @@
+package mp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTemplateBatchWorkers = 8   // 默认的并发数
+	DefaultTemplateBatchQPS     = 100 // 默认每秒最多发送的消息个数
+)
+
+// 批量发送模板消息里一条消息的结果
+type TemplateBatchResult struct {
+	Index  int64  // 消息的序号, 从 0 开始, 按照从 channel 里读取的顺序
+	ToUser string // 接受者OpenID
+	MsgId  int64  // 发送成功时的消息id
+	Err    error  // 发送失败的错误
+}
+
+// 批量发送模板消息的报告
+type TemplateBatchReport struct {
+	Total     int                   // 处理的消息个数, 不包括 checkpoint 之前跳过的消息
+	Succeeded int                   // 发送成功的消息个数
+	Failed    int                   // 发送失败的消息个数
+	Results   []TemplateBatchResult // 每条消息的结果, 按照完成的顺序
+
+	// 序号小于 Checkpoint 的消息都已经处理完(成功或者失败), 下次调用 Send 时传入这个值可以接着发送;
+	// 因为并发发送, 序号大于等于 Checkpoint 的消息也可能已经处理过, 这些消息会再次发送.
+	Checkpoint int64
+}
+
+// 发送失败的消息的结果
+func (report *TemplateBatchReport) FailedResults() (results []TemplateBatchResult) {
+	for _, result := range report.Results {
+		if result.Err != nil {
+			results = append(results, result)
+		}
+	}
+	return
+}
+
+// 批量发送模板消息, 用法:
+//
+//    sender := mp.NewTemplateBatchSender(clt)
+//    sender.SetQPS(200)
+//    sender.SetProgressHandler(func(result *mp.TemplateBatchResult, checkpoint int64) {
+//        saveCheckpoint(checkpoint) // 保存进度, 中断后可以从这里继续
+//    })
+//    report, err := sender.Send(ctx, msgs, loadCheckpoint())
+//
+//  NOTE:
+//  1. 多个 worker 并发调用 SendTemplateMessage, 同时限制每秒发送的消息个数;
+//  2. 遇到 45009(接口调用超过限制)时, 默认停止发送, Send 返回 ErrAPIQuotaExceeded;
+//     SetQuotaPause 设置了暂停时间时, 暂停所有 worker, 之后重新发送这条消息;
+//  3. 其他错误记录在这条消息的结果里, 继续发送后面的消息.
+type TemplateBatchSender struct {
+	clt *Client
+
+	workers         int
+	qps             float64
+	quotaPause      time.Duration
+	progressHandler func(result *TemplateBatchResult, checkpoint int64)
+}
+
+func NewTemplateBatchSender(clt *Client) *TemplateBatchSender {
+	return &TemplateBatchSender{
+		clt:     clt,
+		workers: DefaultTemplateBatchWorkers,
+		qps:     DefaultTemplateBatchQPS,
+	}
+}
+
+// 设置并发数, n <= 0 时使用 DefaultTemplateBatchWorkers.
+func (sender *TemplateBatchSender) SetWorkers(n int) {
+	if n <= 0 {
+		n = DefaultTemplateBatchWorkers
+	}
+	sender.workers = n
+}
+
+// 设置每秒最多发送的消息个数, qps <= 0 表示不限制.
+func (sender *TemplateBatchSender) SetQPS(qps float64) {
+	sender.qps = qps
+}
+
+// 设置遇到 45009(接口调用超过限制)时暂停的时间, d <= 0 表示停止发送(默认).
+func (sender *TemplateBatchSender) SetQuotaPause(d time.Duration) {
+	sender.quotaPause = d
+}
+
+// 设置每条消息处理完后调用的函数, checkpoint 为当前的 TemplateBatchReport.Checkpoint.
+//  NOTE: 这个函数在同一个 goroutine 里按顺序调用, 不需要加锁, 但是不要阻塞太久.
+func (sender *TemplateBatchSender) SetProgressHandler(handler func(result *TemplateBatchResult, checkpoint int64)) {
+	sender.progressHandler = handler
+}
+
+type templateBatchJob struct {
+	index int64
+	msg   *TemplateMessage
+}
+
+// 发送 msgs 里的所有消息, 直到 msgs 关闭, ctx 取消或者遇到 45009.
+//  checkpoint 为上次发送返回的 TemplateBatchReport.Checkpoint, msgs 的前 checkpoint 条消息会被跳过,
+//  所以 msgs 需要和上次的顺序一致; 第一次发送时为 0.
+//
+//  返回的 report 不为 nil; ctx 取消时 err 为 ctx.Err(), 遇到 45009 停止时 err 为 ErrAPIQuotaExceeded.
+func (sender *TemplateBatchSender) Send(ctx context.Context, msgs <-chan *TemplateMessage, checkpoint int64) (report *TemplateBatchReport, err error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var tick <-chan time.Time
+	if sender.qps > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / sender.qps))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	jobs := make(chan templateBatchJob)
+	go func() {
+		defer close(jobs)
+		for index := int64(0); ; index++ {
+			var msg *TemplateMessage
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok = <-msgs:
+				if !ok {
+					return
+				}
+			}
+			if index < checkpoint {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- templateBatchJob{index: index, msg: msg}:
+			}
+		}
+	}()
+
+	var (
+		pause    templateBatchPause
+		quotaErr error
+		quotaMu  sync.Mutex
+		wg       sync.WaitGroup
+	)
+	results := make(chan TemplateBatchResult)
+	for i := 0; i < sender.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				result, done := sender.send(ctx, tick, &pause, job)
+				if !done {
+					if errors.Is(result.Err, ErrAPIQuotaExceeded) {
+						quotaMu.Lock()
+						quotaErr = result.Err
+						quotaMu.Unlock()
+						cancel()
+					}
+					continue
+				}
+				results <- result
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	report = &TemplateBatchReport{Checkpoint: checkpoint}
+	finished := make(map[int64]bool)
+	for result := range results {
+		report.Total++
+		if result.Err == nil {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+
+		finished[result.Index] = true
+		for finished[report.Checkpoint] {
+			delete(finished, report.Checkpoint)
+			report.Checkpoint++
+		}
+		if sender.progressHandler != nil {
+			sender.progressHandler(&result, report.Checkpoint)
+		}
+	}
+
+	if quotaErr != nil {
+		err = quotaErr
+		return
+	}
+	err = parent.Err()
+	return
+}
+
+// 发送一条消息, done == false 表示这条消息没有处理完(ctx 取消或者遇到 45009 停止发送), 不计入结果.
+func (sender *TemplateBatchSender) send(ctx context.Context, tick <-chan time.Time, pause *templateBatchPause, job templateBatchJob) (result TemplateBatchResult, done bool) {
+	result.Index = job.index
+	if job.msg != nil {
+		result.ToUser = job.msg.ToUser
+	}
+	for {
+		if result.Err = pause.wait(ctx, tick); result.Err != nil {
+			return
+		}
+		result.MsgId, result.Err = sender.clt.SendTemplateMessageContext(ctx, job.msg)
+		switch {
+		case errors.Is(result.Err, ErrAPIQuotaExceeded):
+			if sender.quotaPause <= 0 {
+				return
+			}
+			pause.extend(sender.quotaPause)
+		case result.Err != nil && ctx.Err() != nil:
+			return
+		default:
+			done = true
+			return
+		}
+	}
+}
+
+// 所有 worker 共享的暂停状态
+type templateBatchPause struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (p *templateBatchPause) extend(d time.Duration) {
+	until := time.Now().Add(d)
+	p.mu.Lock()
+	if until.After(p.until) {
+		p.until = until
+	}
+	p.mu.Unlock()
+}
+
+// 等待暂停结束, 然后等待 QPS 限制
+func (p *templateBatchPause) wait(ctx context.Context, tick <-chan time.Time) error {
+	for {
+		p.mu.Lock()
+		d := time.Until(p.until)
+		p.mu.Unlock()
+		if d <= 0 {
+			break
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if tick != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+		}
+	}
+	return nil
+}
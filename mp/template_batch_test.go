@@ -0,0 +1,91 @@
+package mp
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestTemplateBatchSender(t *testing.T) {
+	api := wechattest.NewServer()
+	defer api.Close()
+	clt := NewClient(api.AppId, api.AppSecret)
+	clt.SetBaseURL(api.URL, "")
+
+	const n = 30
+	for i := 0; i < n; i++ {
+		if i != 20 { // openid_20 不存在, 发送失败
+			api.SetUser("openid_"+strconv.Itoa(i), nil)
+		}
+	}
+	messages := func() <-chan *TemplateMessage {
+		ch := make(chan *TemplateMessage)
+		go func() {
+			defer close(ch)
+			for i := 0; i < n; i++ {
+				ch <- &TemplateMessage{ToUser: "openid_" + strconv.Itoa(i), TemplateId: "TEMPLATE_1", RawJSONData: []byte(`{}`)}
+			}
+		}()
+		return ch
+	}
+	path := "/cgi-bin/message/template/send"
+
+	// 遇到 45009 停止, 然后从 checkpoint 继续
+	sender := NewTemplateBatchSender(clt)
+	sender.SetWorkers(1)
+	sender.SetQPS(0)
+	api.InjectErrCode(path, ErrCodeAPIQuotaExceeded, 1)
+	report, err := sender.Send(context.Background(), messages(), 0)
+	if !errors.Is(err, ErrAPIQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrAPIQuotaExceeded", err)
+	}
+	if report.Total != 0 || report.Checkpoint != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// 处理完 10 条消息后中断
+	ctx, cancel := context.WithCancel(context.Background())
+	sender.SetProgressHandler(func(result *TemplateBatchResult, checkpoint int64) {
+		if checkpoint == 10 {
+			cancel()
+		}
+	})
+	report, err = sender.Send(ctx, messages(), 0)
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if report.Checkpoint < 10 || int64(report.Total) != report.Checkpoint {
+		t.Fatalf("report: total = %d, checkpoint = %d", report.Total, report.Checkpoint)
+	}
+	checkpoint := report.Checkpoint
+
+	// 暂停后继续发送
+	api.ResetCalls()
+	sender.SetWorkers(4)
+	sender.SetQPS(1000)
+	sender.SetQuotaPause(20 * time.Millisecond)
+	sender.SetProgressHandler(nil)
+	api.InjectErrCode(path, ErrCodeAPIQuotaExceeded, 2)
+	report, err = sender.Send(context.Background(), messages(), checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(report.Total) != n-checkpoint || report.Checkpoint != n {
+		t.Fatalf("report: total = %d, checkpoint = %d", report.Total, report.Checkpoint)
+	}
+	if got := len(api.CallsTo(path)); int64(got) != n-checkpoint+2 {
+		t.Errorf("calls = %d, want %d", got, n-checkpoint+2)
+	}
+	if failed := report.FailedResults(); len(failed) != 1 || failed[0].ToUser != "openid_20" || failed[0].Index != 20 {
+		t.Errorf("failed = %+v", failed)
+	}
+	for _, result := range report.Results {
+		if result.Err == nil && result.MsgId == 0 {
+			t.Errorf("result = %+v", result)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package mp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestTemplate(t *testing.T) {
+	api := wechattest.NewServer()
+	defer api.Close()
+	api.SetUser("openid", nil)
+	api.SetTemplate("TEMPLATE_1", "购买成功通知", "{{first.DATA}}\n商品: {{ keyword1.DATA }}\n{{remark.DATA}}")
+	clt := NewClient(api.AppId, api.AppSecret)
+	clt.SetBaseURL(api.URL, "")
+
+	if err := clt.SetIndustry(1, 4); err != nil {
+		t.Fatal(err)
+	}
+	primary, secondary, err := clt.GetIndustry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primary.FirstClass != "industry_1" || secondary.FirstClass != "industry_4" {
+		t.Errorf("industry = %+v, %+v", primary, secondary)
+	}
+
+	templates, err := clt.GetAllPrivateTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("templates = %+v", templates)
+	}
+	info := templates[0]
+	if names := info.Placeholders(); len(names) != 3 || names[0] != "first" || names[1] != "keyword1" || names[2] != "remark" {
+		t.Errorf("placeholders = %v", names)
+	}
+
+	data := TemplateData{}.Set("first", "您好", "").Set("keyword1", "<商品>", "#173177")
+	if err = info.CheckData(data); err == nil {
+		t.Error("expected error for missing remark")
+	}
+	data.Set("remark", "谢谢", "")
+	if err = info.CheckData(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// 获取过模板后, 发送前检查参数, 缺少参数时不发送
+	msg := TemplateMessage{ToUser: "openid", TemplateId: info.TemplateId}
+	if err = msg.SetData(TemplateData{}.Set("first", "您好", "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clt.SendTemplateMessage(&msg); !errors.Is(err, ErrTemplateDataIncomplete) {
+		t.Errorf("err = %v, want ErrTemplateDataIncomplete", err)
+	}
+	// data 格式错误时返回解析的错误, 不是 ErrTemplateDataIncomplete
+	badMsg := TemplateMessage{ToUser: "openid", TemplateId: info.TemplateId, RawJSONData: []byte(`["first"]`)}
+	var typeErr *json.UnmarshalTypeError
+	if _, err = clt.SendTemplateMessage(&badMsg); errors.Is(err, ErrTemplateDataIncomplete) || !errors.As(err, &typeErr) {
+		t.Errorf("err = %v, want *json.UnmarshalTypeError", err)
+	}
+	if calls := api.CallsTo("/cgi-bin/message/template/send"); len(calls) != 0 {
+		t.Errorf("calls = %+v", calls)
+	}
+
+	if err = msg.SetData(data); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"first":{"value":"您好"},"keyword1":{"value":"<商品>","color":"#173177"},"remark":{"value":"谢谢"}}`; string(msg.RawJSONData) != want {
+		t.Errorf("data = %s, want %s", msg.RawJSONData, want)
+	}
+	if _, err = clt.SendTemplateMessage(&msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = clt.DeleteTemplate(info.TemplateId); err != nil {
+		t.Fatal(err)
+	}
+	if templates, err = clt.GetAllPrivateTemplate(); err != nil || len(templates) != 0 {
+		t.Errorf("templates = %+v, err = %v", templates, err)
+	}
+}
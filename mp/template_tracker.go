@@ -0,0 +1,295 @@
+package mp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 微信服务器一般在几秒内推送 TEMPLATESENDJOBFINISH 事件, 默认记录 24 小时.
+const DefaultTemplateDeliveryTTL = 24 * time.Hour
+
+// 事件先于 Track 到达时(发送接口还没有返回 msgid), 事件在内存中等待 Track 的时间.
+const templateEarlyEventTTL = time.Minute
+
+// 一条已经发送的模板消息的送达情况
+type TemplateDelivery struct {
+	MsgId      int64             `json:"msgid"`
+	ToUser     string            `json:"touser"`
+	TemplateId string            `json:"template_id"`
+	Metadata   map[string]string `json:"metadata,omitempty"` // 调用者的附加信息, 比如订单号
+	SentAt     time.Time         `json:"sent_at"`
+
+	// 收到 TEMPLATESENDJOBFINISH 事件后设置
+	Status     string    `json:"status,omitempty"` // TemplateSendStatusSuccess 等
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// 是否送达成功
+func (delivery *TemplateDelivery) Succeeded() bool {
+	return delivery.Status == TemplateSendStatusSuccess
+}
+
+// 保存已经发送的模板消息的存储接口.
+//
+//  NOTE:
+//  1. Take 必须是原子的: 返回并且删除 msgId 对应的记录, 没有记录(或者已经过期)时返回 nil, nil;
+//  2. 多个进程(机器)发送消息和接收事件时, 可以基于 Redis 等实现这个接口.
+type TemplateDeliveryStore interface {
+	Save(delivery *TemplateDelivery, ttl time.Duration) error
+	Take(msgId int64) (delivery *TemplateDelivery, err error)
+}
+
+// 内存中的 TemplateDeliveryStore.
+type MemoryTemplateDeliveryStore struct {
+	mu         sync.Mutex
+	items      map[int64]*memoryDeliveryEntry
+	lastPurged time.Time
+}
+
+type memoryDeliveryEntry struct {
+	delivery  *TemplateDelivery
+	expiresAt time.Time
+}
+
+func NewMemoryTemplateDeliveryStore() *MemoryTemplateDeliveryStore {
+	return &MemoryTemplateDeliveryStore{
+		items:      make(map[int64]*memoryDeliveryEntry),
+		lastPurged: time.Now(),
+	}
+}
+
+func (s *MemoryTemplateDeliveryStore) Save(delivery *TemplateDelivery, ttl time.Duration) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 最多每分钟清理一次过期的记录
+	if now.Sub(s.lastPurged) >= time.Minute {
+		for msgId, entry := range s.items {
+			if !now.Before(entry.expiresAt) {
+				delete(s.items, msgId)
+			}
+		}
+		s.lastPurged = now
+	}
+	s.items[delivery.MsgId] = &memoryDeliveryEntry{delivery: delivery, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryTemplateDeliveryStore) Take(msgId int64) (delivery *TemplateDelivery, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.items[msgId]
+	if !ok {
+		return
+	}
+	delete(s.items, msgId)
+	if time.Now().Before(entry.expiresAt) {
+		delivery = entry.delivery
+	}
+	return
+}
+
+// 当前记录的消息个数(包括已经过期但是还没有被清理的).
+func (s *MemoryTemplateDeliveryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// TemplateTracker 的统计信息
+type TemplateDeliveryStats struct {
+	Sent      int64            // 记录的消息个数
+	Finished  map[string]int64 // 收到 TEMPLATESENDJOBFINISH 事件的消息个数, key 为 Status
+	Unmatched int64            // 没有找到对应记录的事件个数, 比如记录已经过期, 或者消息不是通过 TemplateTracker 发送的; 包括正在等待 Track 的事件
+}
+
+// 跟踪模板消息的送达情况, 把 TEMPLATESENDJOBFINISH 事件和发送消息时的调用关联起来, 用法:
+//
+//    tracker := mp.NewTemplateTracker(clt, nil)
+//    tracker.SetFinishHandler(func(delivery *mp.TemplateDelivery) {
+//        log.Println(delivery.Metadata["order_id"], delivery.Status)
+//    })
+//    router.HandleEvent(mp.EventTypeTemplateSendJobFinish, tracker)
+//
+//    msgid, err := tracker.Send(ctx, msg, map[string]string{"order_id": orderId})
+//
+//  NOTE:
+//  1. TemplateTracker 实现了 Handler, 处理 TEMPLATESENDJOBFINISH 事件, 不回复消息;
+//  2. 事件可能在发送接口返回 msgid(Track 保存记录)之前到达, 这样的事件在内存中保留 templateEarlyEventTTL,
+//     等 Track 保存记录时再关联; 只在同一个 TemplateTracker 里有效, 多个进程时接收事件的进程需要和发送消息的是同一个,
+//     否则这样的事件计入 Unmatched.
+type TemplateTracker struct {
+	clt           *Client
+	store         TemplateDeliveryStore
+	ttl           time.Duration
+	finishHandler func(delivery *TemplateDelivery)
+
+	mu          sync.Mutex
+	stats       TemplateDeliveryStats
+	early       map[int64]earlyTemplateEvent // msgid -> 先于 Track 到达的事件
+	earlyPurged time.Time
+}
+
+type earlyTemplateEvent struct {
+	event      *TemplateSendJobFinishEvent
+	receivedAt time.Time
+}
+
+// store 为 nil 时使用 NewMemoryTemplateDeliveryStore().
+//  clt 只用于 Send, 只调用 Track 时可以为 nil.
+func NewTemplateTracker(clt *Client, store TemplateDeliveryStore) *TemplateTracker {
+	if store == nil {
+		store = NewMemoryTemplateDeliveryStore()
+	}
+	return &TemplateTracker{
+		clt:   clt,
+		store: store,
+		ttl:   DefaultTemplateDeliveryTTL,
+		stats: TemplateDeliveryStats{Finished: make(map[string]int64)},
+		early: make(map[int64]earlyTemplateEvent),
+	}
+}
+
+// 设置记录保存的时间, d <= 0 时使用 DefaultTemplateDeliveryTTL.
+func (t *TemplateTracker) SetTTL(d time.Duration) {
+	if d <= 0 {
+		d = DefaultTemplateDeliveryTTL
+	}
+	t.ttl = d
+}
+
+// 设置收到 TEMPLATESENDJOBFINISH 事件并且找到对应记录后调用的函数, 需要 channel 时可以在这个函数里发送.
+//  NOTE: 这个函数在处理事件的 goroutine 里调用, 可能并发调用.
+func (t *TemplateTracker) SetFinishHandler(handler func(delivery *TemplateDelivery)) {
+	t.finishHandler = handler
+}
+
+// 发送模板消息并记录, metadata 为调用者的附加信息, 可以为 nil.
+func (t *TemplateTracker) Send(ctx context.Context, msg *TemplateMessage, metadata map[string]string) (msgid int64, err error) {
+	if msgid, err = t.clt.SendTemplateMessageContext(ctx, msg); err != nil {
+		return
+	}
+	err = t.Track(msgid, msg, metadata)
+	return
+}
+
+// 记录通过其他方式(比如 TemplateBatchSender)发送的模板消息.
+func (t *TemplateTracker) Track(msgid int64, msg *TemplateMessage, metadata map[string]string) (err error) {
+	delivery := &TemplateDelivery{
+		MsgId:    msgid,
+		Metadata: metadata,
+		SentAt:   time.Now(),
+	}
+	if msg != nil {
+		delivery.ToUser = msg.ToUser
+		delivery.TemplateId = msg.TemplateId
+	}
+	if err = t.store.Save(delivery, t.ttl); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.stats.Sent++
+	early, ok := t.takeEarly(msgid)
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	// 事件先到了, 取回刚保存的记录; 取不到说明 HandleEvent 已经处理了
+	if delivery, err = t.store.Take(msgid); err != nil || delivery == nil {
+		return
+	}
+	t.finish(delivery, early.event)
+	return
+}
+
+// 处理 TEMPLATESENDJOBFINISH 事件, 返回对应的记录; 没有找到记录时返回 nil, nil.
+func (t *TemplateTracker) HandleEvent(event *TemplateSendJobFinishEvent) (delivery *TemplateDelivery, err error) {
+	if delivery, err = t.store.Take(event.MsgId); err != nil {
+		return
+	}
+	if delivery == nil {
+		// 可能 Track 还没有保存记录: 先保留事件, 再检查一次, 避免和 Track 同时执行时两边都没有关联上
+		t.mu.Lock()
+		t.stats.Unmatched++
+		t.addEarly(event)
+		t.mu.Unlock()
+
+		if delivery, err = t.store.Take(event.MsgId); err != nil || delivery == nil {
+			return
+		}
+		t.mu.Lock()
+		t.takeEarly(event.MsgId)
+		t.mu.Unlock()
+	}
+	t.finish(delivery, event)
+	return
+}
+
+func (t *TemplateTracker) finish(delivery *TemplateDelivery, event *TemplateSendJobFinishEvent) {
+	t.mu.Lock()
+	t.stats.Finished[event.Status]++
+	t.mu.Unlock()
+
+	delivery.Status = event.Status
+	delivery.FinishedAt = time.Unix(event.CreateTime, 0)
+	if t.finishHandler != nil {
+		t.finishHandler(delivery)
+	}
+}
+
+// 保留先于 Track 到达的事件, 调用者持有 t.mu.
+func (t *TemplateTracker) addEarly(event *TemplateSendJobFinishEvent) {
+	now := time.Now()
+	if now.Sub(t.earlyPurged) >= templateEarlyEventTTL {
+		for msgid, early := range t.early {
+			if now.Sub(early.receivedAt) >= templateEarlyEventTTL {
+				delete(t.early, msgid)
+			}
+		}
+		t.earlyPurged = now
+	}
+	t.early[event.MsgId] = earlyTemplateEvent{event: event, receivedAt: now}
+}
+
+// 取出并删除 msgid 对应的没有过期的事件, 同时把它从 Unmatched 里减掉; 调用者持有 t.mu.
+func (t *TemplateTracker) takeEarly(msgid int64) (early earlyTemplateEvent, ok bool) {
+	if early, ok = t.early[msgid]; !ok {
+		return
+	}
+	delete(t.early, msgid)
+	if time.Since(early.receivedAt) >= templateEarlyEventTTL {
+		ok = false // 过期的事件仍然算没有关联上
+		return
+	}
+	t.stats.Unmatched--
+	return
+}
+
+// 实现 Handler, 忽略其他消息(事件).
+func (t *TemplateTracker) ServeMessage(ctx context.Context, req *Request) (reply interface{}, err error) {
+	msg := req.MixedMessage
+	if msg.MsgType != MsgTypeEvent || msg.Event != EventTypeTemplateSendJobFinish {
+		return
+	}
+	_, err = t.HandleEvent(GetTemplateSendJobFinishEvent(msg))
+	return
+}
+
+// 当前的统计信息.
+func (t *TemplateTracker) Stats() (stats TemplateDeliveryStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats = t.stats
+	stats.Finished = make(map[string]int64, len(t.stats.Finished))
+	for status, n := range t.stats.Finished {
+		stats.Finished[status] = n
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package mp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/skynology/wechat/wechattest"
+)
+
+func TestTemplateTracker(t *testing.T) {
+	api := wechattest.NewServer()
+	defer api.Close()
+	api.SetUser("openid", nil)
+	clt := NewClient(api.AppId, api.AppSecret)
+	clt.SetBaseURL(api.URL, "")
+
+	finished := make(chan *TemplateDelivery, 1)
+	tracker := NewTemplateTracker(clt, nil)
+	tracker.SetFinishHandler(func(delivery *TemplateDelivery) { finished <- delivery })
+
+	msg := &TemplateMessage{ToUser: "openid", TemplateId: "TEMPLATE_1", RawJSONData: []byte(`{}`)}
+	msgid, err := tracker.Send(context.Background(), msg, map[string]string{"order_id": "123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	router.HandleEvent(EventTypeTemplateSendJobFinish, tracker)
+	for _, id := range []int64{msgid, msgid} { // 第二次为重复的事件, 找不到记录
+		event := &MixedMessage{
+			CommonMessageHeader: CommonMessageHeader{MsgType: MsgTypeEvent, CreateTime: 1395658984},
+			Event:               EventTypeTemplateSendJobFinish,
+			MsgID:               id,
+			Status:              TemplateSendStatusFailedUserBlock,
+		}
+		if _, err = router.ServeMessage(context.Background(), &Request{MixedMessage: event}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case delivery := <-finished:
+		if delivery.MsgId != msgid || delivery.Metadata["order_id"] != "123" || delivery.Succeeded() ||
+			delivery.Status != TemplateSendStatusFailedUserBlock || delivery.ToUser != "openid" {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	default:
+		t.Fatal("finish handler not called")
+	}
+
+	stats := tracker.Stats()
+	if stats.Sent != 1 || stats.Unmatched != 1 || stats.Finished[TemplateSendStatusFailedUserBlock] != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+// 事件先于 Track 到达(发送接口还没有返回), 以及事件和 Track 同时到达
+func TestTemplateTrackerEventBeforeTrack(t *testing.T) {
+	var finished int32
+	tracker := NewTemplateTracker(nil, nil)
+	tracker.SetFinishHandler(func(delivery *TemplateDelivery) {
+		if delivery.Status != TemplateSendStatusSuccess || delivery.Metadata["order_id"] != "123" {
+			t.Errorf("delivery = %+v", delivery)
+		}
+		atomic.AddInt32(&finished, 1)
+	})
+	event := func(msgid int64) *TemplateSendJobFinishEvent {
+		return &TemplateSendJobFinishEvent{MsgId: msgid, Status: TemplateSendStatusSuccess}
+	}
+	msg := &TemplateMessage{ToUser: "openid", TemplateId: "TEMPLATE_1"}
+	metadata := map[string]string{"order_id": "123"}
+
+	if delivery, err := tracker.HandleEvent(event(1)); err != nil || delivery != nil {
+		t.Fatalf("HandleEvent = %+v, %v", delivery, err)
+	}
+	if err := tracker.Track(1, msg, metadata); err != nil {
+		t.Fatal(err)
+	}
+	if finished != 1 {
+		t.Fatalf("finished = %d, want 1", finished)
+	}
+
+	const n = 200
+	var wg sync.WaitGroup
+	for i := int64(2); i < n+2; i++ {
+		wg.Add(2)
+		go func(msgid int64) {
+			defer wg.Done()
+			if _, err := tracker.HandleEvent(event(msgid)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func(msgid int64) {
+			defer wg.Done()
+			if err := tracker.Track(msgid, msg, metadata); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if finished != n+1 {
+		t.Errorf("finished = %d, want %d", finished, n+1)
+	}
+	stats := tracker.Stats()
+	if stats.Sent != n+1 || stats.Unmatched != 0 || stats.Finished[TemplateSendStatusSuccess] != n+1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -1,6 +1,7 @@
 package mp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -80,6 +81,10 @@ var ErrUserNotSubscriber = errors.New("用户没有订阅公众号")
 // 获取用户基本信息, 如果用户没有订阅公众号, 返回 ErrUserNotSubscriber 错误.
 //  lang 可以是 zh_CN, zh_TW, en, 如果留空 "" 则默认为 zh_CN.
 func (clt *Client) UserInfo(openId string, lang string) (userinfo *UserInfo, err error) {
+	return clt.UserInfoContext(context.Background(), openId, lang)
+}
+
+func (clt *Client) UserInfoContext(ctx context.Context, openId string, lang string) (userinfo *UserInfo, err error) {
 	switch lang {
 	case "":
 		lang = Language_zh_CN
@@ -94,9 +99,9 @@ func (clt *Client) UserInfo(openId string, lang string) (userinfo *UserInfo, err
 		UserInfo
 	}
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/user/info?openid=" + url.QueryEscape(openId) +
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/info?openid=" + url.QueryEscape(openId) +
 		"&lang=" + url.QueryEscape(lang) + "&access_token="
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
@@ -115,6 +120,10 @@ func (clt *Client) UserInfo(openId string, lang string) (userinfo *UserInfo, err
 // 开发者可以通过该接口对指定用户设置备注名.
 //  NOTE: 该接口暂时开放给微信认证的服务号.
 func (clt *Client) UserUpdateRemark(openId, remark string) (err error) {
+	return clt.UserUpdateRemarkContext(context.Background(), openId, remark)
+}
+
+func (clt *Client) UserUpdateRemarkContext(ctx context.Context, openId, remark string) (err error) {
 	var request = struct {
 		OpenId string `json:"openid"`
 		Remark string `json:"remark"`
@@ -125,8 +134,8 @@ func (clt *Client) UserUpdateRemark(openId, remark string) (err error) {
 
 	var result Error
 
-	incompleteURL := "https://api.weixin.qq.com/cgi-bin/user/info/updateremark?access_token="
-	if err = clt.PostJSON(incompleteURL, &request, &result); err != nil {
+	incompleteURL := clt.apiBaseURL + "/cgi-bin/user/info/updateremark?access_token="
+	if err = clt.PostJSONContext(ctx, incompleteURL, &request, &result); err != nil {
 		return
 	}
 
@@ -152,6 +161,10 @@ type UserListResult struct {
 
 // 获取关注者列表, 每次最多能获取 10000 个用户, 如果 beginOpenId == "" 则表示从头获取
 func (clt *Client) UserList(beginOpenId string) (data *UserListResult, err error) {
+	return clt.UserListContext(context.Background(), beginOpenId)
+}
+
+func (clt *Client) UserListContext(ctx context.Context, beginOpenId string) (data *UserListResult, err error) {
 	var result struct {
 		Error
 		UserListResult
@@ -159,13 +172,13 @@ func (clt *Client) UserList(beginOpenId string) (data *UserListResult, err error
 
 	var incompleteURL string
 	if beginOpenId == "" {
-		incompleteURL = "https://api.weixin.qq.com/cgi-bin/user/get?access_token="
+		incompleteURL = clt.apiBaseURL + "/cgi-bin/user/get?access_token="
 	} else {
-		incompleteURL = "https://api.weixin.qq.com/cgi-bin/user/get?next_openid=" + url.QueryEscape(beginOpenId) +
+		incompleteURL = clt.apiBaseURL + "/cgi-bin/user/get?next_openid=" + url.QueryEscape(beginOpenId) +
 			"&access_token="
 	}
 
-	if err = clt.GetJSON(incompleteURL, &result); err != nil {
+	if err = clt.GetJSONContext(ctx, incompleteURL, &result); err != nil {
 		return
 	}
 
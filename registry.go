@@ -0,0 +1,397 @@
+package wechat
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/skynology/wechat/corp"
+	"github.com/skynology/wechat/mch/pay"
+	"github.com/skynology/wechat/mp"
+	"github.com/skynology/wechat/util"
+)
+
+// 公众号账号信息
+type MPAccount struct {
+	AppId     string
+	AppSecret string
+}
+
+// 企业号账号信息
+type CorpAccount struct {
+	CorpId string
+	Secret string // 管理组的凭证密钥
+}
+
+// 微信支付商户信息
+type PayAccount struct {
+	AppId  string
+	MchId  string
+	APIKey string
+
+	// 商户 API 证书, 申请退款, 企业付款等接口需要双向证书; 为 nil 时使用 Registry 共享的 http.Client
+	Certificate *tls.Certificate
+}
+
+// 根据 appid/corpid/mchid 加载账号信息, 比如从数据库或者配置中心读取.
+//  账号不存在时应该返回 ErrAccountNotFound(或者包装了它的错误).
+//  Registry 不会为同一个 id 并发地调用同一个方法.
+type Loader interface {
+	LoadMP(appId string) (MPAccount, error)
+	LoadCorp(corpId string) (CorpAccount, error)
+	LoadPay(mchId string) (PayAccount, error)
+}
+
+var ErrAccountNotFound = errors.New("wechat: account not found")
+
+// 多账号的 Client 注册表.
+//  第一次获取某个账号的 Client 时通过 Loader 加载账号信息并创建 Client, 之后直接返回缓存的 Client;
+//  所有 Client 共享同一个 http.Client(连接池), TokenStore, 重试策略和拦截器.
+//
+//  NOTE:
+//  1. 共享的设置(SetHttpClient 等)应该在获取 Client 之前完成, 只对之后创建的 Client 生效;
+//  2. 账号的 secret 变更后调用 ReloadMP/ReloadCorp/ReloadPay, 如果账号信息有变化则创建新的 Client 替换旧的,
+//     已经拿到旧 Client 的调用者不受影响, 但是之后获取到的是新的 Client;
+//     Reload 返回的总是调用 Reload 之后开始加载的账号信息, 不会使用之前开始的加载的结果.
+type Registry struct {
+	loader Loader
+
+	httpClient   *http.Client
+	tokenStore   util.TokenStore
+	retryPolicy  *util.RetryPolicy
+	interceptors []util.Interceptor
+
+	mu          sync.RWMutex
+	mpClients   map[string]*mpEntry
+	corpClients map[string]*corpEntry
+	payClients  map[string]*payEntry
+
+	loadGroup util.SingleFlight // 合并同一个账号并发的加载, 同一个账号同时只有一个加载在执行
+	gens      map[string]uint64 // "mp:"+appId 等 -> 调用 Reload 的次数, 用于区分加载是在 Reload 之前还是之后开始的
+}
+
+// 一次加载的结果, gen 为开始加载时的 Registry.gens[key]
+type loadResult struct {
+	client interface{}
+	gen    uint64
+}
+
+type mpEntry struct {
+	account MPAccount
+	client  *mp.Client
+	gen     uint64 // 加载账号信息时的 Registry.gens[key]
+}
+
+type corpEntry struct {
+	account CorpAccount
+	client  *corp.Client
+	gen     uint64 // 加载账号信息时的 Registry.gens[key]
+}
+
+type payEntry struct {
+	account PayAccount
+	client  *pay.Client
+	gen     uint64 // 加载账号信息时的 Registry.gens[key]
+}
+
+func NewRegistry(loader Loader) *Registry {
+	return &Registry{
+		loader:      loader,
+		httpClient:  http.DefaultClient,
+		tokenStore:  util.NewMemoryTokenStore(),
+		mpClients:   make(map[string]*mpEntry),
+		corpClients: make(map[string]*corpEntry),
+		payClients:  make(map[string]*payEntry),
+		gens:        make(map[string]uint64),
+	}
+}
+
+// 设置所有 Client 共享的 http.Client, 默认为 http.DefaultClient.
+func (r *Registry) SetHttpClient(httpClient *http.Client) {
+	r.httpClient = httpClient
+}
+
+// 设置所有 mp.Client, corp.Client 共享的 TokenStore, 默认存储在当前进程的内存中.
+func (r *Registry) SetTokenStore(store util.TokenStore) {
+	r.tokenStore = store
+}
+
+// 设置所有 Client 的重试策略, 默认不重试.
+func (r *Registry) SetRetryPolicy(policy *util.RetryPolicy) {
+	r.retryPolicy = policy
+}
+
+// 设置所有 Client 的拦截器.
+func (r *Registry) SetInterceptors(interceptors ...util.Interceptor) {
+	r.interceptors = interceptors
+}
+
+// 获取公众号 appId 的 Client.
+func (r *Registry) MP(appId string) (clt *mp.Client, err error) {
+	r.mu.RLock()
+	entry := r.mpClients[appId]
+	r.mu.RUnlock()
+	if entry != nil {
+		return entry.client, nil
+	}
+
+	v, err := r.load("mp:"+appId, 0, func(gen uint64) (interface{}, error) {
+		return r.loadMP(appId, gen)
+	})
+	if err != nil {
+		return
+	}
+	return v.(*mp.Client), nil
+}
+
+// 重新加载公众号 appId 的账号信息, 账号信息有变化(比如 AppSecret 重置了)时替换缓存的 Client.
+func (r *Registry) ReloadMP(appId string) (clt *mp.Client, err error) {
+	key := "mp:" + appId
+	v, err := r.load(key, r.nextGen(key), func(gen uint64) (interface{}, error) {
+		return r.loadMP(appId, gen)
+	})
+	if err != nil {
+		return
+	}
+	return v.(*mp.Client), nil
+}
+
+// 加载 appId 的账号信息并创建 Client, 缓存的 Client 是在 gen 之后加载的则直接返回它.
+func (r *Registry) loadMP(appId string, gen uint64) (clt *mp.Client, err error) {
+	r.mu.RLock()
+	entry := r.mpClients[appId]
+	r.mu.RUnlock()
+	if entry != nil && entry.gen >= gen {
+		return entry.client, nil
+	}
+
+	account, err := r.loader.LoadMP(appId)
+	if err != nil {
+		return
+	}
+	if entry != nil && entry.account == account {
+		r.mu.Lock()
+		entry.gen = gen
+		r.mu.Unlock()
+		return entry.client, nil
+	}
+
+	clt = mp.NewClient(account.AppId, account.AppSecret)
+	clt.SetHttpClient(r.httpClient)
+	clt.SetTokenStore(r.tokenStore)
+	clt.SetRetryPolicy(r.retryPolicy)
+	clt.SetInterceptors(r.interceptors...)
+
+	r.mu.Lock()
+	r.mpClients[appId] = &mpEntry{account: account, client: clt, gen: gen}
+	r.mu.Unlock()
+	return
+}
+
+// 获取企业号 corpId 的 Client.
+func (r *Registry) Corp(corpId string) (clt *corp.Client, err error) {
+	r.mu.RLock()
+	entry := r.corpClients[corpId]
+	r.mu.RUnlock()
+	if entry != nil {
+		return entry.client, nil
+	}
+
+	v, err := r.load("corp:"+corpId, 0, func(gen uint64) (interface{}, error) {
+		return r.loadCorp(corpId, gen)
+	})
+	if err != nil {
+		return
+	}
+	return v.(*corp.Client), nil
+}
+
+// 重新加载企业号 corpId 的账号信息, 账号信息有变化(比如 Secret 重置了)时替换缓存的 Client.
+func (r *Registry) ReloadCorp(corpId string) (clt *corp.Client, err error) {
+	key := "corp:" + corpId
+	v, err := r.load(key, r.nextGen(key), func(gen uint64) (interface{}, error) {
+		return r.loadCorp(corpId, gen)
+	})
+	if err != nil {
+		return
+	}
+	return v.(*corp.Client), nil
+}
+
+// 加载 corpId 的账号信息并创建 Client, 缓存的 Client 是在 gen 之后加载的则直接返回它.
+func (r *Registry) loadCorp(corpId string, gen uint64) (clt *corp.Client, err error) {
+	r.mu.RLock()
+	entry := r.corpClients[corpId]
+	r.mu.RUnlock()
+	if entry != nil && entry.gen >= gen {
+		return entry.client, nil
+	}
+
+	account, err := r.loader.LoadCorp(corpId)
+	if err != nil {
+		return
+	}
+	if entry != nil && entry.account == account {
+		r.mu.Lock()
+		entry.gen = gen
+		r.mu.Unlock()
+		return entry.client, nil
+	}
+
+	clt = corp.NewClient(account.CorpId, account.Secret)
+	clt.SetHttpClient(r.httpClient)
+	clt.SetTokenStore(r.tokenStore)
+	clt.SetRetryPolicy(r.retryPolicy)
+	clt.SetInterceptors(r.interceptors...)
+
+	r.mu.Lock()
+	r.corpClients[corpId] = &corpEntry{account: account, client: clt, gen: gen}
+	r.mu.Unlock()
+	return
+}
+
+// 获取微信支付商户 mchId 的 Client.
+func (r *Registry) Pay(mchId string) (clt *pay.Client, err error) {
+	r.mu.RLock()
+	entry := r.payClients[mchId]
+	r.mu.RUnlock()
+	if entry != nil {
+		return entry.client, nil
+	}
+
+	v, err := r.load("pay:"+mchId, 0, func(gen uint64) (interface{}, error) {
+		return r.loadPay(mchId, gen)
+	})
+	if err != nil {
+		return
+	}
+	return v.(*pay.Client), nil
+}
+
+// 重新加载微信支付商户 mchId 的信息, 商户信息有变化(比如 API 密钥, 证书更换了)时替换缓存的 Client.
+func (r *Registry) ReloadPay(mchId string) (clt *pay.Client, err error) {
+	key := "pay:" + mchId
+	v, err := r.load(key, r.nextGen(key), func(gen uint64) (interface{}, error) {
+		return r.loadPay(mchId, gen)
+	})
+	if err != nil {
+		return
+	}
+	return v.(*pay.Client), nil
+}
+
+// 加载 mchId 的账号信息并创建 Client, 缓存的 Client 是在 gen 之后加载的则直接返回它.
+func (r *Registry) loadPay(mchId string, gen uint64) (clt *pay.Client, err error) {
+	r.mu.RLock()
+	entry := r.payClients[mchId]
+	r.mu.RUnlock()
+	if entry != nil && entry.gen >= gen {
+		return entry.client, nil
+	}
+
+	account, err := r.loader.LoadPay(mchId)
+	if err != nil {
+		return
+	}
+	if entry != nil && samePayAccount(entry.account, account) {
+		r.mu.Lock()
+		entry.gen = gen
+		r.mu.Unlock()
+		return entry.client, nil
+	}
+
+	clt = pay.NewClient(account.AppId, account.MchId, account.APIKey)
+	if account.Certificate != nil {
+		clt.SetHttpClient(tlsHttpClient(r.httpClient, account.Certificate))
+	} else {
+		clt.SetHttpClient(r.httpClient)
+	}
+	clt.SetRetryPolicy(r.retryPolicy)
+	clt.SetInterceptors(r.interceptors...)
+
+	r.mu.Lock()
+	r.payClients[mchId] = &payEntry{account: account, client: clt, gen: gen}
+	r.mu.Unlock()
+	return
+}
+
+// 增加 key 的 gen, 返回增加后的值.
+func (r *Registry) nextGen(key string) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gens[key]++
+	return r.gens[key]
+}
+
+// 通过 loadGroup 调用 load, 同一个 key 同时只有一个 load 在执行.
+//  minGen 为 nextGen 的返回值(Reload)时, 如果合并到了 Reload 之前开始的加载, 等它结束后重新加载,
+//  所以返回的一定是 Reload 之后开始的加载的结果; 获取 Client 时 minGen 为 0, 接受任何加载的结果.
+func (r *Registry) load(key string, minGen uint64, load func(gen uint64) (interface{}, error)) (clt interface{}, err error) {
+	for {
+		var v interface{}
+		v, err = r.loadGroup.Do(key, func() (interface{}, error) {
+			r.mu.RLock()
+			gen := r.gens[key]
+			r.mu.RUnlock()
+			clt, err := load(gen)
+			return &loadResult{client: clt, gen: gen}, err
+		})
+		result, _ := v.(*loadResult)
+		if result != nil && result.gen < minGen {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		return result.client, nil
+	}
+}
+
+// 从缓存中删除账号的 Client, 下次获取时重新加载.
+func (r *Registry) Remove(id string) {
+	r.mu.Lock()
+	delete(r.mpClients, id)
+	delete(r.corpClients, id)
+	delete(r.payClients, id)
+	r.mu.Unlock()
+}
+
+func samePayAccount(a, b PayAccount) bool {
+	if a.AppId != b.AppId || a.MchId != b.MchId || a.APIKey != b.APIKey {
+		return false
+	}
+	if a.Certificate == nil || b.Certificate == nil {
+		return a.Certificate == b.Certificate
+	}
+	if len(a.Certificate.Certificate) != len(b.Certificate.Certificate) {
+		return false
+	}
+	for i := range a.Certificate.Certificate {
+		if string(a.Certificate.Certificate[i]) != string(b.Certificate.Certificate[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// 基于共享的 http.Client 创建使用商户证书的 http.Client, 证书不同不能共享连接池.
+func tlsHttpClient(shared *http.Client, cert *tls.Certificate) *http.Client {
+	var transport *http.Transport
+	if t, ok := shared.Transport.(*http.Transport); ok {
+		transport = t.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{*cert}
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: shared.CheckRedirect,
+		Jar:           shared.Jar,
+		Timeout:       shared.Timeout,
+	}
+}
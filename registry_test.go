@@ -0,0 +1,166 @@
+package wechat
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skynology/wechat/mp"
+)
+
+type testLoader struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	loads   int32
+	onLoad  func(n int32) // 第 n 次 LoadMP 读取账号信息之后调用, 可以为 nil
+
+	inFlight    int32 // 正在执行的 LoadMP 的个数
+	maxInFlight int32
+}
+
+func (l *testLoader) LoadMP(appId string) (account MPAccount, err error) {
+	n := atomic.AddInt32(&l.loads, 1)
+	l.mu.Lock()
+	if l.inFlight++; l.inFlight > l.maxInFlight {
+		l.maxInFlight = l.inFlight
+	}
+	secret, ok := l.secrets[appId]
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.inFlight--
+		l.mu.Unlock()
+	}()
+
+	if l.onLoad != nil {
+		l.onLoad(n)
+	}
+	if !ok {
+		err = ErrAccountNotFound
+		return
+	}
+	return MPAccount{AppId: appId, AppSecret: secret}, nil
+}
+
+func (l *testLoader) LoadCorp(corpId string) (CorpAccount, error) {
+	return CorpAccount{}, ErrAccountNotFound
+}
+
+func (l *testLoader) LoadPay(mchId string) (PayAccount, error) {
+	return PayAccount{}, ErrAccountNotFound
+}
+
+func TestRegistryMP(t *testing.T) {
+	loader := &testLoader{secrets: map[string]string{"wx1": "secret1"}}
+	registry := NewRegistry(loader)
+
+	const n = 32
+	clients := make([]interface{}, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clt, err := registry.MP("wx1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			clients[i] = clt
+		}(i)
+	}
+	wg.Wait()
+	for i := 1; i < n; i++ {
+		if clients[i] != clients[0] {
+			t.Fatal("同一个 appid 应该返回同一个 Client")
+		}
+	}
+	if loader.loads != 1 {
+		t.Errorf("want 1 load, have %d", loader.loads)
+	}
+
+	// 账号信息没有变化, 保留原来的 Client
+	clt, err := registry.ReloadMP("wx1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clt != clients[0] {
+		t.Error("账号信息没有变化时不应该替换 Client")
+	}
+
+	// secret 变更后替换 Client
+	loader.mu.Lock()
+	loader.secrets["wx1"] = "secret2"
+	loader.mu.Unlock()
+	if clt, err = registry.ReloadMP("wx1"); err != nil {
+		t.Fatal(err)
+	}
+	if clt == clients[0] {
+		t.Error("secret 变更后应该替换 Client")
+	}
+	if clt2, _ := registry.MP("wx1"); clt2 != clt {
+		t.Error("Reload 之后应该返回新的 Client")
+	}
+
+	if _, err = registry.MP("wx2"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("want ErrAccountNotFound, have %v", err)
+	}
+}
+
+func TestRegistryReloadDuringLoad(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	loader := &testLoader{
+		secrets: map[string]string{"wx1": "secret1"},
+		onLoad: func(n int32) {
+			if n == 1 {
+				close(started)
+				<-release
+			}
+		},
+	}
+	registry := NewRegistry(loader)
+
+	// 第一次加载读到旧的 secret 之后, secret 变更了并且调用了 Reload
+	loaded := make(chan *mp.Client)
+	go func() {
+		clt, _ := registry.MP("wx1")
+		loaded <- clt
+	}()
+	<-started
+	loader.mu.Lock()
+	loader.secrets["wx1"] = "secret2"
+	loader.mu.Unlock()
+
+	reloaded := make(chan *mp.Client)
+	for i := 0; i < 2; i++ {
+		go func() {
+			clt, err := registry.ReloadMP("wx1")
+			if err != nil {
+				t.Error(err)
+			}
+			reloaded <- clt
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // 让 Reload 合并到正在进行的加载
+	close(release)
+
+	old := <-loaded
+	clt1, clt2 := <-reloaded, <-reloaded
+	if clt1 == old || clt2 == old {
+		t.Error("Reload 不应该返回 Reload 之前开始的加载的结果")
+	}
+	registry.mu.RLock()
+	entry := registry.mpClients["wx1"]
+	registry.mu.RUnlock()
+	if entry.account.AppSecret != "secret2" || entry.client != clt1 || entry.client != clt2 {
+		t.Errorf("entry = %+v", entry.account)
+	}
+	if clt, _ := registry.MP("wx1"); clt != entry.client {
+		t.Error("Reload 之后应该返回新的 Client")
+	}
+	if loader.maxInFlight != 1 {
+		t.Errorf("同一个 appid 的 LoadMP 不应该并发, maxInFlight = %d", loader.maxInFlight)
+	}
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"fmt"
+)
+
+// AES-ECB 加密, PKCS#7 补位, 比如微信支付退款结果通知的 req_info.
+//  key 的长度为 16, 24 或者 32, 分别对应 AES-128, AES-192 和 AES-256.
+func AESECBEncrypt(plain, key []byte) (encrypted []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+
+	// PKCS#7 补位
+	amountToPad := aes.BlockSize - len(plain)%aes.BlockSize
+	encrypted = make([]byte, len(plain)+amountToPad)
+	copy(encrypted, plain)
+	copy(encrypted[len(plain):], bytes.Repeat([]byte{byte(amountToPad)}, amountToPad))
+
+	for i := 0; i < len(encrypted); i += aes.BlockSize {
+		block.Encrypt(encrypted[i:i+aes.BlockSize], encrypted[i:i+aes.BlockSize])
+	}
+	return
+}
+
+// AES-ECB 解密, 去除 PKCS#7 补位.
+func AESECBDecrypt(encrypted, key []byte) (plain []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		err = fmt.Errorf("encrypted is not a multiple of the block size, the length is %d", len(encrypted))
+		return
+	}
+
+	plain = make([]byte, len(encrypted))
+	for i := 0; i < len(encrypted); i += aes.BlockSize {
+		block.Decrypt(plain[i:i+aes.BlockSize], encrypted[i:i+aes.BlockSize])
+	}
+
+	// PKCS#7 去除补位
+	amountToPad := int(plain[len(plain)-1])
+	if amountToPad < 1 || amountToPad > aes.BlockSize {
+		err = fmt.Errorf("the amount to pad is invalid: %d", amountToPad)
+		plain = nil
+		return
+	}
+	for _, b := range plain[len(plain)-amountToPad:] {
+		if int(b) != amountToPad {
+			err = fmt.Errorf("the amount to pad is invalid: %d", amountToPad)
+			plain = nil
+			return
+		}
+	}
+	plain = plain[:len(plain)-amountToPad]
+	return
+}
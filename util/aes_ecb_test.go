@@ -0,0 +1,33 @@
+package util
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestAESECB(t *testing.T) {
+	// FIPS-197 附录 C.3 的 AES-256 测试向量
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	plain, _ := hex.DecodeString("00112233445566778899aabbccddeeff")
+	want := "8ea2b7ca516745bfeafc49904b496089"
+
+	encrypted, err := AESECBEncrypt(plain, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encrypted) != 32 || hex.EncodeToString(encrypted[:16]) != want {
+		t.Fatalf("encrypted = %x", encrypted)
+	}
+	decrypted, err := AESECBDecrypt(encrypted, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plain) {
+		t.Errorf("decrypted = %x", decrypted)
+	}
+
+	if _, err = AESECBDecrypt(encrypted[:16], key); err == nil {
+		t.Error("want padding error")
+	}
+}
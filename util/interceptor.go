@@ -0,0 +1,202 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 一次请求微信服务器的信息, 拦截器通过它观察请求和响应.
+type Call struct {
+	Method string // GET, POST
+	Path   string // 接口的路径, 如 "/cgi-bin/tags/get", 不包含查询参数
+	URL    string // 完整的 URL, access_token, secret 等敏感参数已经替换为 "***"
+
+	RequestBody []byte // 请求的 body, 上传文件等流式的 body 为 nil
+
+	// 下面的字段在请求完成后才有效
+	StatusCode   int           // http 状态码, 网络错误时为 0
+	ResponseBody []byte        // 响应的 body, 只保存 JSON, XML 和文本, 下载的文件等为 nil; 记录前用 RedactBody 去掉凭证
+	ErrCode      string        // 微信返回的错误码: errcode(非 0 时), 或者支付接口的 err_code, return_code(非 SUCCESS 时)
+	Latency      time.Duration // 从发送请求到读完响应 body 的时间
+	Err          error         // 网络错误等, *url.Error 的 URL 已经用 RedactError 处理过
+}
+
+// 拦截器, 包裹每一个发往微信服务器的请求.
+//  调用 next 发送请求(或者交给下一个拦截器), 可以在 next 前后记录日志, 统计指标,
+//  或者修改传给 next 的 ctx (比如开始一个 trace span); 不调用 next 直接返回错误则终止这个请求.
+type Interceptor func(ctx context.Context, call *Call, next func(ctx context.Context) error) error
+
+// 被替换为 "***" 的查询参数; code, js_code 可以换取 access_token, session_key 等, 同样需要隐藏
+var redactedQueryKeys = []string{"access_token", "refresh_token", "secret", "corpsecret", "code", "js_code"}
+
+// 被替换为 "***" 的 JSON 字段, 比如 /cgi-bin/token, /sns/oauth2/access_token, /cgi-bin/ticket/getticket 的返回
+var redactedBodyKeys = []string{"access_token", "refresh_token", "session_key", "ticket", "secret", "corpsecret"}
+
+var redactedBodyRegexp = regexp.MustCompile(`"(` + strings.Join(redactedBodyKeys, "|") + `)"(\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// 返回 body 的副本, JSON 里 access_token, refresh_token 等凭证字段的值替换为 "***".
+//  NOTE: 只处理值为字符串的字段, 没有凭证时返回 body 本身.
+func RedactBody(body []byte) []byte {
+	if !redactedBodyRegexp.Match(body) {
+		return body
+	}
+	return redactedBodyRegexp.ReplaceAll(body, []byte(`"$1"$2"***"`))
+}
+
+// 返回 u 的字符串形式, access_token, secret 等敏感参数替换为 "***".
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	query := u.Query()
+	redacted := false
+	for _, key := range redactedQueryKeys {
+		if _, ok := query[key]; ok {
+			query.Set(key, "***")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	u2 := *u
+	u2.RawQuery = query.Encode()
+	return u2.String()
+}
+
+// 如果 err 是 http.Client 返回的 *url.Error, 返回 URL 经过 RedactURL 处理的副本, 否则返回 err 本身.
+//  *url.Error 的错误信息里有完整的请求 URL, 直接记录会泄露 access_token 等参数.
+func RedactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return err
+	}
+	redacted := RedactURL(u)
+	if redacted == urlErr.URL {
+		return err
+	}
+	return &url.Error{Op: urlErr.Op, URL: redacted, Err: urlErr.Err}
+}
+
+// 通过 interceptors 发送 httpReq, 没有拦截器时等价于 httpClient.Do(httpReq).
+//  NOTE:
+//  1. 有拦截器时, JSON, XML 和文本类型的响应 body 会被完整读入内存, 然后替换为可以重新读取的 body;
+//  2. 网络错误的 *url.Error 用 RedactError 处理过, 错误信息里不包含 access_token 等参数.
+func DoHTTP(httpClient *http.Client, interceptors []Interceptor, httpReq *http.Request) (httpResp *http.Response, err error) {
+	if len(interceptors) == 0 {
+		httpResp, err = httpClient.Do(httpReq)
+		err = RedactError(err)
+		return
+	}
+
+	call := &Call{
+		Method: httpReq.Method,
+		Path:   httpReq.URL.Path,
+		URL:    RedactURL(httpReq.URL),
+	}
+	if httpReq.GetBody != nil {
+		if body, err := httpReq.GetBody(); err == nil {
+			call.RequestBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	invoke := func(ctx context.Context) (err error) {
+		start := time.Now()
+		defer func() {
+			call.Latency = time.Since(start)
+			call.Err = err
+		}()
+
+		if httpResp, err = httpClient.Do(httpReq.WithContext(ctx)); err != nil {
+			err = RedactError(err)
+			return
+		}
+		call.StatusCode = httpResp.StatusCode
+
+		if !isTextContent(httpResp.Header.Get("Content-Type")) {
+			return
+		}
+		b, err := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if err != nil {
+			httpResp = nil
+			return
+		}
+		httpResp.Body = io.NopCloser(bytes.NewReader(b))
+		call.ResponseBody = b
+		call.ErrCode = responseErrCode(b)
+		return
+	}
+
+	next := invoke
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context) error {
+			return interceptor(ctx, call, inner)
+		}
+	}
+
+	if err = next(httpReq.Context()); err != nil && httpResp != nil {
+		httpResp.Body.Close()
+		httpResp = nil
+	}
+	return
+}
+
+func isTextContent(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json", "text/json", "application/xml", "text/xml", "text/plain", "text/html":
+		return true
+	}
+	return false
+}
+
+// 从响应 body 中解析错误码, 没有错误时返回 "".
+func responseErrCode(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return ""
+	}
+
+	switch body[0] {
+	case '{':
+		var result struct {
+			ErrCode int `json:"errcode"`
+		}
+		if json.Unmarshal(body, &result) != nil || result.ErrCode == 0 {
+			return ""
+		}
+		return strconv.Itoa(result.ErrCode)
+	case '<':
+		var result struct {
+			ReturnCode string `xml:"return_code"`
+			ErrCode    string `xml:"err_code"`
+		}
+		if xml.Unmarshal(body, &result) != nil {
+			return ""
+		}
+		if result.ErrCode != "" {
+			return result.ErrCode
+		}
+		if result.ReturnCode != "" && result.ReturnCode != "SUCCESS" {
+			return result.ReturnCode
+		}
+	}
+	return ""
+}
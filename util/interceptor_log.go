@@ -0,0 +1,48 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+)
+
+// 用 slog 记录每一个请求的结构化日志.
+//  成功的请求用 Info 级别, 网络错误, http 状态码不是 200 或者返回了错误码用 Warn 级别;
+//  logBody 为 true 时同时记录请求和响应的 body(Debug 级别), access_token 等凭证用 RedactBody 替换,
+//  但是 body 里仍然可能有用户的敏感信息.
+func NewLogInterceptor(logger *slog.Logger, logBody bool) Interceptor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(ctx context.Context, call *Call, next func(ctx context.Context) error) (err error) {
+		err = next(ctx)
+
+		attrs := []slog.Attr{
+			slog.String("method", call.Method),
+			slog.String("url", call.URL),
+			slog.Int("status", call.StatusCode),
+			slog.Duration("latency", call.Latency),
+		}
+		if call.ErrCode != "" {
+			attrs = append(attrs, slog.String("errcode", call.ErrCode))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", RedactError(err).Error()))
+		}
+
+		level := slog.LevelInfo
+		if err != nil || call.ErrCode != "" || call.StatusCode != 200 {
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(ctx, level, "wechat request", attrs...)
+
+		if logBody {
+			logger.LogAttrs(ctx, slog.LevelDebug, "wechat request body",
+				slog.String("method", call.Method),
+				slog.String("url", call.URL),
+				slog.String("request", string(RedactBody(call.RequestBody))),
+				slog.String("response", string(RedactBody(call.ResponseBody))),
+			)
+		}
+		return
+	}
+}
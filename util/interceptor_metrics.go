@@ -0,0 +1,210 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 默认的请求耗时直方图的桶, 单位秒.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Prometheus 风格的请求指标:
+//
+//  <namespace>_requests_total{method, path, code}        请求次数计数器
+//  <namespace>_request_duration_seconds{method, path}    请求耗时直方图
+//
+//  code 为 "ok", 微信返回的错误码(如 "45009", "SYSTEMERROR"), "http_<状态码>" 或者 "error"(网络错误).
+//  Metrics 实现了 http.Handler, 可以直接挂载为 /metrics 给 Prometheus 抓取;
+//  如果已经在使用 Prometheus 的 client 库, 可以参考 Interceptor 自己实现拦截器.
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	mu         sync.Mutex
+	counters   map[counterKey]uint64
+	histograms map[histogramKey]*histogram
+}
+
+type counterKey struct {
+	method, path, code string
+}
+
+type histogramKey struct {
+	method, path string
+}
+
+type histogram struct {
+	counts []uint64 // 每个桶的计数, 不累加
+	count  uint64
+	sum    float64
+}
+
+// 创建 Metrics, namespace 为 "" 时使用 "wechat", buckets 为 nil 时使用 DefaultLatencyBuckets.
+func NewMetrics(namespace string, buckets []float64) *Metrics {
+	if namespace == "" {
+		namespace = "wechat"
+	}
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		namespace:  namespace,
+		buckets:    buckets,
+		counters:   make(map[counterKey]uint64),
+		histograms: make(map[histogramKey]*histogram),
+	}
+}
+
+// 返回记录指标的拦截器.
+func (m *Metrics) Interceptor() Interceptor {
+	return func(ctx context.Context, call *Call, next func(ctx context.Context) error) (err error) {
+		err = next(ctx)
+		m.observe(call, err)
+		return
+	}
+}
+
+func (m *Metrics) observe(call *Call, err error) {
+	var code string
+	switch {
+	case call.ErrCode != "":
+		code = call.ErrCode
+	case err != nil:
+		code = "error"
+	case call.StatusCode != http.StatusOK:
+		code = "http_" + strconv.Itoa(call.StatusCode)
+	default:
+		code = "ok"
+	}
+	seconds := call.Latency.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[counterKey{call.Method, call.Path, code}]++
+
+	hk := histogramKey{call.Method, call.Path}
+	h := m.histograms[hk]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.histograms[hk] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// 以 Prometheus 文本格式输出所有指标.
+func (m *Metrics) WriteTo(w io.Writer) (n int64, err error) {
+	m.mu.Lock()
+	counterKeys := make([]counterKey, 0, len(m.counters))
+	for k := range m.counters {
+		counterKeys = append(counterKeys, k)
+	}
+	counters := make([]uint64, len(counterKeys))
+	sort.Slice(counterKeys, func(i, j int) bool {
+		a, b := counterKeys[i], counterKeys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.code < b.code
+	})
+	for i, k := range counterKeys {
+		counters[i] = m.counters[k]
+	}
+
+	histogramKeys := make([]histogramKey, 0, len(m.histograms))
+	for k := range m.histograms {
+		histogramKeys = append(histogramKeys, k)
+	}
+	sort.Slice(histogramKeys, func(i, j int) bool {
+		a, b := histogramKeys[i], histogramKeys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return a.method < b.method
+	})
+	histograms := make([]histogram, len(histogramKeys))
+	for i, k := range histogramKeys {
+		h := m.histograms[k]
+		histograms[i] = histogram{counts: append([]uint64(nil), h.counts...), count: h.count, sum: h.sum}
+	}
+	m.mu.Unlock()
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+
+	name := m.namespace + "_requests_total"
+	fmt.Fprintf(cw, "# HELP %s Total number of requests to the wechat api.\n", name)
+	fmt.Fprintf(cw, "# TYPE %s counter\n", name)
+	for i, k := range counterKeys {
+		fmt.Fprintf(cw, "%s{method=%s,path=%s,code=%s} %d\n", name,
+			quoteLabel(k.method), quoteLabel(k.path), quoteLabel(k.code), counters[i])
+	}
+
+	name = m.namespace + "_request_duration_seconds"
+	fmt.Fprintf(cw, "# HELP %s Latency of requests to the wechat api.\n", name)
+	fmt.Fprintf(cw, "# TYPE %s histogram\n", name)
+	for i, k := range histogramKeys {
+		labels := "method=" + quoteLabel(k.method) + ",path=" + quoteLabel(k.path)
+		h := histograms[i]
+		var cumulative uint64
+		for j, bound := range m.buckets {
+			cumulative += h.counts[j]
+			fmt.Fprintf(cw, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels,
+				strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(cw, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(cw, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(cw, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(s string) string {
+	return `"` + labelReplacer.Replace(s) + `"`
+}
+
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (n int, err error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err = cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return
+}
@@ -0,0 +1,153 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoHTTPInterceptors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"errcode":45009,"errmsg":"api freq out of limit"}`)
+	}))
+	defer server.Close()
+
+	var order []string
+	var seen *Call
+	first := func(ctx context.Context, call *Call, next func(ctx context.Context) error) error {
+		order = append(order, "first")
+		return next(ctx)
+	}
+	second := func(ctx context.Context, call *Call, next func(ctx context.Context) error) error {
+		order = append(order, "second")
+		err := next(ctx)
+		seen = call
+		return err
+	}
+	metrics := NewMetrics("", nil)
+
+	body := []byte(`{"touser":"OPENID"}`)
+	httpReq, _ := http.NewRequest("POST", server.URL+"/cgi-bin/message/custom/send?access_token=SECRET_TOKEN", bytes.NewReader(body))
+	httpResp, err := DoHTTP(http.DefaultClient, []Interceptor{first, second, metrics.Interceptor()}, httpReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpResp.Body.Close()
+
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("unexpected order: %v", order)
+	}
+	if seen.Path != "/cgi-bin/message/custom/send" {
+		t.Errorf("unexpected path: %s", seen.Path)
+	}
+	if strings.Contains(seen.URL, "SECRET_TOKEN") || !strings.Contains(seen.URL, "access_token=%2A%2A%2A") {
+		t.Errorf("access_token not redacted: %s", seen.URL)
+	}
+	if !bytes.Equal(seen.RequestBody, body) {
+		t.Errorf("unexpected request body: %s", seen.RequestBody)
+	}
+	if seen.ErrCode != "45009" || seen.StatusCode != 200 {
+		t.Errorf("unexpected errcode %q, status %d", seen.ErrCode, seen.StatusCode)
+	}
+
+	// 拦截器读取过 body 后, 调用者仍然能读到完整的 body
+	var buf bytes.Buffer
+	buf.ReadFrom(httpResp.Body)
+	if !bytes.Equal(buf.Bytes(), seen.ResponseBody) {
+		t.Errorf("response body mismatch: %s", buf.Bytes())
+	}
+
+	buf.Reset()
+	metrics.WriteTo(&buf)
+	for _, want := range []string{
+		`wechat_requests_total{method="POST",path="/cgi-bin/message/custom/send",code="45009"} 1`,
+		`wechat_request_duration_seconds_count{method="POST",path="/cgi-bin/message/custom/send"} 1`,
+		`wechat_request_duration_seconds_bucket{method="POST",path="/cgi-bin/message/custom/send",le="+Inf"} 1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("metrics output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestLogInterceptorRedact(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cgi-bin/token":
+			fmt.Fprint(w, `{"access_token":"SECRET_ACCESS_TOKEN","expires_in":7200}`)
+		case "/sns/oauth2/access_token":
+			fmt.Fprint(w, `{"access_token": "SECRET_OAUTH_TOKEN", "refresh_token": "SECRET_REFRESH_TOKEN", "openid":"OPENID"}`)
+		}
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	interceptors := []Interceptor{NewLogInterceptor(logger, true)}
+	for _, path := range []string{
+		"/cgi-bin/token?grant_type=client_credential&appid=APPID&secret=SECRET_APPSECRET",
+		"/sns/oauth2/access_token?appid=APPID&secret=SECRET_APPSECRET&code=SECRET_CODE&grant_type=authorization_code",
+		"/sns/oauth2/refresh_token?appid=APPID&grant_type=refresh_token&refresh_token=SECRET_REFRESH_TOKEN",
+	} {
+		httpReq, _ := http.NewRequest("GET", server.URL+path, nil)
+		httpResp, err := DoHTTP(http.DefaultClient, interceptors, httpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		httpResp.Body.Close()
+	}
+
+	if strings.Contains(logs.String(), "SECRET_") {
+		t.Errorf("credentials in logs:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "OPENID") || !strings.Contains(logs.String(), "expires_in") {
+		t.Errorf("response body not logged:\n%s", logs.String())
+	}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestLogInterceptorRedactError(t *testing.T) {
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, context.DeadlineExceeded
+	})}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var callErr error
+	interceptors := []Interceptor{NewLogInterceptor(logger, true), func(ctx context.Context, call *Call, next func(ctx context.Context) error) error {
+		err := next(ctx)
+		callErr = call.Err
+		return err
+	}}
+
+	const rawURL = "http://127.0.0.1/cgi-bin/menu/get?access_token=SECRET_TOKEN"
+	for _, interceptors := range [][]Interceptor{interceptors, nil} {
+		httpReq, _ := http.NewRequest("GET", rawURL, nil)
+		_, err := DoHTTP(httpClient, interceptors, httpReq)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+		}
+		if strings.Contains(err.Error(), "SECRET_TOKEN") {
+			t.Errorf("access_token in err: %v", err)
+		}
+	}
+	if callErr == nil || strings.Contains(callErr.Error(), "SECRET_TOKEN") {
+		t.Errorf("call.Err = %v", callErr)
+	}
+	if !strings.Contains(logs.String(), "deadline exceeded") || strings.Contains(logs.String(), "SECRET_TOKEN") {
+		t.Errorf("logs:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// 把 v 编码为 JSON 写入 w, 不转义 HTML 字符.
+//  NOTE:
+//  1. 微信服务器不会还原 <, >, &, 所以图文消息的内容, 菜单的 URL, 模板消息的数据等
+//     里面的 <, >, & 必须原样提交, 否则用户看到的就是转义后的字符;
+//  2. 和 json.Encoder 一样, 末尾会写入一个换行符, 不影响微信服务器的解析.
+func EncodeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// 同 json.Marshal, 但是不转义 HTML 字符, 参考 EncodeJSON.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
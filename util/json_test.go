@@ -0,0 +1,43 @@
+package util
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{
+			v:    map[string]string{"url": "http://example.com/?a=1&b=2"},
+			want: `{"url":"http://example.com/?a=1&b=2"}`,
+		},
+		{
+			v:    map[string]string{"content": `<p>a & b</p>`},
+			want: `{"content":"<p>a & b</p>"}`,
+		},
+		{
+			// 原来先 json.Marshal 再用 bytes.Replace 还原的做法会把这里的 \\u003c 变成 \<, 得到非法的 JSON
+			v:    map[string]string{"content": `\u003c`},
+			want: `{"content":"\\u003c"}`,
+		},
+		{
+			v:    map[string]json.RawMessage{"data": json.RawMessage(`{"first": {"value": "<b>"}}`)},
+			want: `{"data":{"first":{"value":"<b>"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		b, err := MarshalJSON(tt.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("MarshalJSON(%v) = %s, want %s", tt.v, b, tt.want)
+		}
+		if !json.Valid(b) {
+			t.Errorf("MarshalJSON(%v) = %s, invalid JSON", tt.v, b)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 微信服务器返回的 http 状态码不是 200 OK.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPError) Error() string {
+	return "http.Status: " + e.Status
+}
+
+// 重试策略.
+//
+//  NOTE:
+//  1. 第 n 次重试前等待 InitialBackoff * Multiplier^(n-1), 最大不超过 MaxBackoff,
+//     然后在 [-Jitter, +Jitter] 的比例范围内随机抖动, 避免大量客户端同时重试;
+//  2. 等待期间 ctx 取消则立即返回 ctx.Err();
+//  3. 请求的 body 必须可以重复发送, 所以上传多媒体文件等从 io.Reader 读取数据的接口不会重试.
+type RetryPolicy struct {
+	MaxAttempts    int           // 最多请求的次数(包括第一次), <= 1 表示不重试
+	InitialBackoff time.Duration // 第一次重试前等待的时间
+	MaxBackoff     time.Duration // 最长的等待时间, <= 0 表示不限制
+	Multiplier     float64       // 每次重试等待时间的倍数, < 1 时按 1 处理
+	Jitter         float64       // 随机抖动的比例, 取值 [0, 1]
+
+	// 判断错误是否可以重试, nil 表示使用 IsRetryableError
+	Retryable func(err error) bool
+}
+
+// 推荐的重试策略: 最多请求 3 次, 等待 200ms, 400ms (±20%).
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// 判断 err 是否是临时性的错误:
+//  1. ctx 取消或者超时不重试;
+//  2. 网络错误(连接失败, 连接被重置等)可以重试;
+//  3. http 5xx 和 429 Too Many Requests 可以重试;
+//  4. 实现了 Temporary() bool 的错误(比如 mp.Error, corp.Error, pay.Error)由 Temporary() 决定.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var temporary interface {
+		Temporary() bool
+	}
+	if errors.As(err, &temporary) {
+		return temporary.Temporary()
+	}
+	return false
+}
+
+// 第 attempt 次重试(从 1 开始)前需要等待的时间.
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= multiplier
+		if p.MaxBackoff > 0 && backoff >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		backoff += backoff * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(backoff)
+}
+
+// 按照重试策略调用 fn, 直到 fn 返回 nil, 返回不可重试的错误, 或者达到最大请求次数, 返回最后一次的错误.
+//  p == nil 时只调用一次 fn.
+func (p *RetryPolicy) Do(ctx context.Context, fn func() error) (err error) {
+	if p == nil {
+		return fn()
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return
+		}
+		if attempt >= p.MaxAttempts || !retryable(err) {
+			return
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const DefaultSeenStoreCapacity = 100000
+
+// SeenStore 里 key 的状态
+type SeenState int
+
+const (
+	SeenNew        SeenState = iota // 没有记录, Claim 已经把它记录为正在处理
+	SeenProcessing                  // 正在处理(还没有调用 Done 或者 Forget)
+	SeenDone                        // 已经处理成功
+)
+
+// 记录 key 的处理状态的存储接口, 用于去掉微信服务器重试推送的重复消息(事件), 支付结果通知等.
+//
+//  NOTE:
+//  1. Claim 必须是原子的: key 在有效期内有记录时返回它的状态; 否则把 key 记录为正在处理, 有效期为 ttl, 返回 SeenNew;
+//     并发的多个相同 key 的调用只有一个返回 SeenNew;
+//  2. 处理成功后调用 Done, 把 key 记录为已经处理成功, 有效期为 ttl;
+//  3. 处理失败后调用 Forget 删除 key, 让之后的重试可以被处理;
+//     正在处理的记录在 Claim 的 ttl 之后过期, 避免进程崩溃等原因没有调用 Done/Forget 时 key 一直处于正在处理的状态;
+//  4. 多个进程(机器)共同处理回调时, 可以基于 Redis 等实现这个接口, 比如
+//     Claim: SET key processing NX EX ttl, 失败时 GET key; Done: SET key done EX ttl; Forget: DEL key.
+type SeenStore interface {
+	Claim(key string, ttl time.Duration) (state SeenState, err error)
+	Done(key string, ttl time.Duration) error
+	Forget(key string) error
+}
+
+// 内存中的 SeenStore, 超过容量时淘汰最久没有访问的 key.
+type MemorySeenStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List               // 最近访问的在前面
+	items    map[string]*list.Element // key -> *seenEntry
+}
+
+type seenEntry struct {
+	key       string
+	state     SeenState // SeenProcessing 或者 SeenDone
+	expiresAt time.Time
+}
+
+// capacity <= 0 时使用 DefaultSeenStoreCapacity.
+func NewMemorySeenStore(capacity int) *MemorySeenStore {
+	if capacity <= 0 {
+		capacity = DefaultSeenStoreCapacity
+	}
+	return &MemorySeenStore{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (s *MemorySeenStore) Claim(key string, ttl time.Duration) (state SeenState, err error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		entry := elem.Value.(*seenEntry)
+		s.ll.MoveToFront(elem)
+		if now.Before(entry.expiresAt) {
+			return entry.state, nil
+		}
+		entry.state = SeenProcessing
+		entry.expiresAt = now.Add(ttl)
+		return SeenNew, nil
+	}
+
+	s.add(key, SeenProcessing, now.Add(ttl))
+	return SeenNew, nil
+}
+
+func (s *MemorySeenStore) Done(key string, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		entry := elem.Value.(*seenEntry)
+		entry.state = SeenDone
+		entry.expiresAt = expiresAt
+		s.ll.MoveToFront(elem)
+		return nil
+	}
+	s.add(key, SeenDone, expiresAt)
+	return nil
+}
+
+func (s *MemorySeenStore) add(key string, state SeenState, expiresAt time.Time) {
+	s.items[key] = s.ll.PushFront(&seenEntry{key: key, state: state, expiresAt: expiresAt})
+	for s.ll.Len() > s.capacity {
+		s.removeElement(s.ll.Back())
+	}
+}
+
+func (s *MemorySeenStore) Forget(key string) error {
+	s.mu.Lock()
+	if elem, ok := s.items[key]; ok {
+		s.removeElement(elem)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// 当前记录的 key 的个数(包括已经过期但是还没有被淘汰的).
+func (s *MemorySeenStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+func (s *MemorySeenStore) removeElement(elem *list.Element) {
+	s.ll.Remove(elem)
+	delete(s.items, elem.Value.(*seenEntry).key)
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemorySeenStore(t *testing.T) {
+	s := NewMemorySeenStore(2)
+
+	mustClaim := func(key string, ttl time.Duration, want SeenState) {
+		t.Helper()
+		state, err := s.Claim(key, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state != want {
+			t.Fatalf("Claim(%q) = %v, want %v", key, state, want)
+		}
+	}
+
+	// 处理完成之前为 SeenProcessing, Done 之后为 SeenDone
+	mustClaim("a", time.Minute, SeenNew)
+	mustClaim("a", time.Minute, SeenProcessing)
+	s.Done("a", time.Minute)
+	mustClaim("a", time.Minute, SeenDone)
+
+	// 过期后重新记录
+	mustClaim("b", -time.Second, SeenNew)
+	mustClaim("b", time.Minute, SeenNew)
+	mustClaim("b", time.Minute, SeenProcessing)
+	s.Done("b", -time.Second)
+	mustClaim("b", time.Minute, SeenNew)
+
+	// 超过容量时淘汰最久没有访问的 a
+	mustClaim("b", time.Minute, SeenProcessing)
+	mustClaim("c", time.Minute, SeenNew)
+	if n := s.Len(); n != 2 {
+		t.Fatalf("Len() = %d, want 2", n)
+	}
+	mustClaim("a", time.Minute, SeenNew)
+
+	// 处理失败后删除记录
+	s.Forget("a")
+	mustClaim("a", time.Minute, SeenNew)
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 合并同一个 key 上并发的调用: 同一时刻只有一个 fn 在执行, 期间到达的调用者等待它完成并共享它的结果.
+//  零值可以直接使用.
+type SingleFlight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// SingleFlight.DoChan 的结果
+type FlightResult struct {
+	Val interface{}
+	Err error
+}
+
+func (g *SingleFlight) Do(key string, fn func() (interface{}, error)) (v interface{}, err error) {
+	r := <-g.DoChan(key, fn)
+	return r.Val, r.Err
+}
+
+// 同 Do, 但是不阻塞, 结果从返回的 channel 里读取; 调用者可以不等待结果(比如 context 取消了).
+func (g *SingleFlight) DoChan(key string, fn func() (interface{}, error)) <-chan FlightResult {
+	ch := make(chan FlightResult, 1)
+
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.call(key, call, fn)
+	}
+	g.mu.Unlock()
+
+	go func() {
+		<-call.done
+		ch <- FlightResult{Val: call.val, Err: call.err}
+	}()
+	return ch
+}
+
+func (g *SingleFlight) call(key string, call *flightCall, fn func() (interface{}, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("panic: %v", r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.val, call.err = fn()
+}
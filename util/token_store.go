@@ -0,0 +1,273 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// access_token, jsapi_ticket 等凭证.
+type Credential struct {
+	Value     string `json:"value"`
+	ExpiresAt int64  `json:"expires_at"` // 过期时间, unixtime
+}
+
+// 凭证的存储接口, mp.Client 和 corp.Client 的 access_token, jsapi_ticket 都通过它读写.
+//
+//  NOTE:
+//  1. Get 在 key 不存在时返回零值的 Credential 和 nil error;
+//  2. CompareAndSwap 只有在当前值等于 old 时才写入 new, 多个进程共享同一个存储时,
+//     用它来保证同一时刻只有一个新的凭证生效;
+//  3. 如果要在多个进程(机器)之间共享凭证, 可以基于 Redis, 数据库等实现这个接口,
+//     然后通过 Client.SetTokenStore 设置.
+type TokenStore interface {
+	Get(key string) (cred Credential, err error)
+	Set(key string, cred Credential) error
+	CompareAndSwap(key string, old, new Credential) (swapped bool, err error)
+}
+
+// 内存中的 TokenStore, 只能在单个进程内共享.
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	creds map[string]Credential
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		creds: make(map[string]Credential),
+	}
+}
+
+func (s *MemoryTokenStore) Get(key string) (cred Credential, err error) {
+	s.mu.Lock()
+	cred = s.creds[key]
+	s.mu.Unlock()
+	return
+}
+
+func (s *MemoryTokenStore) Set(key string, cred Credential) error {
+	s.mu.Lock()
+	s.creds[key] = cred
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryTokenStore) CompareAndSwap(key string, old, new Credential) (swapped bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.creds[key] != old {
+		return
+	}
+	s.creds[key] = new
+	swapped = true
+	return
+}
+
+const (
+	fileTokenStoreLockRetry   = 10 * time.Millisecond
+	fileTokenStoreLockTimeout = 5 * time.Second
+	fileTokenStoreLockStale   = 30 * time.Second // 超过这个时间没有更新的锁文件认为是崩溃的进程遗留的
+)
+
+// 基于文件的 TokenStore, 同一台机器上的多个进程可以共享.
+//  所有凭证以 JSON 格式保存在 path 指定的文件里, 读写时用 path + ".lock" 文件做进程间互斥:
+//  锁文件的内容为持有者的随机 token, 只有持有者才会删除它; 持有期间定期更新锁文件的修改时间,
+//  超过 30 秒没有更新的锁文件认为持有者已经崩溃, 其他进程可以删除它.
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{
+		path: path,
+	}
+}
+
+func (s *FileTokenStore) Get(key string) (cred Credential, err error) {
+	err = s.withLock(func(creds map[string]Credential) (changed bool) {
+		cred = creds[key]
+		return false
+	})
+	return
+}
+
+func (s *FileTokenStore) Set(key string, cred Credential) error {
+	return s.withLock(func(creds map[string]Credential) (changed bool) {
+		creds[key] = cred
+		return true
+	})
+}
+
+func (s *FileTokenStore) CompareAndSwap(key string, old, new Credential) (swapped bool, err error) {
+	err = s.withLock(func(creds map[string]Credential) (changed bool) {
+		if creds[key] != old {
+			return false
+		}
+		creds[key] = new
+		swapped = true
+		return true
+	})
+	if err != nil {
+		swapped = false
+	}
+	return
+}
+
+// 加锁后读取文件内容并调用 fn, 如果 fn 返回 true 则把修改后的内容写回文件.
+func (s *FileTokenStore) withLock(fn func(creds map[string]Credential) (changed bool)) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lockFile()
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	creds := make(map[string]Credential)
+	b, err := os.ReadFile(s.path)
+	switch {
+	case err == nil:
+		if len(b) > 0 {
+			if err = json.Unmarshal(b, &creds); err != nil {
+				return
+			}
+		}
+	case os.IsNotExist(err):
+		err = nil
+	default:
+		return
+	}
+
+	if !fn(creds) {
+		return
+	}
+
+	if b, err = json.Marshal(creds); err != nil {
+		return
+	}
+
+	// 先写临时文件再 rename, 避免其他进程读到写了一半的文件
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return
+	}
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	return
+}
+
+func (s *FileTokenStore) lockFile() (unlock func(), err error) {
+	lockPath := s.path + ".lock"
+	owner := RandString(32)
+	deadline := time.Now().Add(fileTokenStoreLockTimeout)
+
+	for {
+		if err = createLockFile(lockPath, owner); err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return
+		}
+		if removeStaleLockFile(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			err = errors.New("timeout waiting for lock file: " + lockPath)
+			return
+		}
+		time.Sleep(fileTokenStoreLockRetry)
+	}
+
+	// 持有期间定期更新修改时间, 避免处理较慢时被其他进程当作遗留的锁删除
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(fileTokenStoreLockStale / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if isLockFileOwner(lockPath, owner) {
+					os.Chtimes(lockPath, now, now)
+				}
+			}
+		}
+	}()
+	unlock = func() {
+		close(stop)
+		<-stopped
+		if isLockFileOwner(lockPath, owner) {
+			os.Remove(lockPath)
+		}
+	}
+	return
+}
+
+// 创建锁文件并写入 owner, 锁文件已经存在时返回的错误满足 os.IsExist.
+func createLockFile(lockPath, owner string) (err error) {
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	if _, err = f.WriteString(owner); err != nil {
+		f.Close()
+		os.Remove(lockPath)
+		return
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(lockPath)
+	}
+	return
+}
+
+func isLockFileOwner(lockPath, owner string) bool {
+	b, err := os.ReadFile(lockPath)
+	return err == nil && string(b) == owner
+}
+
+// 删除过期(持有者已经崩溃)的锁文件, 返回是否删除了.
+//  先把锁文件改名再检查, 这样不会删除其他进程在检查之后刚刚创建的锁文件.
+func removeStaleLockFile(lockPath string) bool {
+	fi, err := os.Stat(lockPath)
+	if err != nil || time.Since(fi.ModTime()) <= fileTokenStoreLockStale {
+		return false
+	}
+	staleOwner, err := os.ReadFile(lockPath)
+	if err != nil {
+		return false
+	}
+
+	stalePath := lockPath + ".stale." + RandString(16)
+	if err = os.Rename(lockPath, stalePath); err != nil {
+		return false
+	}
+	defer os.Remove(stalePath)
+
+	b, err := os.ReadFile(stalePath)
+	fi, statErr := os.Stat(stalePath)
+	if err == nil && statErr == nil && string(b) == string(staleOwner) && time.Since(fi.ModTime()) > fileTokenStoreLockStale {
+		return true
+	}
+	// 改名的是其他进程刚刚创建(或者刚刚更新)的锁文件, 放回去; 如果又有新的锁文件则保留新的
+	os.Link(stalePath, lockPath)
+	return false
+}
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testTokenStore(t *testing.T, store TokenStore) {
+	t.Helper()
+
+	cred, err := store.Get("mp:access_token:wx1")
+	if err != nil || cred != (Credential{}) {
+		t.Fatalf("Get missing key = %+v, %v", cred, err)
+	}
+
+	expired := Credential{Value: "TOKEN1", ExpiresAt: time.Now().Unix() - 1}
+	if err = store.Set("mp:access_token:wx1", expired); err != nil {
+		t.Fatal(err)
+	}
+	if cred, err = store.Get("mp:access_token:wx1"); err != nil || cred != expired {
+		t.Fatalf("Get = %+v, %v, want %+v", cred, err, expired)
+	}
+
+	// 当前值不是 old 时不写入
+	fresh := Credential{Value: "TOKEN2", ExpiresAt: time.Now().Unix() + 7200}
+	other := Credential{Value: "TOKEN3", ExpiresAt: time.Now().Unix() + 7200}
+	if swapped, err := store.CompareAndSwap("mp:access_token:wx1", Credential{}, other); err != nil || swapped {
+		t.Fatalf("CompareAndSwap with wrong old = %v, %v", swapped, err)
+	}
+	if swapped, err := store.CompareAndSwap("mp:access_token:wx1", expired, fresh); err != nil || !swapped {
+		t.Fatalf("CompareAndSwap = %v, %v", swapped, err)
+	}
+	// 其他调用者用同一个过期的 old 替换, 失败
+	if swapped, err := store.CompareAndSwap("mp:access_token:wx1", expired, other); err != nil || swapped {
+		t.Fatalf("second CompareAndSwap = %v, %v", swapped, err)
+	}
+	if cred, err = store.Get("mp:access_token:wx1"); err != nil || cred != fresh {
+		t.Fatalf("Get = %+v, %v, want %+v", cred, err, fresh)
+	}
+
+	// 不存在的 key 的 old 为零值
+	if swapped, err := store.CompareAndSwap("mp:jsapi_ticket:wx1", Credential{}, fresh); err != nil || !swapped {
+		t.Fatalf("CompareAndSwap missing key = %v, %v", swapped, err)
+	}
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	testTokenStore(t, NewMemoryTokenStore())
+}
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	testTokenStore(t, NewFileTokenStore(path))
+
+	// 其他进程打开同一个文件读到相同的内容
+	cred, err := NewFileTokenStore(path).Get("mp:access_token:wx1")
+	if err != nil || cred.Value != "TOKEN2" {
+		t.Errorf("Get = %+v, %v", cred, err)
+	}
+	if _, err = os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file not removed: %v", err)
+	}
+}
+
+// 多个进程(每个 FileTokenStore 有自己的 sync.Mutex, 只靠锁文件互斥)并发地 CompareAndSwap
+func TestFileTokenStoreConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	const workers, increments = 8, 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewFileTokenStore(path)
+			for j := 0; j < increments; j++ {
+				for {
+					old, err := store.Get("counter")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					n, _ := strconv.Atoi(old.Value)
+					swapped, err := store.CompareAndSwap("counter", old, Credential{Value: strconv.Itoa(n + 1)})
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if swapped {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	cred, err := NewFileTokenStore(path).Get("counter")
+	if err != nil || cred.Value != strconv.Itoa(workers*increments) {
+		t.Errorf("counter = %+v, %v, want %d", cred, err, workers*increments)
+	}
+}
+
+func TestFileTokenStoreLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	lockPath := path + ".lock"
+	store := NewFileTokenStore(path)
+
+	// 其他进程持有的(没有过期的)锁不能删除, 等它释放后才能写入
+	if err := os.WriteFile(lockPath, []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- store.Set("k", Credential{Value: "v"}) }()
+	select {
+	case err := <-done:
+		t.Fatalf("Set returned while the lock is held: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if b, _ := os.ReadFile(lockPath); string(b) != "other" {
+		t.Fatalf("lock file = %q, want other", b)
+	}
+	os.Remove(lockPath)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// 过期的锁(持有者已经崩溃)被删除
+	if err := os.WriteFile(lockPath, []byte("crashed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * fileTokenStoreLockStale)
+	os.Chtimes(lockPath, old, old)
+	if cred, err := store.Get("k"); err != nil || cred.Value != "v" {
+		t.Fatalf("Get = %+v, %v", cred, err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("stale lock file not removed: %v", err)
+	}
+
+	// 锁被其他进程拿走后, 原来的持有者释放时不能删除其他进程的锁
+	unlock, err := store.lockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(lockPath, []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if b, _ := os.ReadFile(lockPath); string(b) != "other" {
+		t.Errorf("lock file = %q, want other", b)
+	}
+}
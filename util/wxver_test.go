@@ -57,35 +57,35 @@ func TestWXVersion(t *testing.T) {
 	userAgent = `Mozilla5.0(iphone;CPU iphone OS 5_1_1 like Mac OS X) AppleWebKit534.46(KHTML,like Geocko)Mobile9B206 MicroMessenger5.0`
 	_, _, _, err = WXVersion(userAgent)
 	if err == nil {
-		t.Errorf("从 %#s 获取版本号应该出错, 但是目前却没有错误!", userAgent)
+		t.Errorf("从 %q 获取版本号应该出错, 但是目前却没有错误!", userAgent)
 		return
 	}
 
 	userAgent = `Mozilla/5.0(iphone;CPU iphone OS 5_1_1 like Mac OS X) AppleWebKit/534.46(KHTML,like Geocko)Mobile/9B206 MicroMessenger/`
 	_, _, _, err = WXVersion(userAgent)
 	if err == nil {
-		t.Errorf("从 %#s 获取版本号应该出错, 但是目前却没有错误!", userAgent)
+		t.Errorf("从 %q 获取版本号应该出错, 但是目前却没有错误!", userAgent)
 		return
 	}
 
 	userAgent = `Mozilla/5.0(iphone;CPU iphone OS 5_1_1 like Mac OS X) AppleWebKit/534.46(KHTML,like Geocko)Mobile/9B206 MicroMessenger/5x`
 	_, _, _, err = WXVersion(userAgent)
 	if err == nil {
-		t.Errorf("从 %#s 获取版本号应该出错, 但是目前却没有错误!", userAgent)
+		t.Errorf("从 %q 获取版本号应该出错, 但是目前却没有错误!", userAgent)
 		return
 	}
 
 	userAgent = `Mozilla/5.0(iphone;CPU iphone OS 5_1_1 like Mac OS X) AppleWebKit/534.46(KHTML,like Geocko)Mobile/9B206 MicroMessenger/5.3x`
 	_, _, _, err = WXVersion(userAgent)
 	if err == nil {
-		t.Errorf("从 %#s 获取版本号应该出错, 但是目前却没有错误!", userAgent)
+		t.Errorf("从 %q 获取版本号应该出错, 但是目前却没有错误!", userAgent)
 		return
 	}
 
 	userAgent = `Mozilla/5.0(iphone;CPU iphone OS 5_1_1 like Mac OS X) AppleWebKit/534.46(KHTML,like Geocko)Mobile/9B206 MicroMessenger/5.3.1x`
 	_, _, _, err = WXVersion(userAgent)
 	if err == nil {
-		t.Errorf("从 %#s 获取版本号应该出错, 但是目前却没有错误!", userAgent)
+		t.Errorf("从 %q 获取版本号应该出错, 但是目前却没有错误!", userAgent)
 		return
 	}
 }
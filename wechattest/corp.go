@@ -0,0 +1,529 @@
+package wechattest
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// 创建模拟企业号接口的 Server, 对应 corp.Client.
+//  corpid 为 "wx_test_corpid", corpsecret 为 "wx_test_corpsecret"; 默认有一个 id 为 1 的根部门.
+func NewCorpServer() *Server {
+	return newServer("wx_test_corpid", "wx_test_corpsecret", corpRoutes())
+}
+
+func corpRoutes() map[string]route {
+	return map[string]route{
+		"/cgi-bin/gettoken":         {method: "GET", noAuth: true, fn: corpToken},
+		"/cgi-bin/get_jsapi_ticket": {method: "GET", fn: func(r *request) interface{} { return r.s.newTicket() }},
+
+		"/cgi-bin/menu/create": {method: "POST", fn: menuCreate},
+		"/cgi-bin/menu/get":    {method: "GET", fn: menuGet},
+		"/cgi-bin/menu/delete": {method: "GET", fn: menuDelete},
+
+		"/cgi-bin/user/create":      {method: "POST", fn: corpUserCreate},
+		"/cgi-bin/user/update":      {method: "POST", fn: corpUserUpdate},
+		"/cgi-bin/user/delete":      {method: "GET", fn: corpUserDelete},
+		"/cgi-bin/user/batchdelete": {method: "POST", fn: corpUserBatchDelete},
+		"/cgi-bin/user/get":         {method: "GET", fn: corpUserGet},
+		"/cgi-bin/user/simplelist":  {method: "GET", fn: corpUserSimpleList},
+		"/cgi-bin/user/list":        {method: "GET", fn: corpUserList},
+
+		"/cgi-bin/department/create": {method: "POST", fn: corpDepartmentCreate},
+		"/cgi-bin/department/update": {method: "POST", fn: corpDepartmentUpdate},
+		"/cgi-bin/department/delete": {method: "GET", fn: corpDepartmentDelete},
+		"/cgi-bin/department/list":   {method: "GET", fn: corpDepartmentList},
+
+		"/cgi-bin/tag/create":      {method: "POST", fn: corpTagCreate},
+		"/cgi-bin/tag/update":      {method: "POST", fn: corpTagUpdate},
+		"/cgi-bin/tag/delete":      {method: "GET", fn: corpTagDelete},
+		"/cgi-bin/tag/get":         {method: "GET", fn: corpTagGet},
+		"/cgi-bin/tag/addtagusers": {method: "POST", fn: corpTagAddUsers},
+		"/cgi-bin/tag/deltagusers": {method: "POST", fn: corpTagDeleteUsers},
+		"/cgi-bin/tag/list":        {method: "GET", fn: corpTagList},
+
+		"/cgi-bin/message/send": {method: "POST", fn: corpMessageSend},
+
+		"/cgi-bin/media/upload": {method: "POST", fn: corpMediaUpload},
+		"/cgi-bin/media/get":    {method: "GET", fn: func(r *request) interface{} { return r.download(r.query.Get("media_id")) }},
+	}
+}
+
+func corpToken(r *request) interface{} {
+	if r.query.Get("corpid") != r.s.AppId {
+		return fail(40013, "invalid corpid")
+	}
+	if r.query.Get("corpsecret") != r.s.AppSecret {
+		return fail(40001, "invalid credential")
+	}
+	return r.s.newToken()
+}
+
+func corpUserCreate(r *request) interface{} {
+	var user map[string]interface{}
+	if err := r.decode(&user); err != nil {
+		return fail(47001, "data format error")
+	}
+	userId, _ := user["userid"].(string)
+	if userId == "" {
+		return fail(41009, "missing userid")
+	}
+	if _, found := r.s.users[userId]; found {
+		return fail(60102, "userid existed")
+	}
+	if _, found := user["department"]; !found {
+		user["department"] = []interface{}{float64(1)}
+	}
+	if _, found := user["status"]; !found {
+		user["status"] = 4
+	}
+	r.s.setUser(userId, user)
+	return ok(nil)
+}
+
+func corpUserUpdate(r *request) interface{} {
+	var fields map[string]interface{}
+	if err := r.decode(&fields); err != nil {
+		return fail(47001, "data format error")
+	}
+	userId, _ := fields["userid"].(string)
+	user, found := r.s.users[userId]
+	if !found {
+		return fail(60111, "userid not found")
+	}
+	for k, v := range fields {
+		user[k] = v
+	}
+	return ok(nil)
+}
+
+func corpUserDelete(r *request) interface{} {
+	userId := r.query.Get("userid")
+	if _, found := r.s.users[userId]; !found {
+		return fail(60111, "userid not found")
+	}
+	r.s.deleteCorpUser(userId)
+	return ok(nil)
+}
+
+func corpUserBatchDelete(r *request) interface{} {
+	var req struct {
+		UserIdList []string `json:"useridlist"`
+	}
+	if err := r.decode(&req); err != nil || len(req.UserIdList) == 0 {
+		return fail(41009, "missing userid")
+	}
+	for _, userId := range req.UserIdList {
+		if _, found := r.s.users[userId]; !found {
+			return fail(60111, "userid not found")
+		}
+	}
+	for _, userId := range req.UserIdList {
+		r.s.deleteCorpUser(userId)
+	}
+	return ok(nil)
+}
+
+func (s *Server) deleteCorpUser(userId string) {
+	delete(s.users, userId)
+	for _, t := range s.qyTags {
+		t.Users = removeString(t.Users, userId)
+	}
+}
+
+func corpUserGet(r *request) interface{} {
+	user, found := r.s.users[r.query.Get("userid")]
+	if !found {
+		return fail(60111, "userid not found")
+	}
+	return ok(user)
+}
+
+// 部门 departmentId 下的成员, fetchChild 时包括子部门
+func (r *request) departmentUsers() (users []map[string]interface{}, resp interface{}) {
+	departmentId, _ := strconv.ParseInt(r.query.Get("department_id"), 10, 64)
+	if _, found := r.s.parties[departmentId]; !found {
+		return nil, fail(60003, "department not found")
+	}
+	parties := map[int64]bool{departmentId: true}
+	if r.query.Get("fetch_child") == "1" {
+		for _, id := range r.s.childDepartments(departmentId) {
+			parties[id] = true
+		}
+	}
+	status, _ := strconv.Atoi(r.query.Get("status"))
+
+	users = make([]map[string]interface{}, 0)
+	for _, userId := range r.s.userIds() {
+		user := r.s.users[userId]
+		if status != 0 && status&toInt(user["status"]) == 0 {
+			continue
+		}
+		departments, _ := user["department"].([]interface{})
+		for _, id := range departments {
+			if parties[int64(toInt(id))] {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+	return
+}
+
+func corpUserSimpleList(r *request) interface{} {
+	users, resp := r.departmentUsers()
+	if resp != nil {
+		return resp
+	}
+	list := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		list = append(list, map[string]interface{}{"userid": user["userid"], "name": user["name"]})
+	}
+	return ok(map[string]interface{}{"userlist": list})
+}
+
+func corpUserList(r *request) interface{} {
+	users, resp := r.departmentUsers()
+	if resp != nil {
+		return resp
+	}
+	return ok(map[string]interface{}{"userlist": users})
+}
+
+func (s *Server) childDepartments(parentId int64) (ids []int64) {
+	for id, party := range s.parties {
+		if int64(toInt(party["parentid"])) == parentId && id != parentId {
+			ids = append(ids, id)
+			ids = append(ids, s.childDepartments(id)...)
+		}
+	}
+	return
+}
+
+func corpDepartmentCreate(r *request) interface{} {
+	var req struct {
+		Name     string `json:"name"`
+		ParentId int64  `json:"parentid"`
+		Order    int64  `json:"order"`
+		Id       int64  `json:"id"`
+	}
+	if err := r.decode(&req); err != nil || req.Name == "" {
+		return fail(60001, "department name size invalid")
+	}
+	if _, found := r.s.parties[req.ParentId]; !found {
+		return fail(60004, "parent department not found")
+	}
+	if req.Id == 0 {
+		req.Id = r.s.newId()
+	}
+	if _, found := r.s.parties[req.Id]; found {
+		return fail(60008, "department existed")
+	}
+	r.s.parties[req.Id] = map[string]interface{}{"id": req.Id, "name": req.Name, "parentid": req.ParentId, "order": req.Order}
+	return ok(map[string]interface{}{"id": req.Id})
+}
+
+func corpDepartmentUpdate(r *request) interface{} {
+	var req map[string]interface{}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	party, found := r.s.parties[int64(toInt(req["id"]))]
+	if !found {
+		return fail(60003, "department not found")
+	}
+	for _, k := range []string{"name", "parentid", "order"} {
+		if v, found := req[k]; found {
+			party[k] = v
+		}
+	}
+	return ok(nil)
+}
+
+func corpDepartmentDelete(r *request) interface{} {
+	id, _ := strconv.ParseInt(r.query.Get("id"), 10, 64)
+	if _, found := r.s.parties[id]; !found {
+		return fail(60003, "department not found")
+	}
+	if len(r.s.childDepartments(id)) > 0 {
+		return fail(60006, "department has sub department")
+	}
+	for _, user := range r.s.users {
+		departments, _ := user["department"].([]interface{})
+		for _, partyId := range departments {
+			if int64(toInt(partyId)) == id {
+				return fail(60005, "department has member")
+			}
+		}
+	}
+	delete(r.s.parties, id)
+	return ok(nil)
+}
+
+func corpDepartmentList(r *request) interface{} {
+	id, _ := strconv.ParseInt(r.query.Get("id"), 10, 64)
+	if id == 0 {
+		id = 1
+	}
+	if _, found := r.s.parties[id]; !found {
+		return fail(60003, "department not found")
+	}
+	ids := append([]int64{id}, r.s.childDepartments(id)...)
+	sortInt64s(ids)
+	list := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, r.s.parties[id])
+	}
+	return ok(map[string]interface{}{"department": list})
+}
+
+func corpTagCreate(r *request) interface{} {
+	var req struct {
+		TagName string `json:"tagname"`
+	}
+	if err := r.decode(&req); err != nil || req.TagName == "" {
+		return fail(41018, "missing tagname")
+	}
+	for _, t := range r.s.qyTags {
+		if t.Name == req.TagName {
+			return fail(40071, "tagname existed")
+		}
+	}
+	t := &tag{Id: r.s.newId(), Name: req.TagName}
+	r.s.qyTags[t.Id] = t
+	return ok(map[string]interface{}{"tagid": t.Id})
+}
+
+func corpTagUpdate(r *request) interface{} {
+	var req struct {
+		TagId   int64  `json:"tagid"`
+		TagName string `json:"tagname"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.qyTags[req.TagId]
+	if !found {
+		return fail(40068, "invalid tagid")
+	}
+	t.Name = req.TagName
+	return ok(nil)
+}
+
+func corpTagDelete(r *request) interface{} {
+	id, _ := strconv.ParseInt(r.query.Get("tagid"), 10, 64)
+	if _, found := r.s.qyTags[id]; !found {
+		return fail(40068, "invalid tagid")
+	}
+	delete(r.s.qyTags, id)
+	return ok(nil)
+}
+
+func corpTagGet(r *request) interface{} {
+	id, _ := strconv.ParseInt(r.query.Get("tagid"), 10, 64)
+	t, found := r.s.qyTags[id]
+	if !found {
+		return fail(40068, "invalid tagid")
+	}
+	users := make([]map[string]interface{}, 0, len(t.Users))
+	for _, userId := range t.Users {
+		name := ""
+		if user, found := r.s.users[userId]; found {
+			name, _ = user["name"].(string)
+		}
+		users = append(users, map[string]interface{}{"userid": userId, "name": name})
+	}
+	parties := t.Parties
+	if parties == nil {
+		parties = []int64{}
+	}
+	return ok(map[string]interface{}{"userlist": users, "partylist": parties})
+}
+
+type corpTagMembersRequest struct {
+	TagId          int64    `json:"tagid"`
+	UserList       []string `json:"userlist"`
+	DepartmentList []int64  `json:"partylist"`
+}
+
+func corpTagAddUsers(r *request) interface{} {
+	var req corpTagMembersRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.qyTags[req.TagId]
+	if !found {
+		return fail(40068, "invalid tagid")
+	}
+	var invalidUsers []string
+	var invalidParties []int64
+	for _, userId := range req.UserList {
+		if _, found := r.s.users[userId]; !found {
+			invalidUsers = append(invalidUsers, userId)
+			continue
+		}
+		if !containsString(t.Users, userId) {
+			t.Users = append(t.Users, userId)
+		}
+	}
+	for _, id := range req.DepartmentList {
+		if _, found := r.s.parties[id]; !found {
+			invalidParties = append(invalidParties, id)
+			continue
+		}
+		if !containsInt64(t.Parties, id) {
+			t.Parties = append(t.Parties, id)
+		}
+	}
+	return tagMembersResult(len(req.UserList)+len(req.DepartmentList), invalidUsers, invalidParties)
+}
+
+func corpTagDeleteUsers(r *request) interface{} {
+	var req corpTagMembersRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.qyTags[req.TagId]
+	if !found {
+		return fail(40068, "invalid tagid")
+	}
+	var invalidUsers []string
+	var invalidParties []int64
+	for _, userId := range req.UserList {
+		if !containsString(t.Users, userId) {
+			invalidUsers = append(invalidUsers, userId)
+			continue
+		}
+		t.Users = removeString(t.Users, userId)
+	}
+	for _, id := range req.DepartmentList {
+		if !containsInt64(t.Parties, id) {
+			invalidParties = append(invalidParties, id)
+			continue
+		}
+		t.Parties = removeInt64(t.Parties, id)
+	}
+	return tagMembersResult(len(req.UserList)+len(req.DepartmentList), invalidUsers, invalidParties)
+}
+
+// 部分成员非法时返回 errcode 0 和 invalidlist, invalidparty; 全部非法时返回 40070
+func tagMembersResult(total int, invalidUsers []string, invalidParties []int64) interface{} {
+	if invalidUsers == nil && invalidParties == nil {
+		return ok(nil)
+	}
+	if len(invalidUsers)+len(invalidParties) == total {
+		return fail(40070, "all tag users invalid")
+	}
+	resp := ok(nil)
+	resp["invalidlist"] = strings.Join(invalidUsers, "|")
+	if invalidParties != nil {
+		resp["invalidparty"] = invalidParties
+	}
+	return resp
+}
+
+func corpTagList(r *request) interface{} {
+	list := make([]map[string]interface{}, 0, len(r.s.qyTags))
+	for _, t := range sortedTags(r.s.qyTags) {
+		list = append(list, map[string]interface{}{"tagid": t.Id, "tagname": t.Name})
+	}
+	return ok(map[string]interface{}{"taglist": list})
+}
+
+func corpMessageSend(r *request) interface{} {
+	var req struct {
+		ToUser  string `json:"touser"`
+		ToParty string `json:"toparty"`
+		ToTag   string `json:"totag"`
+		MsgType string `json:"msgtype"`
+		AgentId int64  `json:"agentid"`
+	}
+	if err := r.decode(&req); err != nil || req.MsgType == "" {
+		return fail(40008, "invalid message type")
+	}
+	if req.ToUser == "@all" {
+		return ok(nil)
+	}
+
+	var invalidUsers, invalidParties, invalidTags []string
+	valid := 0
+	for _, userId := range splitList(req.ToUser) {
+		if _, found := r.s.users[userId]; found {
+			valid++
+		} else {
+			invalidUsers = append(invalidUsers, userId)
+		}
+	}
+	for _, id := range splitList(req.ToParty) {
+		partyId, _ := strconv.ParseInt(id, 10, 64)
+		if _, found := r.s.parties[partyId]; found {
+			valid++
+		} else {
+			invalidParties = append(invalidParties, id)
+		}
+	}
+	for _, id := range splitList(req.ToTag) {
+		tagId, _ := strconv.ParseInt(id, 10, 64)
+		if _, found := r.s.qyTags[tagId]; found {
+			valid++
+		} else {
+			invalidTags = append(invalidTags, id)
+		}
+	}
+	if valid == 0 {
+		return fail(82001, "all touser/toparty/totag invalid")
+	}
+	resp := ok(nil)
+	if invalidUsers != nil {
+		resp["invaliduser"] = strings.Join(invalidUsers, "|")
+	}
+	if invalidParties != nil {
+		resp["invalidparty"] = strings.Join(invalidParties, "|")
+	}
+	if invalidTags != nil {
+		resp["invalidtag"] = strings.Join(invalidTags, "|")
+	}
+	return resp
+}
+
+func corpMediaUpload(r *request) interface{} {
+	mediaType := r.query.Get("type")
+	switch mediaType {
+	case "image", "voice", "video", "file":
+	default:
+		return fail(40004, "invalid media type")
+	}
+	mediaId, m, resp := r.upload(mediaType, false)
+	if resp != nil {
+		return resp
+	}
+	return ok(map[string]interface{}{"type": mediaType, "media_id": mediaId, "created_at": m.CreatedAt})
+}
+
+// 解析 JSON 数字(float64), json.Number, int, int64 为 int
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return int(n)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func splitList(s string) (list []string) {
+	for _, item := range strings.Split(s, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
@@ -0,0 +1,894 @@
+package wechattest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 创建模拟公众号接口的 Server, 对应 mp.Client.
+//  appid 为 "wx_test_appid", appsecret 为 "wx_test_appsecret".
+func NewServer() *Server {
+	return newServer("wx_test_appid", "wx_test_appsecret", mpRoutes())
+}
+
+func mpRoutes() map[string]route {
+	routes := map[string]route{
+		// 基础支持
+		"/cgi-bin/token":            {method: "GET", noAuth: true, fn: mpToken},
+		"/cgi-bin/ticket/getticket": {method: "GET", fn: func(r *request) interface{} { return r.s.newTicket() }},
+		"/cgi-bin/qrcode/create":    {method: "POST", fn: mpQRCodeCreate},
+		"/cgi-bin/showqrcode":       {method: "GET", noAuth: true, fn: mpShowQRCode}, // mp.weixin.qq.com, 见 mp.Client.SetMPBaseURL
+		"/cgi-bin/shorturl":         {method: "POST", fn: mpShortURL},
+
+		// 自定义菜单
+		"/cgi-bin/menu/create": {method: "POST", fn: menuCreate},
+		"/cgi-bin/menu/get":    {method: "GET", fn: menuGet},
+		"/cgi-bin/menu/delete": {method: "GET", fn: menuDelete},
+
+		// 用户管理
+		"/cgi-bin/user/info":              {method: "GET", fn: mpUserInfo},
+		"/cgi-bin/user/get":               {method: "GET", fn: mpUserList},
+		"/cgi-bin/user/info/updateremark": {method: "POST", fn: mpUserUpdateRemark},
+
+		// 用户标签
+		"/cgi-bin/tags/create":                 {method: "POST", fn: mpTagCreate},
+		"/cgi-bin/tags/get":                    {method: "GET", fn: mpTagList},
+		"/cgi-bin/tags/update":                 {method: "POST", fn: mpTagUpdate},
+		"/cgi-bin/tags/delete":                 {method: "POST", fn: mpTagDelete},
+		"/cgi-bin/tags/members/batchtagging":   {method: "POST", fn: mpTagBatchTagging},
+		"/cgi-bin/tags/members/batchuntagging": {method: "POST", fn: mpTagBatchUntagging},
+		"/cgi-bin/tags/getidlist":              {method: "POST", fn: mpTagIdList},
+		"/cgi-bin/user/tag/get":                {method: "POST", fn: mpTagUserList},
+
+		// 素材管理
+		"/cgi-bin/media/upload":                  {method: "POST", fn: mpMediaUpload},
+		"/cgi-bin/media/get":                     {method: "GET", fn: func(r *request) interface{} { return r.download(r.query.Get("media_id")) }},
+		"/cgi-bin/media/uploadnews":              {method: "POST", fn: mpMediaUploadNews},
+		"/cgi-bin/media/uploadvideo":             {method: "POST", fn: mpMediaUploadVideo},
+		"/cgi-bin/material/add_material":         {method: "POST", fn: mpMaterialAdd},
+		"/cgi-bin/material/add_news":             {method: "POST", fn: mpMaterialAddNews},
+		"/cgi-bin/material/get_material":         {method: "POST", fn: mpMaterialGet},
+		"/cgi-bin/material/del_material":         {method: "POST", fn: mpMaterialDelete},
+		"/cgi-bin/material/get_materialcount":    {method: "GET", fn: mpMaterialCount},
+		"/cgi-bin/material/batchget_material":    {method: "POST", fn: mpMaterialBatchGet},
+		"/cgi-bin/material/update_news":          {method: "POST", fn: mpMaterialUpdateNews},
+		"/cgi-bin/template/api_set_industry":     {method: "POST", fn: mpTemplateSetIndustry},
+		"/cgi-bin/template/api_add_template":     {method: "POST", fn: mpTemplateAdd},
+		"/cgi-bin/message/template/send":         {method: "POST", fn: mpTemplateSend},
+		"/cgi-bin/message/mass/sendall":          {method: "POST", fn: mpMassSend},
+		"/cgi-bin/message/mass/send":             {method: "POST", fn: mpMassSend},
+		"/cgi-bin/message/mass/preview":          {method: "POST", fn: mpMassPreview},
+		"/cgi-bin/message/mass/delete":           {method: "POST", fn: mpMassDelete},
+		"/cgi-bin/message/custom/send":           {method: "POST", fn: mpCustomSend},
+		"/cgi-bin/message/custom/typing":         {method: "POST", fn: mpCustomTyping},
+		"/cgi-bin/customservice/getkflist":       {method: "GET", fn: mpKfList},
+		"/cgi-bin/customservice/getonlinekflist": {method: "GET", fn: mpKfOnlineList},
+		"/customservice/kfaccount/add":           {method: "POST", fn: mpKfAdd},
+		"/customservice/kfaccount/update":        {method: "POST", fn: mpKfUpdate},
+		"/customservice/kfaccount/del":           {method: "GET", fn: mpKfDelete},
+
+		// 模板消息
+		"/cgi-bin/template/get_industry":             {method: "GET", fn: mpTemplateGetIndustry},
+		"/cgi-bin/template/get_all_private_template": {method: "GET", fn: mpTemplateList},
+		"/cgi-bin/template/del_private_template":     {method: "POST", fn: mpTemplateDelete},
+		"/cgi-bin/message/template/subscribe":        {method: "POST", fn: mpTemplateSubscribe},
+
+		// 卡券
+		"/card/create":   {method: "POST", fn: mpCardCreate},
+		"/card/get":      {method: "POST", fn: mpCardGet},
+		"/card/update":   {method: "POST", fn: mpCardUpdate},
+		"/card/delete":   {method: "POST", fn: mpCardDelete},
+		"/card/batchget": {method: "POST", fn: mpCardBatchGet},
+	}
+
+	// 数据统计接口都返回 {"list": [...]}
+	for _, name := range []string{
+		"getusersummary", "getusercumulate",
+		"getarticlesummary", "getarticletotal", "getuserread", "getuserreadhour", "getusershare", "getusersharehour",
+		"getupstreammsg", "getupstreammsghour", "getupstreammsgweek", "getupstreammsgmonth",
+		"getupstreammsgdist", "getupstreammsgdistweek", "getupstreammsgdistmonth",
+		"getinterfacesummary", "getinterfacesummaryhour",
+	} {
+		routes["/datacube/"+name] = route{method: "POST", fn: datacube}
+	}
+	return routes
+}
+
+func mpToken(r *request) interface{} {
+	if r.query.Get("grant_type") != "client_credential" {
+		return fail(40002, "invalid grant_type")
+	}
+	if r.query.Get("appid") != r.s.AppId {
+		return fail(40013, "invalid appid")
+	}
+	if r.query.Get("secret") != r.s.AppSecret {
+		return fail(40001, "invalid credential, access_token is invalid or not latest")
+	}
+	return r.s.newToken()
+}
+
+func mpQRCodeCreate(r *request) interface{} {
+	var req struct {
+		ExpireSeconds int `json:"expire_seconds"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	ticket := "QRCODE_TICKET_" + strconv.FormatInt(r.s.newId(), 10)
+	r.s.qrcodes[ticket] = json.RawMessage(r.body)
+	resp := ok(map[string]interface{}{
+		"ticket": ticket,
+		"url":    "http://weixin.qq.com/q/" + ticket,
+	})
+	if req.ExpireSeconds > 0 {
+		resp["expire_seconds"] = req.ExpireSeconds
+	}
+	return resp
+}
+
+// 返回的图片内容为 ticket, 不是真正的二维码.
+func mpShowQRCode(r *request) interface{} {
+	ticket := r.query.Get("ticket")
+	if _, ok := r.s.qrcodes[ticket]; !ok {
+		return rawResponse(func(w http.ResponseWriter) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		})
+	}
+	return rawResponse(func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "image/jpg")
+		w.Write([]byte(ticket))
+	})
+}
+
+func mpShortURL(r *request) interface{} {
+	var req struct {
+		LongURL string `json:"long_url"`
+	}
+	if err := r.decode(&req); err != nil || req.LongURL == "" {
+		return fail(40039, "invalid url size")
+	}
+	return ok(map[string]interface{}{"short_url": "http://w.url.cn/s/" + strconv.FormatInt(r.s.newId(), 36)})
+}
+
+// 公众号和企业号的菜单接口相同, 企业号多了 agentid 参数
+func menuCreate(r *request) interface{} {
+	var menu struct {
+		Buttons []json.RawMessage `json:"button"`
+	}
+	if err := r.decode(&menu); err != nil {
+		return fail(47001, "data format error")
+	}
+	if len(menu.Buttons) == 0 || len(menu.Buttons) > 3 {
+		return fail(40016, "invalid button size")
+	}
+	r.s.menus[r.query.Get("agentid")] = json.RawMessage(r.body)
+	return ok(nil)
+}
+
+func menuGet(r *request) interface{} {
+	menu, found := r.s.menus[r.query.Get("agentid")]
+	if !found {
+		return fail(46003, "menu no exist")
+	}
+	return ok(map[string]interface{}{"menu": menu})
+}
+
+func menuDelete(r *request) interface{} {
+	delete(r.s.menus, r.query.Get("agentid"))
+	return ok(nil)
+}
+
+func mpUserInfo(r *request) interface{} {
+	user, found := r.s.users[r.query.Get("openid")]
+	if !found {
+		return fail(40003, "invalid openid")
+	}
+	resp := ok(user)
+	if tagIds := r.s.userTags[r.query.Get("openid")]; tagIds != nil {
+		resp["tagid_list"] = tagIds
+	} else {
+		resp["tagid_list"] = []int64{}
+	}
+	return resp
+}
+
+const mpUserListLimit = 10000
+
+func mpUserList(r *request) interface{} {
+	ids := r.s.userIds()
+	begin := 0
+	if next := r.query.Get("next_openid"); next != "" {
+		for i, id := range ids {
+			if id == next {
+				begin = i + 1
+				break
+			}
+		}
+	}
+	if begin > len(ids) {
+		begin = len(ids)
+	}
+	end := begin + mpUserListLimit
+	if end > len(ids) {
+		end = len(ids)
+	}
+	page := ids[begin:end]
+
+	nextOpenId := ""
+	if len(page) > 0 && end < len(ids) {
+		nextOpenId = page[len(page)-1]
+	}
+	return map[string]interface{}{
+		"total":       len(ids),
+		"count":       len(page),
+		"data":        map[string]interface{}{"openid": page},
+		"next_openid": nextOpenId,
+	}
+}
+
+func mpUserUpdateRemark(r *request) interface{} {
+	var req struct {
+		OpenId string `json:"openid"`
+		Remark string `json:"remark"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	user, found := r.s.users[req.OpenId]
+	if !found {
+		return fail(40003, "invalid openid")
+	}
+	user["remark"] = req.Remark
+	return ok(nil)
+}
+
+func mpTagCreate(r *request) interface{} {
+	var req struct {
+		Tag struct {
+			Name string `json:"name"`
+		} `json:"tag"`
+	}
+	if err := r.decode(&req); err != nil || req.Tag.Name == "" {
+		return fail(47001, "data format error")
+	}
+	for _, t := range r.s.tags {
+		if t.Name == req.Tag.Name {
+			return fail(45157, "tag name duplicated")
+		}
+	}
+	t := &tag{Id: r.s.newId(), Name: req.Tag.Name}
+	r.s.tags[t.Id] = t
+	return map[string]interface{}{"tag": map[string]interface{}{"id": t.Id, "name": t.Name}}
+}
+
+func mpTagList(r *request) interface{} {
+	tags := make([]map[string]interface{}, 0, len(r.s.tags))
+	for _, t := range sortedTags(r.s.tags) {
+		tags = append(tags, map[string]interface{}{"id": t.Id, "name": t.Name, "count": len(t.Users)})
+	}
+	return map[string]interface{}{"tags": tags}
+}
+
+func mpTagUpdate(r *request) interface{} {
+	var req struct {
+		Tag struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"tag"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.tags[req.Tag.Id]
+	if !found {
+		return fail(45058, "tag not exist")
+	}
+	t.Name = req.Tag.Name
+	return ok(nil)
+}
+
+func mpTagDelete(r *request) interface{} {
+	var req struct {
+		Id int64 `json:"tagid"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.tags[req.Id]; !found {
+		return fail(45058, "tag not exist")
+	}
+	delete(r.s.tags, req.Id)
+	for openId, tagIds := range r.s.userTags {
+		r.s.userTags[openId] = removeInt64(tagIds, req.Id)
+	}
+	return ok(nil)
+}
+
+type tagMembersRequest struct {
+	OpenIdList []string `json:"openid_list"`
+	TagId      int64    `json:"tagid"`
+}
+
+func mpTagBatchTagging(r *request) interface{} {
+	var req tagMembersRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.tags[req.TagId]
+	if !found {
+		return fail(45058, "tag not exist")
+	}
+	for _, openId := range req.OpenIdList {
+		if _, found := r.s.users[openId]; !found {
+			return fail(40003, "invalid openid")
+		}
+	}
+	for _, openId := range req.OpenIdList {
+		if !containsInt64(r.s.userTags[openId], t.Id) {
+			r.s.userTags[openId] = append(r.s.userTags[openId], t.Id)
+			t.Users = append(t.Users, openId)
+		}
+	}
+	return ok(nil)
+}
+
+func mpTagBatchUntagging(r *request) interface{} {
+	var req tagMembersRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.tags[req.TagId]
+	if !found {
+		return fail(45058, "tag not exist")
+	}
+	for _, openId := range req.OpenIdList {
+		r.s.userTags[openId] = removeInt64(r.s.userTags[openId], t.Id)
+		t.Users = removeString(t.Users, openId)
+	}
+	return ok(nil)
+}
+
+func mpTagIdList(r *request) interface{} {
+	var req struct {
+		OpenId string `json:"openid"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.users[req.OpenId]; !found {
+		return fail(40003, "invalid openid")
+	}
+	tagIds := r.s.userTags[req.OpenId]
+	if tagIds == nil {
+		tagIds = []int64{}
+	}
+	return ok(map[string]interface{}{"tagid_list": tagIds})
+}
+
+func mpTagUserList(r *request) interface{} {
+	var req struct {
+		TagId      int64  `json:"tagid"`
+		NextOpenId string `json:"next_openid"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	t, found := r.s.tags[req.TagId]
+	if !found {
+		return fail(45058, "tag not exist")
+	}
+	begin := 0
+	if req.NextOpenId != "" {
+		for i, openId := range t.Users {
+			if openId == req.NextOpenId {
+				begin = i + 1
+				break
+			}
+		}
+	}
+	users := append([]string{}, t.Users[begin:]...)
+	return map[string]interface{}{
+		"count":       len(users),
+		"data":        map[string]interface{}{"openid": users},
+		"next_openid": "",
+	}
+}
+
+func mpMediaUpload(r *request) interface{} {
+	mediaType := r.query.Get("type")
+	switch mediaType {
+	case "image", "voice", "video", "thumb":
+	default:
+		return fail(40004, "invalid media type")
+	}
+	mediaId, m, resp := r.upload(mediaType, false)
+	if resp != nil {
+		return resp
+	}
+	if mediaType == "thumb" {
+		return map[string]interface{}{"type": mediaType, "thumb_media_id": mediaId, "created_at": m.CreatedAt}
+	}
+	return map[string]interface{}{"type": mediaType, "media_id": mediaId, "created_at": m.CreatedAt}
+}
+
+func mpMediaUploadNews(r *request) interface{} {
+	var req struct {
+		Articles []json.RawMessage `json:"articles"`
+	}
+	if err := r.decode(&req); err != nil || len(req.Articles) == 0 {
+		return fail(44003, "empty news data")
+	}
+	mediaId := r.s.newMediaId("news")
+	r.s.news[mediaId] = json.RawMessage(r.body)
+	return map[string]interface{}{"type": "news", "media_id": mediaId, "created_at": now()}
+}
+
+func mpMediaUploadVideo(r *request) interface{} {
+	var req struct {
+		MediaId string `json:"media_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.media[req.MediaId]; !found {
+		return fail(40007, "invalid media_id")
+	}
+	return map[string]interface{}{"type": "video", "media_id": r.s.newMediaId("video"), "created_at": now()}
+}
+
+func mpMaterialAdd(r *request) interface{} {
+	mediaType := r.query.Get("type")
+	switch mediaType {
+	case "image", "voice", "video", "thumb":
+	default:
+		return fail(40004, "invalid media type")
+	}
+	mediaId, _, resp := r.upload(mediaType, true)
+	if resp != nil {
+		return resp
+	}
+	result := map[string]interface{}{"media_id": mediaId}
+	if mediaType == "image" {
+		result["url"] = "http://mmbiz.qpic.cn/mmbiz/" + mediaId
+	}
+	return result
+}
+
+func mpMaterialAddNews(r *request) interface{} {
+	var req struct {
+		Articles []json.RawMessage `json:"articles"`
+	}
+	if err := r.decode(&req); err != nil || len(req.Articles) == 0 {
+		return fail(44003, "empty news data")
+	}
+	mediaId := r.s.newMediaId("news")
+	r.s.news[mediaId] = json.RawMessage(r.body)
+	r.s.media[mediaId] = &media{Type: "news", CreatedAt: now(), Permanent: true}
+	return map[string]interface{}{"media_id": mediaId}
+}
+
+func mpMaterialUpdateNews(r *request) interface{} {
+	var req struct {
+		MediaId string `json:"media_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.news[req.MediaId]; !found {
+		return fail(40007, "invalid media_id")
+	}
+	return ok(nil)
+}
+
+func mpMaterialGet(r *request) interface{} {
+	var req struct {
+		MediaId string `json:"media_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if news, found := r.s.news[req.MediaId]; found {
+		var result struct {
+			Articles json.RawMessage `json:"articles"`
+		}
+		json.Unmarshal(news, &result)
+		return map[string]interface{}{"news_item": result.Articles}
+	}
+	m, found := r.s.media[req.MediaId]
+	if !found || !m.Permanent {
+		return fail(40007, "invalid media_id")
+	}
+	if m.Type == "video" {
+		return map[string]interface{}{"title": m.Filename, "description": "", "down_url": "http://example.com/" + req.MediaId}
+	}
+	return r.download(req.MediaId)
+}
+
+func mpMaterialDelete(r *request) interface{} {
+	var req struct {
+		MediaId string `json:"media_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	m, found := r.s.media[req.MediaId]
+	if !found || !m.Permanent {
+		return fail(40007, "invalid media_id")
+	}
+	delete(r.s.media, req.MediaId)
+	delete(r.s.news, req.MediaId)
+	return ok(nil)
+}
+
+func mpMaterialCount(r *request) interface{} {
+	counts := map[string]int{}
+	for _, m := range r.s.media {
+		if m.Permanent {
+			counts[m.Type]++
+		}
+	}
+	return map[string]interface{}{
+		"voice_count": counts["voice"],
+		"video_count": counts["video"],
+		"image_count": counts["image"],
+		"news_count":  counts["news"],
+	}
+}
+
+func mpMaterialBatchGet(r *request) interface{} {
+	var req struct {
+		Type   string `json:"type"`
+		Offset int    `json:"offset"`
+		Count  int    `json:"count"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	var ids []string
+	for id, m := range r.s.media {
+		if m.Permanent && m.Type == req.Type {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	total := len(ids)
+	if req.Offset > len(ids) {
+		req.Offset = len(ids)
+	}
+	ids = ids[req.Offset:]
+	if req.Count > 0 && req.Count < len(ids) {
+		ids = ids[:req.Count]
+	}
+	items := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		m := r.s.media[id]
+		items = append(items, map[string]interface{}{"media_id": id, "name": m.Filename, "update_time": m.CreatedAt})
+	}
+	return map[string]interface{}{"total_count": total, "item_count": len(items), "item": items}
+}
+
+func mpTemplateSetIndustry(r *request) interface{} {
+	r.s.industry = json.RawMessage(r.body)
+	return ok(nil)
+}
+
+func mpTemplateAdd(r *request) interface{} {
+	var req struct {
+		TemplateIdShort string `json:"template_id_short"`
+	}
+	if err := r.decode(&req); err != nil || req.TemplateIdShort == "" {
+		return fail(40037, "invalid template_id")
+	}
+	templateId := "TEMPLATE_" + strconv.FormatInt(r.s.newId(), 10)
+	r.s.templates[templateId] = map[string]interface{}{"template_id": templateId, "title": req.TemplateIdShort, "content": ""}
+	return ok(map[string]interface{}{"template_id": templateId})
+}
+
+// 所属行业: 没有设置时返回空的行业, 否则行业名称为 "industry_" + 行业代码
+func mpTemplateGetIndustry(r *request) interface{} {
+	var req struct {
+		IndustryId1 int64 `json:"industry_id1"`
+		IndustryId2 int64 `json:"industry_id2"`
+	}
+	industry := func(id int64) map[string]interface{} {
+		if id == 0 {
+			return map[string]interface{}{"first_class": "", "second_class": ""}
+		}
+		name := "industry_" + strconv.FormatInt(id, 10)
+		return map[string]interface{}{"first_class": name, "second_class": name}
+	}
+	if r.s.industry != nil {
+		json.Unmarshal(r.s.industry, &req)
+	}
+	return map[string]interface{}{
+		"primary_industry":   industry(req.IndustryId1),
+		"secondary_industry": industry(req.IndustryId2),
+	}
+}
+
+func mpTemplateList(r *request) interface{} {
+	list := make([]map[string]interface{}, 0, len(r.s.templates))
+	for _, id := range r.s.templateIds() {
+		list = append(list, r.s.templates[id])
+	}
+	return map[string]interface{}{"template_list": list}
+}
+
+func mpTemplateDelete(r *request) interface{} {
+	var req struct {
+		TemplateId string `json:"template_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.templates[req.TemplateId]; !found {
+		return fail(40037, "invalid template_id")
+	}
+	delete(r.s.templates, req.TemplateId)
+	return ok(nil)
+}
+
+// 模板消息: 模板需要先通过 api_add_template 添加, 或者以 "TEMPLATE_" 开头
+func mpTemplateSend(r *request) interface{} {
+	var req struct {
+		ToUser     string `json:"touser"`
+		TemplateId string `json:"template_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.templates[req.TemplateId]; !found && !strings.HasPrefix(req.TemplateId, "TEMPLATE_") {
+		return fail(40037, "invalid template_id")
+	}
+	if _, found := r.s.users[req.ToUser]; !found {
+		return fail(40003, "invalid openid")
+	}
+	return ok(map[string]interface{}{"msgid": r.s.newId()})
+}
+
+// 一次性订阅消息: 只校验用户和消息正文
+func mpTemplateSubscribe(r *request) interface{} {
+	var req struct {
+		ToUser     string `json:"touser"`
+		TemplateId string `json:"template_id"`
+		Data       struct {
+			Content *struct {
+				Value string `json:"value"`
+			} `json:"content"`
+		} `json:"data"`
+	}
+	if err := r.decode(&req); err != nil || req.TemplateId == "" || req.Data.Content == nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.users[req.ToUser]; !found {
+		return fail(40003, "invalid openid")
+	}
+	return ok(nil)
+}
+
+func mpMassSend(r *request) interface{} {
+	var req struct {
+		MsgType string `json:"msgtype"`
+	}
+	if err := r.decode(&req); err != nil || req.MsgType == "" {
+		return fail(40008, "invalid message type")
+	}
+	msgId := r.s.newId()
+	r.s.mass[msgId] = json.RawMessage(r.body)
+	return ok(map[string]interface{}{"type": req.MsgType, "msg_id": msgId})
+}
+
+func mpMassPreview(r *request) interface{} {
+	var req struct {
+		ToUser  string `json:"touser"`
+		MsgType string `json:"msgtype"`
+	}
+	if err := r.decode(&req); err != nil || req.MsgType == "" {
+		return fail(40008, "invalid message type")
+	}
+	if _, found := r.s.users[req.ToUser]; !found {
+		return fail(40003, "invalid openid")
+	}
+	return ok(map[string]interface{}{"msg_id": r.s.newId()})
+}
+
+func mpMassDelete(r *request) interface{} {
+	var req struct {
+		MsgId int64 `json:"msg_id"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.mass[req.MsgId]; !found {
+		return fail(40008, "invalid msg_id")
+	}
+	delete(r.s.mass, req.MsgId)
+	return ok(nil)
+}
+
+func mpCustomSend(r *request) interface{} {
+	var req struct {
+		ToUser  string `json:"touser"`
+		MsgType string `json:"msgtype"`
+	}
+	if err := r.decode(&req); err != nil || req.MsgType == "" {
+		return fail(40008, "invalid message type")
+	}
+	if _, found := r.s.users[req.ToUser]; !found {
+		return fail(40003, "invalid openid")
+	}
+	return ok(nil)
+}
+
+func mpCustomTyping(r *request) interface{} {
+	var req struct {
+		ToUser  string `json:"touser"`
+		Command string `json:"command"`
+	}
+	if err := r.decode(&req); err != nil || (req.Command != "Typing" && req.Command != "CancelTyping") {
+		return fail(40001, "invalid command")
+	}
+	if _, found := r.s.users[req.ToUser]; !found {
+		return fail(40003, "invalid openid")
+	}
+	return ok(nil)
+}
+
+func mpKfList(r *request) interface{} {
+	list := make([]map[string]interface{}, 0, len(r.s.kfs))
+	for _, account := range r.s.kfAccounts() {
+		list = append(list, r.s.kfs[account])
+	}
+	return ok(map[string]interface{}{"kf_list": list})
+}
+
+func mpKfOnlineList(r *request) interface{} {
+	list := make([]map[string]interface{}, 0, len(r.s.kfs))
+	for _, account := range r.s.kfAccounts() {
+		kf := r.s.kfs[account]
+		list = append(list, map[string]interface{}{
+			"kf_account":    kf["kf_account"],
+			"kf_id":         kf["kf_id"],
+			"status":        1,
+			"auto_accept":   0,
+			"accepted_case": 0,
+		})
+	}
+	return ok(map[string]interface{}{"kf_online_list": list})
+}
+
+type kfAccountRequest struct {
+	Account  string `json:"kf_account"`
+	Nickname string `json:"nickname"`
+	Password string `json:"password"`
+}
+
+func mpKfAdd(r *request) interface{} {
+	var req kfAccountRequest
+	if err := r.decode(&req); err != nil || !strings.Contains(req.Account, "@") {
+		return fail(65400, "invalid kf_account")
+	}
+	if _, found := r.s.kfs[req.Account]; found {
+		return fail(61450, "kf account exists")
+	}
+	r.s.kfs[req.Account] = map[string]interface{}{
+		"kf_account":    req.Account,
+		"kf_nick":       req.Nickname,
+		"kf_id":         strconv.FormatInt(r.s.newId(), 10),
+		"kf_headimgurl": "",
+	}
+	return ok(nil)
+}
+
+func mpKfUpdate(r *request) interface{} {
+	var req kfAccountRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	kf, found := r.s.kfs[req.Account]
+	if !found {
+		return fail(61451, "invalid kf_account")
+	}
+	if req.Nickname != "" {
+		kf["kf_nick"] = req.Nickname
+	}
+	return ok(nil)
+}
+
+func mpKfDelete(r *request) interface{} {
+	account := r.query.Get("kf_account")
+	if _, found := r.s.kfs[account]; !found {
+		return fail(61451, "invalid kf_account")
+	}
+	delete(r.s.kfs, account)
+	return ok(nil)
+}
+
+func mpCardCreate(r *request) interface{} {
+	var req struct {
+		Card json.RawMessage `json:"card"`
+	}
+	if err := r.decode(&req); err != nil || len(req.Card) == 0 {
+		return fail(47001, "data format error")
+	}
+	cardId := "CARD_" + strconv.FormatInt(r.s.newId(), 10)
+	r.s.cards[cardId] = req.Card
+	return ok(map[string]interface{}{"card_id": cardId})
+}
+
+type cardIdRequest struct {
+	CardId string `json:"card_id"`
+}
+
+func mpCardGet(r *request) interface{} {
+	var req cardIdRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	card, found := r.s.cards[req.CardId]
+	if !found {
+		return fail(40056, "invalid card_id")
+	}
+	return ok(map[string]interface{}{"card": card})
+}
+
+func mpCardUpdate(r *request) interface{} {
+	var req cardIdRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.cards[req.CardId]; !found {
+		return fail(40056, "invalid card_id")
+	}
+	return ok(map[string]interface{}{"send_check": false})
+}
+
+func mpCardDelete(r *request) interface{} {
+	var req cardIdRequest
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	if _, found := r.s.cards[req.CardId]; !found {
+		return fail(40056, "invalid card_id")
+	}
+	delete(r.s.cards, req.CardId)
+	return ok(nil)
+}
+
+func mpCardBatchGet(r *request) interface{} {
+	var req struct {
+		Offset int `json:"offset"`
+		Count  int `json:"count"`
+	}
+	if err := r.decode(&req); err != nil {
+		return fail(47001, "data format error")
+	}
+	ids := r.s.cardIds()
+	total := len(ids)
+	if req.Offset > len(ids) {
+		req.Offset = len(ids)
+	}
+	ids = ids[req.Offset:]
+	if req.Count > 0 && req.Count < len(ids) {
+		ids = ids[:req.Count]
+	}
+	return ok(map[string]interface{}{"card_id_list": ids, "total_num": total})
+}
+
+func datacube(r *request) interface{} {
+	var req struct {
+		BeginDate string `json:"begin_date"`
+		EndDate   string `json:"end_date"`
+	}
+	if err := r.decode(&req); err != nil || req.BeginDate == "" || req.EndDate == "" {
+		return fail(61500, "date format error")
+	}
+	list := r.s.datacube[r.URL.Path]
+	if list == nil {
+		list = []interface{}{}
+	}
+	return map[string]interface{}{"list": list}
+}
@@ -0,0 +1,289 @@
+// 模拟微信服务器的测试桩, 用于离线测试.
+//
+//  NewServer 模拟公众号(api.weixin.qq.com)的接口, NewCorpServer 模拟企业号(qyapi.weixin.qq.com)的接口,
+//  接口的状态(菜单, 用户, 标签, 素材等)保存在内存中. 用法:
+//
+//    srv := wechattest.NewServer()
+//    defer srv.Close()
+//
+//    clt := mp.NewClient(srv.AppId, srv.AppSecret)
+//    clt.SetBaseURL(srv.URL, "")
+//
+//    srv.InjectErrCode("/cgi-bin/message/custom/send", -1, 1) // 下一次调用返回 -1 系统繁忙
+//    ...
+//    calls := srv.CallsTo("/cgi-bin/message/custom/send")     // 断言请求的内容
+package wechattest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const tokenExpiresIn = 7200
+
+// 一次接口调用的记录
+type Call struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Body    []byte
+	ErrCode int // 返回的 errcode
+}
+
+// 把 Body 解析到 v.
+func (c *Call) Decode(v interface{}) error {
+	return json.Unmarshal(c.Body, v)
+}
+
+// 模拟的微信服务器.
+type Server struct {
+	*httptest.Server
+
+	AppId     string // 公众号的 appid, 企业号的 corpid
+	AppSecret string // 公众号的 appsecret, 企业号的 corpsecret
+
+	mu       sync.Mutex
+	routes   map[string]route
+	custom   map[string]http.HandlerFunc
+	injected map[string][]int
+	calls    []Call
+
+	token       string
+	tokens      map[string]bool // 发放过的 access_token
+	ticket      string
+	tokenCalls  int
+	ticketCalls int
+	nextId      int64
+
+	state
+}
+
+// 接口的处理函数, 在持有 s.mu 的情况下调用, 返回的 resp 编码为 JSON 写回.
+type route struct {
+	method string // "" 表示 GET, POST 都可以
+	noAuth bool   // 不需要 access_token
+	fn     func(r *request) (resp interface{})
+}
+
+type request struct {
+	*http.Request
+	s     *Server
+	query url.Values
+	body  []byte
+}
+
+func (r *request) decode(v interface{}) error {
+	return json.Unmarshal(r.body, v)
+}
+
+// 返回 errcode 为 0 的响应, fields 为其他字段.
+func ok(fields map[string]interface{}) map[string]interface{} {
+	resp := map[string]interface{}{"errcode": 0, "errmsg": "ok"}
+	for k, v := range fields {
+		resp[k] = v
+	}
+	return resp
+}
+
+func fail(errCode int, errMsg string) map[string]interface{} {
+	return map[string]interface{}{"errcode": errCode, "errmsg": errMsg}
+}
+
+func newServer(appId, appSecret string, routes map[string]route) *Server {
+	s := &Server{
+		AppId:     appId,
+		AppSecret: appSecret,
+		routes:    routes,
+		custom:    make(map[string]http.HandlerFunc),
+		injected:  make(map[string][]int),
+		tokens:    make(map[string]bool),
+		nextId:    1000,
+	}
+	s.state.init()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// 接下来 times 次调用 path 接口时直接返回 errCode, 比如:
+//  InjectErrCode("/cgi-bin/token", -1, 2) 让接下来的两次获取 access_token 返回系统繁忙.
+//  errCode 为 40001, 42001 等 access_token 相关的错误码时可以测试 access_token 的刷新逻辑.
+func (s *Server) InjectErrCode(path string, errCode int, times int) {
+	s.mu.Lock()
+	for i := 0; i < times; i++ {
+		s.injected[path] = append(s.injected[path], errCode)
+	}
+	s.mu.Unlock()
+}
+
+// 用 handler 处理 path 接口, 替换内置的模拟实现; handler 为 nil 时恢复内置的实现.
+//  handler 的调用同样会被记录, 但是不检查 access_token.
+func (s *Server) Handle(path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	if handler == nil {
+		delete(s.custom, path)
+	} else {
+		s.custom[path] = handler
+	}
+	s.mu.Unlock()
+}
+
+// 所有接口调用的记录, 按调用的顺序.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// path 接口的调用记录.
+func (s *Server) CallsTo(path string) (calls []Call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, call := range s.calls {
+		if call.Path == path {
+			calls = append(calls, call)
+		}
+	}
+	return
+}
+
+// 清空调用记录.
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	s.calls = nil
+	s.mu.Unlock()
+}
+
+// 当前有效的 access_token, 还没有获取过时为 "".
+func (s *Server) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// 让当前的 access_token 失效, 之后用它调用接口返回 42001.
+func (s *Server) ExpireToken() {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+}
+
+// 获取 access_token 和 jsapi_ticket 的次数.
+func (s *Server) TokenCalls() (tokenCalls, ticketCalls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenCalls, s.ticketCalls
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, httpReq *http.Request) {
+	body, err := io.ReadAll(httpReq.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	path := httpReq.URL.Path
+
+	s.mu.Lock()
+	if handler, ok := s.custom[path]; ok {
+		s.calls = append(s.calls, Call{Method: httpReq.Method, Path: path, Query: httpReq.URL.Query(), Body: body})
+		s.mu.Unlock()
+		httpReq.Body = io.NopCloser(bytes.NewReader(body))
+		handler(w, httpReq)
+		return
+	}
+
+	resp, raw := s.handle(httpReq, body)
+	errCode := 0
+	if m, ok := resp.(map[string]interface{}); ok {
+		if code, ok := m["errcode"].(int); ok {
+			errCode = code
+		}
+	}
+	s.calls = append(s.calls, Call{Method: httpReq.Method, Path: path, Query: httpReq.URL.Query(), Body: body, ErrCode: errCode})
+	s.mu.Unlock()
+
+	if raw != nil {
+		raw(w)
+		return
+	}
+	if resp == nil {
+		http.NotFound(w, httpReq)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// 返回 JSON 响应, 或者 raw 不为 nil 时由 raw 写入响应(比如下载多媒体文件); 在持有 s.mu 的情况下调用.
+func (s *Server) handle(httpReq *http.Request, body []byte) (resp interface{}, raw func(w http.ResponseWriter)) {
+	path := httpReq.URL.Path
+	rt, found := s.routes[path]
+	if !found {
+		return
+	}
+	if rt.method != "" && rt.method != httpReq.Method {
+		return fail(43002, "require "+rt.method+" method"), nil
+	}
+
+	if codes := s.injected[path]; len(codes) > 0 {
+		s.injected[path] = codes[1:]
+		return fail(codes[0], "injected error"), nil
+	}
+
+	query := httpReq.URL.Query()
+	if !rt.noAuth {
+		token := query.Get("access_token")
+		switch {
+		case token == "":
+			return fail(41001, "access_token missing"), nil
+		case token != s.token && s.tokens[token]:
+			return fail(42001, "access_token expired"), nil
+		case token != s.token:
+			return fail(40014, "invalid access_token"), nil
+		}
+	}
+
+	r := &request{Request: httpReq, s: s, query: query, body: body}
+	resp = rt.fn(r)
+	if f, ok := resp.(rawResponse); ok {
+		return nil, f
+	}
+	return
+}
+
+// 非 JSON 的响应
+type rawResponse func(w http.ResponseWriter)
+
+// 发放新的 access_token
+func (s *Server) newToken() map[string]interface{} {
+	s.tokenCalls++
+	s.token = "ACCESS_TOKEN_" + strconv.Itoa(s.tokenCalls)
+	s.tokens[s.token] = true
+	return map[string]interface{}{"access_token": s.token, "expires_in": tokenExpiresIn}
+}
+
+func (s *Server) newTicket() map[string]interface{} {
+	s.ticketCalls++
+	s.ticket = "JSAPI_TICKET_" + strconv.Itoa(s.ticketCalls)
+	return ok(map[string]interface{}{"ticket": s.ticket, "expires_in": tokenExpiresIn})
+}
+
+func (s *Server) newId() int64 {
+	s.nextId++
+	return s.nextId
+}
+
+func (s *Server) newMediaId(prefix string) string {
+	return fmt.Sprintf("%s_%d", prefix, s.newId())
+}
+
+func now() int64 {
+	return time.Now().Unix()
+}
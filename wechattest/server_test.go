@@ -0,0 +1,230 @@
+package wechattest_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/skynology/wechat/corp"
+	"github.com/skynology/wechat/mp"
+	"github.com/skynology/wechat/wechattest"
+)
+
+func newMPClient(srv *wechattest.Server) *mp.Client {
+	clt := mp.NewClient(srv.AppId, srv.AppSecret)
+	clt.SetBaseURL(srv.URL, "")
+	return clt
+}
+
+func newCorpClient(srv *wechattest.Server) *corp.Client {
+	clt := corp.NewClient(srv.AppId, srv.AppSecret)
+	clt.SetBaseURL(srv.URL)
+	return clt
+}
+
+func TestTokenRefresh(t *testing.T) {
+	srv := wechattest.NewServer()
+	defer srv.Close()
+	clt := newMPClient(srv)
+
+	if err := clt.CreateMenu(mp.Menu{Buttons: []mp.Button{{Type: "click", Name: "今日歌曲", Key: "V1001"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Menu("") == nil {
+		t.Fatal("menu not created")
+	}
+
+	// access_token 失效后客户端收到 42001, 刷新一次后重试成功
+	srv.ExpireToken()
+	menu, err := clt.GetMenu()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(menu.Buttons) != 1 || menu.Buttons[0].Key != "V1001" {
+		t.Errorf("menu = %+v", menu)
+	}
+	if tokenCalls, _ := srv.TokenCalls(); tokenCalls != 2 {
+		t.Errorf("tokenCalls = %d, want 2", tokenCalls)
+	}
+	calls := srv.CallsTo("/cgi-bin/menu/get")
+	if len(calls) != 2 || calls[0].ErrCode != 42001 || calls[1].ErrCode != 0 {
+		t.Errorf("calls = %+v", calls)
+	}
+
+	if err = clt.DeleteMenu(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clt.GetMenu(); !errors.Is(err, mp.ErrMenuDataNotExist) {
+		t.Errorf("err = %v, want %v", err, mp.ErrMenuDataNotExist)
+	}
+}
+
+func TestInjectErrCode(t *testing.T) {
+	srv := wechattest.NewServer()
+	defer srv.Close()
+	clt := newMPClient(srv)
+
+	srv.InjectErrCode("/cgi-bin/tags/create", mp.ErrCodeSystemBusy, 1)
+	if _, err := clt.CreateTag("星标组"); !errors.Is(err, mp.ErrSystemBusy) {
+		t.Fatalf("err = %v, want %v", err, mp.ErrSystemBusy)
+	}
+	tag, err := clt.CreateTag("星标组")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.SetUser("openid1", map[string]interface{}{"nickname": "Band"})
+	srv.SetUser("openid2", map[string]interface{}{"nickname": "Tom"})
+	if err = clt.UserBatchMoveToTag([]string{"openid1", "openid2"}, tag.Id); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := clt.ListTag()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Name != "星标组" || tags[0].UserCount != 2 {
+		t.Errorf("tags = %+v", tags)
+	}
+	tagIds, err := clt.UserTagIdList("openid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tagIds) != 1 || tagIds[0] != tag.Id {
+		t.Errorf("tagIds = %v", tagIds)
+	}
+
+	info, err := clt.UserInfo("openid2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Nickname != "Tom" || info.OpenId != "openid2" {
+		t.Errorf("info = %+v", info)
+	}
+	if _, err = clt.UserInfo("openid3", ""); err == nil {
+		t.Error("want error for unknown openid")
+	}
+
+	var req struct {
+		OpenIdList []string `json:"openid_list"`
+		TagId      int64    `json:"tagid"`
+	}
+	calls := srv.CallsTo("/cgi-bin/tags/members/batchtagging")
+	if len(calls) != 1 {
+		t.Fatalf("calls = %+v", calls)
+	}
+	if err = calls[0].Decode(&req); err != nil {
+		t.Fatal(err)
+	}
+	if req.TagId != tag.Id || len(req.OpenIdList) != 2 {
+		t.Errorf("request = %+v", req)
+	}
+}
+
+func TestMedia(t *testing.T) {
+	srv := wechattest.NewServer()
+	defer srv.Close()
+	clt := newMPClient(srv)
+
+	info, err := clt.UploadImageFromReader("a.jpg", strings.NewReader("image data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := srv.Media(info.MediaId); !ok || string(data) != "image data" {
+		t.Errorf("media = %q, %v", data, ok)
+	}
+
+	var buf bytes.Buffer
+	if err = clt.DownloadMediaToWriter(info.MediaId, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "image data" {
+		t.Errorf("download = %q", buf.String())
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	srv := wechattest.NewServer()
+	defer srv.Close()
+	clt := newMPClient(srv)
+	clt.SetMPBaseURL(srv.URL)
+
+	qrcode, err := clt.CreateTemporaryQRCode(100, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = clt.QRCodeDownloadToWriter(qrcode.Ticket, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != qrcode.Ticket {
+		t.Errorf("download = %q", buf.String())
+	}
+	if err = clt.QRCodeDownloadToWriter("INVALID_TICKET", &buf); err == nil {
+		t.Error("expected error for invalid ticket")
+	}
+}
+
+func TestCorp(t *testing.T) {
+	srv := wechattest.NewCorpServer()
+	defer srv.Close()
+	clt := newCorpClient(srv)
+
+	id, err := clt.DepartmentCreate(&corp.DepartmentCreateParameters{Name: "研发部", ParentId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = clt.UserCreate(&corp.UserCreateParameters{UserId: "zhangsan", Name: "张三", Department: []int64{id}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = clt.UserCreate(&corp.UserCreateParameters{UserId: "lisi", Name: "李四"}); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := clt.UserSimpleList(id, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Id != "zhangsan" {
+		t.Errorf("users = %+v", users)
+	}
+	if users, err = clt.UserSimpleList(1, true, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Errorf("users = %+v", users)
+	}
+
+	departments, err := clt.DepartmentList(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(departments) != 2 || departments[1].Name != "研发部" {
+		t.Errorf("departments = %+v", departments)
+	}
+	if err = clt.DepartmentDelete(id); err == nil {
+		t.Error("want error deleting department with members")
+	}
+
+	if err = clt.UserDelete("zhangsan"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clt.UserInfo("zhangsan"); err == nil {
+		t.Error("want error for deleted user")
+	}
+	if err = clt.DepartmentDelete(id); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := clt.UploadMediaFromReader("file", "a.txt", strings.NewReader("file data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = clt.DownloadMediaToWriter(info.MediaId, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "file data" {
+		t.Errorf("download = %q", buf.String())
+	}
+}
@@ -0,0 +1,299 @@
+package wechattest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// 保存在内存中的接口状态
+type state struct {
+	menus     map[string]json.RawMessage        // key 为企业号的 agentid, 公众号为 ""
+	users     map[string]map[string]interface{} // key 为 openid 或者企业号的 userid
+	tags      map[int64]*tag                    // 用户标签
+	userTags  map[string][]int64                // openid -> tagid 列表
+	media     map[string]*media                 // 临时素材和永久素材
+	templates map[string]map[string]interface{} // template_id -> get_all_private_template 返回的模板信息
+	industry  json.RawMessage                   // 设置的所属行业
+	mass      map[int64]json.RawMessage         // 群发消息
+	kfs       map[string]map[string]interface{} // 客服账号
+	cards     map[string]json.RawMessage        // 卡券
+	datacube  map[string]interface{}            // 数据统计接口返回的 list
+	parties   map[int64]map[string]interface{}  // 企业号部门
+	qyTags    map[int64]*tag                    // 企业号标签
+	qrcodes   map[string]json.RawMessage        // 二维码 ticket -> 创建二维码的请求
+	news      map[string]json.RawMessage        // 图文消息素材
+}
+
+type tag struct {
+	Id      int64
+	Name    string
+	Users   []string
+	Parties []int64
+}
+
+type media struct {
+	Type      string
+	Filename  string
+	Data      []byte
+	CreatedAt int64
+	Permanent bool
+}
+
+func (st *state) init() {
+	st.menus = make(map[string]json.RawMessage)
+	st.users = make(map[string]map[string]interface{})
+	st.tags = make(map[int64]*tag)
+	st.userTags = make(map[string][]int64)
+	st.media = make(map[string]*media)
+	st.templates = make(map[string]map[string]interface{})
+	st.mass = make(map[int64]json.RawMessage)
+	st.kfs = make(map[string]map[string]interface{})
+	st.cards = make(map[string]json.RawMessage)
+	st.datacube = make(map[string]interface{})
+	st.parties = map[int64]map[string]interface{}{
+		1: {"id": int64(1), "name": "根部门", "parentid": int64(0), "order": int64(1)},
+	}
+	st.qyTags = make(map[int64]*tag)
+	st.qrcodes = make(map[string]json.RawMessage)
+	st.news = make(map[string]json.RawMessage)
+}
+
+// 添加或者替换一个用户, id 为公众号的 openid 或者企业号的 userid, info 为用户信息接口返回的字段.
+func (s *Server) SetUser(id string, info map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setUser(id, info)
+}
+
+func (s *Server) setUser(id string, info map[string]interface{}) {
+	user := make(map[string]interface{}, len(info)+1)
+	for k, v := range info {
+		user[k] = v
+	}
+	if s.isCorp() {
+		user["userid"] = id
+	} else {
+		user["openid"] = id
+		if _, ok := user["subscribe"]; !ok {
+			user["subscribe"] = 1
+		}
+	}
+	s.users[id] = user
+}
+
+// 返回用户信息的拷贝, 不存在时返回 nil.
+func (s *Server) User(id string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil
+	}
+	cp := make(map[string]interface{}, len(user))
+	for k, v := range user {
+		cp[k] = v
+	}
+	return cp
+}
+
+// 排好序的所有用户 id
+func (s *Server) userIds() []string {
+	ids := make([]string, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// 返回当前的自定义菜单(创建菜单时提交的 JSON), 企业号需要指定 agentId, 没有菜单时返回 nil.
+func (s *Server) Menu(agentId string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.menus[agentId]
+}
+
+// 返回上传的多媒体文件的内容.
+func (s *Server) Media(mediaId string) (data []byte, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.media[mediaId]
+	if !ok {
+		return
+	}
+	return m.Data, true
+}
+
+// 添加或者替换一个模板消息的模板, content 为模板内容, 比如 "{{first.DATA}}\n时间: {{time.DATA}}".
+func (s *Server) SetTemplate(templateId, title, content string) {
+	s.mu.Lock()
+	s.templates[templateId] = map[string]interface{}{
+		"template_id":      templateId,
+		"title":            title,
+		"primary_industry": "IT科技",
+		"deputy_industry":  "互联网|电子商务",
+		"content":          content,
+		"example":          "",
+	}
+	s.mu.Unlock()
+}
+
+// 排好序的所有模板 id
+func (s *Server) templateIds() []string {
+	ids := make([]string, 0, len(s.templates))
+	for id := range s.templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// 设置数据统计接口(比如 "/datacube/getusersummary")返回的 list, 默认返回空的 list.
+func (s *Server) SetDatacube(path string, list interface{}) {
+	s.mu.Lock()
+	s.datacube[path] = list
+	s.mu.Unlock()
+}
+
+func (s *Server) isCorp() bool {
+	_, ok := s.routes["/cgi-bin/gettoken"]
+	return ok
+}
+
+// 解析 multipart/form-data 里名为 name 的文件.
+//  NOTE: corp.Client 的 boundary 含有 '?', '(' 等字符却没有加引号, mime.ParseMediaType 会失败,
+//  微信服务器能接受这样的请求, 所以这里直接截取 "boundary=" 之后的内容.
+func (r *request) formFile(name string) (filename string, data []byte, err error) {
+	contentType := r.Header.Get("Content-Type")
+	i := strings.Index(contentType, "boundary=")
+	if i < 0 {
+		err = errors.New("no multipart boundary")
+		return
+	}
+	boundary := strings.Trim(contentType[i+len("boundary="):], `"`)
+
+	mr := multipart.NewReader(bytes.NewReader(r.body), boundary)
+	for {
+		var part *multipart.Part
+		if part, err = mr.NextPart(); err != nil {
+			return
+		}
+		if part.FormName() != name {
+			continue
+		}
+		if data, err = io.ReadAll(part); err != nil {
+			return
+		}
+		filename = part.FileName()
+		return
+	}
+}
+
+// 上传多媒体文件
+func (r *request) upload(mediaType string, permanent bool) (mediaId string, m *media, resp interface{}) {
+	filename, data, err := r.formFile("media")
+	if err != nil {
+		filename, data, err = r.formFile("file") // corp.Client 使用的字段名
+	}
+	if err != nil {
+		resp = fail(41005, "media data missing")
+		return
+	}
+	mediaId = r.s.newMediaId(mediaType)
+	m = &media{Type: mediaType, Filename: filename, Data: data, CreatedAt: now(), Permanent: permanent}
+	r.s.media[mediaId] = m
+	return
+}
+
+// 下载多媒体文件
+func (r *request) download(mediaId string) interface{} {
+	m, ok := r.s.media[mediaId]
+	if !ok {
+		return fail(40007, "invalid media_id")
+	}
+	data := m.Data
+	filename := m.Filename
+	return rawResponse(func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.Write(data)
+	})
+}
+
+// 排好序的所有客服账号
+func (st *state) kfAccounts() []string {
+	accounts := make([]string, 0, len(st.kfs))
+	for account := range st.kfs {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	return accounts
+}
+
+// 排好序的所有卡券 id
+func (st *state) cardIds() []string {
+	ids := make([]string, 0, len(st.cards))
+	for id := range st.cards {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// 按 id 排序的标签
+func sortedTags(tags map[int64]*tag) []*tag {
+	list := make([]*tag, 0, len(tags))
+	for _, t := range tags {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	return list
+}
+
+func sortInt64s(a []int64) {
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+}
+
+func containsInt64(a []int64, x int64) bool {
+	for _, v := range a {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+func removeInt64(a []int64, x int64) []int64 {
+	b := a[:0]
+	for _, v := range a {
+		if v != x {
+			b = append(b, v)
+		}
+	}
+	return b
+}
+
+func containsString(a []string, x string) bool {
+	for _, v := range a {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(a []string, x string) []string {
+	b := a[:0]
+	for _, v := range a {
+		if v != x {
+			b = append(b, v)
+		}
+	}
+	return b
+}